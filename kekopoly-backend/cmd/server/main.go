@@ -2,16 +2,20 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/kekopoly/backend/internal/api"
 	"github.com/kekopoly/backend/internal/config"
 	"github.com/kekopoly/backend/internal/db/mongodb"
 	"github.com/kekopoly/backend/internal/db/redis"
+	"github.com/kekopoly/backend/internal/game/board"
 	"github.com/kekopoly/backend/internal/game/manager"
+	"github.com/kekopoly/backend/internal/game/rules"
 	"github.com/kekopoly/backend/internal/game/websocket"
 	"github.com/kekopoly/backend/internal/queue"
 	"go.uber.org/zap"
@@ -73,6 +77,28 @@ func main() {
 	gameManager := manager.NewGameManager(ctx, mongoClient, redisClient, sugar, hub, redisQueue)
 	sugar.Info("Game manager initialized")
 
+	// Install the board packs available to new games
+	boards := board.NewRegistry()
+	if cfg.Game.BoardPacksDir != "" {
+		loaded, err := boards.LoadDir(cfg.Game.BoardPacksDir)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			sugar.Infof("Board pack directory %s not found, using the default board only", cfg.Game.BoardPacksDir)
+		case err != nil:
+			sugar.Warnf("Failed to load some board packs from %s: %v", cfg.Game.BoardPacksDir, err)
+		}
+		sugar.Infof("Loaded %d board packs from %s", loaded, cfg.Game.BoardPacksDir)
+	}
+	gameManager.SetBoardRegistry(boards)
+	gameManager.SetMarketSettings(rules.MarketSettings{
+		BullChance:  cfg.Game.MarketBullChance,
+		CrashChance: cfg.Game.MarketCrashChance,
+		BullRounds:  cfg.Game.MarketBullRounds,
+		CrashRounds: cfg.Game.MarketCrashRounds,
+	})
+	gameManager.SetTurnTimeout(time.Duration(cfg.Game.TurnTimeout) * time.Second)
+	gameManager.SetDisconnectionTimeout(time.Duration(cfg.Game.DisconnectionTimeout) * time.Second)
+
 	// Set the game manager in the hub
 	hub.SetGameManager(gameManager)
 	sugar.Info("Game manager set in WebSocket hub")
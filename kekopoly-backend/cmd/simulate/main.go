@@ -0,0 +1,114 @@
+// Command simulate plays batches of bot-vs-bot games in memory with the server's rules
+// and reports how the board and cards played out, as JSON or CSV.
+//
+//	go run ./cmd/simulate -games 5000 -seats balanced,balanced,aggressive,cautious -format csv > run.csv
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kekopoly/backend/internal/game/board"
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/simulation"
+)
+
+func main() {
+	games := flag.Int("games", 1000, "number of games to play")
+	seed := flag.Int64("seed", 1, "seed of the first game; game n uses seed+n")
+	seats := flag.String("seats", "balanced,balanced,balanced,balanced", "bot difficulty in each seat, in turn order (cautious, balanced or aggressive)")
+	boardID := flag.String("board", "", "board pack to play on; empty selects the default board")
+	boardsDir := flag.String("boards", "", "directory of extra board packs to load")
+	cardSet := flag.String("cards", "all", "cards in the decks: all, none, or a comma-separated list of card IDs")
+	rounds := flag.Int("rounds", 0, "end each game by net worth after this many rounds; 0 plays until one player is left")
+	format := flag.String("format", "json", "report format: json or csv")
+	outPath := flag.String("out", "", "file to write the report to; empty writes to stdout")
+	flag.Parse()
+
+	cfg := simulation.Config{
+		Games:      *games,
+		Seed:       *seed,
+		Seats:      parseSeats(*seats),
+		Cards:      parseCards(*cardSet),
+		RoundLimit: *rounds,
+	}
+
+	boards := board.NewRegistry()
+	if *boardsDir != "" {
+		if _, err := boards.LoadDir(*boardsDir); err != nil {
+			fail("Failed to load board packs: %v", err)
+		}
+	}
+	b, err := boards.Get(*boardID)
+	if err != nil {
+		fail("%v", err)
+	}
+	cfg.Board = b
+
+	if *format != "json" && *format != "csv" {
+		fail("Unknown format %q: use json or csv", *format)
+	}
+
+	report, err := simulation.Run(cfg)
+	if err != nil {
+		fail("Simulation failed: %v", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			fail("Failed to create %s: %v", *outPath, err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if *format == "csv" {
+		err = report.WriteCSV(out)
+	} else {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	}
+	if err != nil {
+		fail("Failed to write report: %v", err)
+	}
+}
+
+// parseSeats reads the comma-separated difficulties of the seats
+func parseSeats(value string) []models.BotDifficulty {
+	var seats []models.BotDifficulty
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			seats = append(seats, models.BotDifficulty(strings.ToUpper(name)))
+		}
+	}
+	return seats
+}
+
+// parseCards reads the card set: nil for every card, or the listed card IDs
+func parseCards(value string) []string {
+	switch value {
+	case "", "all":
+		return nil
+	case "none":
+		return []string{}
+	}
+	var ids []string
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
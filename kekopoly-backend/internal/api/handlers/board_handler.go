@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/kekopoly/backend/internal/game/manager"
+)
+
+// BoardHandler serves the board packs installed on the server
+type BoardHandler struct {
+	gameManager *manager.GameManager
+	logger      *zap.SugaredLogger
+}
+
+// NewBoardHandler creates a new BoardHandler
+func NewBoardHandler(gameManager *manager.GameManager, logger *zap.SugaredLogger) *BoardHandler {
+	return &BoardHandler{
+		gameManager: gameManager,
+		logger:      logger,
+	}
+}
+
+// GetBoard returns the spaces and property catalog of the default board, or of the
+// board pack named by the boardId path parameter
+func (h *BoardHandler) GetBoard(c echo.Context) error {
+	b, err := h.gameManager.Boards().Get(c.Param("boardId"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return c.JSON(http.StatusOK, b)
+}
+
+// ListBoards lists the installed board packs
+func (h *BoardHandler) ListBoards(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"boards": h.gameManager.Boards().List(),
+	})
+}
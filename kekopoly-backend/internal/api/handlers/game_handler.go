@@ -1,16 +1,23 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 
+	"github.com/kekopoly/backend/internal/game/board"
+	"github.com/kekopoly/backend/internal/game/history"
 	"github.com/kekopoly/backend/internal/game/manager"
 	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/replay"
+	"github.com/kekopoly/backend/internal/game/rules"
 	"github.com/kekopoly/backend/internal/game/utils"
 	"github.com/kekopoly/backend/internal/game/websocket"
 )
@@ -35,6 +42,11 @@ func NewGameHandler(gameManager *manager.GameManager, wsHub *websocket.Hub, logg
 type CreateGameRequest struct {
 	GameName   string `json:"gameName" validate:"required"`
 	MaxPlayers int    `json:"maxPlayers,omitempty"`
+	BoardID    string `json:"boardId,omitempty"` // Board pack to play on; defaults to the standard board
+	// How the game ends: CLASSIC (the default), TIMED or ROUND_CAPPED
+	Mode             models.GameMode `json:"mode,omitempty"`
+	TimeLimitMinutes int             `json:"timeLimitMinutes,omitempty"` // Length of a TIMED game; defaults to 30 minutes
+	RoundLimit       int             `json:"roundLimit,omitempty"`       // Rounds in a ROUND_CAPPED game; defaults to 20
 }
 
 // JoinGameRequest represents a join game request
@@ -42,12 +54,30 @@ type JoinGameRequest struct {
 	// No wallet address needed
 }
 
+// AddBotRequest represents a request to add an AI opponent to a lobby game
+type AddBotRequest struct {
+	Difficulty models.BotDifficulty `json:"difficulty,omitempty"` // CAUTIOUS, BALANCED (the default) or AGGRESSIVE
+}
+
 // ActionRequest represents a game action request
 type ActionRequest struct {
 	PlayerID string      `json:"playerId" validate:"required"`
 	Payload  interface{} `json:"payload"`
 }
 
+// GameStateResponse is the game state along with status effects derived from it
+type GameStateResponse struct {
+	*models.Game
+	ActiveShadowbans []rules.Shadowban `json:"activeShadowbans"`
+}
+
+// GameEventsResponse is a page of a game's history
+type GameEventsResponse struct {
+	GameID string          `json:"gameId"`
+	Since  int             `json:"since"`
+	Events []history.Event `json:"events"`
+}
+
 // CreateGame creates a new game
 func (h *GameHandler) CreateGame(c echo.Context) error {
 	var req CreateGameRequest
@@ -66,7 +96,15 @@ func (h *GameHandler) CreateGame(c echo.Context) error {
 	if maxPlayers == 0 {
 		maxPlayers = 6 // Default max players if not specified
 	}
-	gameID, err := h.gameManager.CreateGame(userID, req.GameName, maxPlayers)
+	gameID, err := h.gameManager.CreateGame(userID, req.GameName, maxPlayers, manager.GameOptions{
+		BoardID:          req.BoardID,
+		Mode:             req.Mode,
+		TimeLimitMinutes: req.TimeLimitMinutes,
+		RoundLimit:       req.RoundLimit,
+	})
+	if errors.Is(err, board.ErrUnknownBoard) || errors.Is(err, rules.ErrInvalidGameMode) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
 	if err != nil {
 		h.logger.Errorf("Failed to create game: %v", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create game")
@@ -83,10 +121,15 @@ func (h *GameHandler) CreateGame(c echo.Context) error {
 	go h.broadcastNewGame(gameID)
 
 	return c.JSON(http.StatusCreated, map[string]interface{}{
-		"gameId": gameID,
-		"code":   game.Code,
-		"name":   game.Name,
-		"status": string(game.Status),
+		"gameId":           gameID,
+		"code":             game.Code,
+		"name":             game.Name,
+		"status":           string(game.Status),
+		"boardId":          game.BoardID,
+		"boardVersion":     game.BoardVersion,
+		"mode":             game.Mode,
+		"timeLimitMinutes": game.TimeLimitMinutes,
+		"roundLimit":       game.RoundLimit,
 	})
 }
 
@@ -108,14 +151,17 @@ func (h *GameHandler) broadcastNewGame(gameID string) {
 	}
 
 	gameResponse := map[string]interface{}{
-		"id":         game.ID.Hex(),
-		"name":       game.Name,
-		"status":     string(game.Status),
-		"players":    len(game.Players),
-		"maxPlayers": game.MaxPlayers, // Use the actual value from the game model
-		"createdAt":  game.CreatedAt.Format(time.RFC3339),
-		"hostName":   hostName,
-		"updatedAt":  time.Now().Format(time.RFC3339), // Add timestamp for tracking
+		"id":               game.ID.Hex(),
+		"name":             game.Name,
+		"status":           string(game.Status),
+		"players":          len(game.Players),
+		"maxPlayers":       game.MaxPlayers, // Use the actual value from the game model
+		"createdAt":        game.CreatedAt.Format(time.RFC3339),
+		"hostName":         hostName,
+		"updatedAt":        time.Now().Format(time.RFC3339), // Add timestamp for tracking
+		"mode":             game.Mode,
+		"timeLimitMinutes": game.TimeLimitMinutes,
+		"roundLimit":       game.RoundLimit,
 	}
 
 	// Create the broadcast message
@@ -162,6 +208,10 @@ func (h *GameHandler) ListGames(c echo.Context) error {
 		MaxPlayers int    `json:"maxPlayers"`
 		CreatedAt  string `json:"createdAt"`
 		HostName   string `json:"hostName,omitempty"`
+		// How the game ends and its limits, so players know what they are joining
+		Mode             models.GameMode `json:"mode"`
+		TimeLimitMinutes int             `json:"timeLimitMinutes,omitempty"`
+		RoundLimit       int             `json:"roundLimit,omitempty"`
 	}
 
 	gamesList := make([]GameResponse, 0, len(games))
@@ -184,14 +234,17 @@ func (h *GameHandler) ListGames(c echo.Context) error {
 		h.logger.Debugf("Active player count for game %s: %d", game.ID.Hex(), activePlayerCount)
 
 		gamesList = append(gamesList, GameResponse{
-			ID:         game.ID.Hex(),
-			Code:       game.Code, // Room code
-			Name:       game.Name, // Assuming there's a Name field in the game model
-			Status:     string(game.Status),
-			Players:    activePlayerCount, // Use the count of active players
-			MaxPlayers: game.MaxPlayers,   // Use the actual value from the game model
-			CreatedAt:  game.CreatedAt.Format(time.RFC3339),
-			HostName:   hostName,
+			ID:               game.ID.Hex(),
+			Code:             game.Code, // Room code
+			Name:             game.Name, // Assuming there's a Name field in the game model
+			Status:           string(game.Status),
+			Players:          activePlayerCount, // Use the count of active players
+			MaxPlayers:       game.MaxPlayers,   // Use the actual value from the game model
+			CreatedAt:        game.CreatedAt.Format(time.RFC3339),
+			HostName:         hostName,
+			Mode:             gameMode(&game),
+			TimeLimitMinutes: game.TimeLimitMinutes,
+			RoundLimit:       game.RoundLimit,
 		})
 	}
 
@@ -201,6 +254,14 @@ func (h *GameHandler) ListGames(c echo.Context) error {
 	})
 }
 
+// gameMode returns the mode of a game, treating games created before modes existed as classic
+func gameMode(game *models.Game) models.GameMode {
+	if game.Mode == "" {
+		return models.GameModeClassic
+	}
+	return game.Mode
+}
+
 // GetGameDetails gets details for a specific game
 func (h *GameHandler) GetGameDetails(c echo.Context) error {
 	gameID := c.Param("gameId")
@@ -219,7 +280,9 @@ func (h *GameHandler) GetGameDetails(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, "Game not found")
 	}
 
-	return c.JSON(http.StatusOK, game)
+	// Only the requesting player's own hand is shown
+	userID := c.Get("userID").(string)
+	return c.JSON(http.StatusOK, game.VisibleTo(userID))
 }
 
 // JoinGame joins a game
@@ -305,6 +368,36 @@ func (h *GameHandler) StartGame(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// AddBot seats an AI opponent in a lobby game at the host's request
+func (h *GameHandler) AddBot(c echo.Context) error {
+	gameID := c.Param("gameId")
+	if gameID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing game ID")
+	}
+
+	var req AddBotRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	userID := c.Get("userID").(string)
+	player, err := h.gameManager.AddBot(gameID, userID, req.Difficulty)
+	if err != nil {
+		switch {
+		case errors.Is(err, manager.ErrInvalidBotDifficulty):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		case errors.Is(err, manager.ErrNotHost):
+			return echo.NewHTTPError(http.StatusForbidden, "Only the host can add bots")
+		case errors.Is(err, manager.ErrGameNotInLobby), errors.Is(err, manager.ErrGameFull):
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		h.logger.Errorf("Failed to add bot: %v", err)
+		return echo.NewHTTPError(http.StatusNotFound, "Game not found")
+	}
+
+	return c.JSON(http.StatusCreated, player)
+}
+
 // PauseGame pauses a game
 func (h *GameHandler) PauseGame(c echo.Context) error {
 	gameID := c.Param("gameId")
@@ -331,7 +424,140 @@ func (h *GameHandler) GetGameState(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, "Game not found")
 	}
 
-	return c.JSON(http.StatusOK, game)
+	// Only the requesting player's own hand is shown
+	userID := c.Get("userID").(string)
+	return c.JSON(http.StatusOK, GameStateResponse{
+		Game:             game.VisibleTo(userID),
+		ActiveShadowbans: rules.ActiveShadowbans(game),
+	})
+}
+
+// GetGameResults returns the final standings of a completed game
+func (h *GameHandler) GetGameResults(c echo.Context) error {
+	gameID := c.Param("gameId")
+	if gameID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing game ID")
+	}
+
+	results, err := h.gameManager.GetGameResults(gameID)
+	if err != nil {
+		if errors.Is(err, manager.ErrGameNotCompleted) {
+			return echo.NewHTTPError(http.StatusConflict, "Game is not completed")
+		}
+		h.logger.Errorf("Failed to get game results: %v", err)
+		return echo.NewHTTPError(http.StatusNotFound, "Game not found")
+	}
+
+	return c.JSON(http.StatusOK, results)
+}
+
+// GetGameEvents returns the entries of a game's history after the sequence number in
+// the since query parameter, so a reconnecting client can catch up from the last event
+// it saw. Entries come oldest first, a page at a time.
+func (h *GameHandler) GetGameEvents(c echo.Context) error {
+	gameID := c.Param("gameId")
+	if gameID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing game ID")
+	}
+
+	since := 0
+	if value := c.QueryParam("since"); value != "" {
+		var err error
+		if since, err = strconv.Atoi(value); err != nil || since < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "since must be a sequence number")
+		}
+	}
+
+	userID := c.Get("userID").(string)
+	events, err := h.gameManager.GetGameEvents(gameID, userID, since)
+	if err != nil {
+		h.logger.Errorf("Failed to get game events: %v", err)
+		return echo.NewHTTPError(http.StatusNotFound, "Game not found")
+	}
+
+	return c.JSON(http.StatusOK, GameEventsResponse{
+		GameID: gameID,
+		Since:  since,
+		Events: events,
+	})
+}
+
+// ReplayGame streams the recorded timeline of a completed game over a read-only
+// WebSocket. The speed (1, 2 or 4) and turn query parameters choose how fast and from
+// which turn it starts; the viewer can then send speed, seek, pause and resume controls.
+func (h *GameHandler) ReplayGame(c echo.Context) error {
+	gameID := c.Param("gameId")
+	if gameID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing game ID")
+	}
+
+	opts := replay.Options{ViewerID: c.Get("userID").(string), Speed: 1, Turn: 1}
+	if value := c.QueryParam("speed"); value != "" {
+		speed, err := strconv.Atoi(value)
+		if err != nil || !replay.ValidSpeed(speed) {
+			return echo.NewHTTPError(http.StatusBadRequest, "speed must be 1, 2 or 4")
+		}
+		opts.Speed = speed
+	}
+
+	timeline, err := h.gameManager.GetReplay(gameID)
+	if err != nil {
+		if errors.Is(err, manager.ErrGameNotCompleted) {
+			return echo.NewHTTPError(http.StatusConflict, "Game is not completed")
+		}
+		if errors.Is(err, manager.ErrNoHistory) || errors.Is(err, replay.ErrNoStart) {
+			return echo.NewHTTPError(http.StatusNotFound, "No replay was recorded for this game")
+		}
+		h.logger.Errorf("Failed to load replay: %v", err)
+		return echo.NewHTTPError(http.StatusNotFound, "Game not found")
+	}
+	if value := c.QueryParam("turn"); value != "" {
+		turn, err := strconv.Atoi(value)
+		if err != nil || turn < 1 || turn > timeline.Turns() {
+			return echo.NewHTTPError(http.StatusBadRequest, "turn must be between 1 and "+strconv.Itoa(timeline.Turns()))
+		}
+		opts.Turn = turn
+	}
+
+	conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		h.logger.Errorf("Failed to upgrade replay connection: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to establish WebSocket connection")
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+
+	// The viewer can only steer the playback; anything that is not a control is ignored,
+	// and closing the connection ends the replay
+	controls := make(chan replay.Control)
+	go func() {
+		defer cancel()
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var control replay.Control
+			if json.Unmarshal(data, &control) != nil {
+				continue
+			}
+			select {
+			case controls <- control:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	err = timeline.Play(ctx, opts, controls, func(msg map[string]interface{}) error {
+		return conn.WriteJSON(msg)
+	})
+	if err != nil {
+		h.logger.Warnf("Replay of game %s for %s ended: %v", gameID, opts.ViewerID, err)
+	}
+	return nil
 }
 
 // RollDice handles the roll dice action
@@ -359,6 +585,26 @@ func (h *GameHandler) UseCard(c echo.Context) error {
 	return h.handleGameAction(c, models.ActionTypeUseCard)
 }
 
+// DeclineProperty handles passing on a property, which puts it up for auction
+func (h *GameHandler) DeclineProperty(c echo.Context) error {
+	return h.handleGameAction(c, models.ActionTypeDeclineProperty)
+}
+
+// PlaceBid handles a bid in a property auction
+func (h *GameHandler) PlaceBid(c echo.Context) error {
+	return h.handleGameAction(c, models.ActionTypePlaceBid)
+}
+
+// DeclareBankruptcy handles a player giving up over a debt they cannot pay
+func (h *GameHandler) DeclareBankruptcy(c echo.Context) error {
+	return h.handleGameAction(c, models.ActionTypeDeclareBankruptcy)
+}
+
+// CastVote handles a vote called by a card
+func (h *GameHandler) CastVote(c echo.Context) error {
+	return h.handleGameAction(c, models.ActionTypeCastVote)
+}
+
 // MortgageProperty handles the mortgage property action
 func (h *GameHandler) MortgageProperty(c echo.Context) error {
 	return h.handleGameAction(c, models.ActionTypeMortgageProperty)
@@ -379,6 +625,26 @@ func (h *GameHandler) BuildCheckmark(c echo.Context) error {
 	return h.handleGameAction(c, models.ActionTypeBuildCheckmark)
 }
 
+// SellEngagement handles the sell engagement action
+func (h *GameHandler) SellEngagement(c echo.Context) error {
+	return h.handleGameAction(c, models.ActionTypeSellEngagement)
+}
+
+// SellCheckmark handles the sell checkmark action
+func (h *GameHandler) SellCheckmark(c echo.Context) error {
+	return h.handleGameAction(c, models.ActionTypeSellCheckmark)
+}
+
+// PayJailFine handles the pay jail fine action
+func (h *GameHandler) PayJailFine(c echo.Context) error {
+	return h.handleGameAction(c, models.ActionTypePayJailFine)
+}
+
+// UseJailCard handles the use get out of jail card action
+func (h *GameHandler) UseJailCard(c echo.Context) error {
+	return h.handleGameAction(c, models.ActionTypeUseJailCard)
+}
+
 // EndTurn handles the end turn action
 func (h *GameHandler) EndTurn(c echo.Context) error {
 	return h.handleGameAction(c, models.ActionTypeEndTurn)
@@ -519,6 +785,12 @@ func (h *GameHandler) FixGamesWithoutCodes(c echo.Context) error {
 	})
 }
 
+// pathPayloadKeys maps the path parameters of action routes to the payload field they fill in
+var pathPayloadKeys = map[string]string{
+	"actionId": "effect",  // The special effect to start
+	"tradeId":  "tradeId", // The trade being answered
+}
+
 // handleGameAction is a helper function to handle game actions
 func (h *GameHandler) handleGameAction(c echo.Context, actionType models.ActionType) error {
 	gameID := c.Param("gameId")
@@ -531,23 +803,43 @@ func (h *GameHandler) handleGameAction(c echo.Context, actionType models.ActionT
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
 	}
 
+	// Some routes name what the action applies to in the path
+	for param, key := range pathPayloadKeys {
+		value := c.Param(param)
+		if value == "" {
+			continue
+		}
+		payload, _ := req.Payload.(map[string]interface{})
+		if payload == nil {
+			payload = map[string]interface{}{}
+		}
+		if _, ok := payload[key]; !ok {
+			payload[key] = value
+		}
+		req.Payload = payload
+	}
+
 	// Get user ID from context (set by JWT middleware)
 	userID := c.Get("userID").(string)
 
 	// Create game action
 	action := models.GameAction{
-		Type:     actionType,
-		PlayerID: userID,
-		GameID:   gameID,
-		Payload:  req.Payload,
+		Type:      actionType,
+		PlayerID:  userID,
+		GameID:    gameID,
+		Payload:   req.Payload,
+		Timestamp: time.Now(),
 	}
 
 	// Process action
-	err := h.gameManager.ProcessGameAction(action)
+	result, err := h.gameManager.ProcessGameAction(action)
 	if err != nil {
+		if rules.IsRuleViolation(err) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		h.logger.Errorf("Failed to process action: %v", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process action")
 	}
 
-	return c.NoContent(http.StatusNoContent)
+	return c.JSON(http.StatusOK, result)
 }
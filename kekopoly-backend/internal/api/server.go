@@ -231,6 +231,7 @@ func (s *Server) configureRoutes() {
 	userHandler := handlers.NewUserHandler(s.logger)
 	wsHandler := handlers.NewWebSocketHandler(s.wsHub, s.logger, s.cfg)
 	healthHandler := handlers.NewHealthHandler(s.mongoClient, s.redisClient, s.logger)
+	boardHandler := handlers.NewBoardHandler(s.gameManager, s.logger)
 
 	// Configure static file serving with proper MIME types
 	staticConfig := middleware.StaticConfig{
@@ -283,6 +284,11 @@ func (s *Server) configureRoutes() {
 	authGroup.GET("/refresh-token", authHandler.RefreshToken)
 	authGroup.POST("/logout", authHandler.Logout)
 
+	// Board definitions (no JWT required, they are the same for every player)
+	apiV1.GET("/board", boardHandler.GetBoard)
+	apiV1.GET("/boards", boardHandler.ListBoards)
+	apiV1.GET("/boards/:boardId", boardHandler.GetBoard)
+
 	// JWT middleware for protected routes
 	jwtMiddleware := auth.JWTMiddleware(s.cfg.JWT.Secret)
 
@@ -299,7 +305,11 @@ func (s *Server) configureRoutes() {
 	gameGroup.POST("/:gameId/join", gameHandler.JoinGame)
 	gameGroup.POST("/:gameId/leave", gameHandler.LeaveGame)
 	gameGroup.POST("/:gameId/start", gameHandler.StartGame)
+	gameGroup.POST("/:gameId/bots", gameHandler.AddBot)
 	gameGroup.GET("/:gameId/state", gameHandler.GetGameState)
+	gameGroup.GET("/:gameId/results", gameHandler.GetGameResults)
+	gameGroup.GET("/:gameId/events", gameHandler.GetGameEvents)
+	gameGroup.GET("/:gameId/replay", gameHandler.ReplayGame) // Read-only WebSocket
 	gameGroup.POST("/:gameId/sync", gameHandler.SyncGameState)
 	gameGroup.POST("/cleanup", gameHandler.CleanupStaleGames)
 	gameGroup.POST("/fix-codes", gameHandler.FixGamesWithoutCodes) // Fix for games without room codes
@@ -308,13 +318,21 @@ func (s *Server) configureRoutes() {
 	actionGroup := apiV1.Group("/games/:gameId/actions", jwtMiddleware)
 	actionGroup.POST("/roll-dice", gameHandler.RollDice)
 	actionGroup.POST("/buy-property", gameHandler.BuyProperty)
+	actionGroup.POST("/decline-property", gameHandler.DeclineProperty)
+	actionGroup.POST("/place-bid", gameHandler.PlaceBid)
 	actionGroup.POST("/pay-rent", gameHandler.PayRent)
+	actionGroup.POST("/declare-bankruptcy", gameHandler.DeclareBankruptcy)
 	actionGroup.POST("/draw-card", gameHandler.DrawCard)
 	actionGroup.POST("/use-card", gameHandler.UseCard)
+	actionGroup.POST("/cast-vote", gameHandler.CastVote)
 	actionGroup.POST("/mortgage-property", gameHandler.MortgageProperty)
 	actionGroup.POST("/unmortgage-property", gameHandler.UnmortgageProperty)
 	actionGroup.POST("/build-engagement", gameHandler.BuildEngagement)
 	actionGroup.POST("/build-checkmark", gameHandler.BuildCheckmark)
+	actionGroup.POST("/sell-engagement", gameHandler.SellEngagement)
+	actionGroup.POST("/sell-checkmark", gameHandler.SellCheckmark)
+	actionGroup.POST("/pay-jail-fine", gameHandler.PayJailFine)
+	actionGroup.POST("/use-jail-card", gameHandler.UseJailCard)
 	actionGroup.POST("/end-turn", gameHandler.EndTurn)
 	actionGroup.POST("/trade", gameHandler.InitiateTrade)
 	actionGroup.POST("/trade/:tradeId/respond", gameHandler.RespondToTrade)
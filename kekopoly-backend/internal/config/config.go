@@ -87,13 +87,18 @@ type JWTConfig struct {
 
 // GameConfig holds game-specific configuration
 type GameConfig struct {
-	DisconnectionTimeout   int `mapstructure:"disconnection_timeout"` // in seconds
-	MaxPlayers             int `mapstructure:"max_players"`
-	InitialBalance         int `mapstructure:"initial_balance"`
-	TurnTimeout            int `mapstructure:"turn_timeout"` // in seconds
-	CardDeckSize           int `mapstructure:"card_deck_size"`
-	MinimumPlayersToStart  int `mapstructure:"minimum_players_to_start"`
-	IdleGameExpiryDuration int `mapstructure:"idle_game_expiry"` // in hours
+	DisconnectionTimeout   int    `mapstructure:"disconnection_timeout"` // in seconds
+	MaxPlayers             int    `mapstructure:"max_players"`
+	InitialBalance         int    `mapstructure:"initial_balance"`
+	TurnTimeout            int    `mapstructure:"turn_timeout"` // in seconds
+	CardDeckSize           int    `mapstructure:"card_deck_size"`
+	MinimumPlayersToStart  int    `mapstructure:"minimum_players_to_start"`
+	IdleGameExpiryDuration int    `mapstructure:"idle_game_expiry"`    // in hours
+	BoardPacksDir          string `mapstructure:"board_packs_dir"`     // directory of additional board pack JSON files
+	MarketBullChance       int    `mapstructure:"market_bull_chance"`  // percent chance per round of a bull market
+	MarketCrashChance      int    `mapstructure:"market_crash_chance"` // percent chance per round of a market crash
+	MarketBullRounds       int    `mapstructure:"market_bull_rounds"`  // rounds a bull market lasts
+	MarketCrashRounds      int    `mapstructure:"market_crash_rounds"` // rounds a market crash lasts
 }
 
 // SolanaConfig holds Solana blockchain configuration
@@ -167,6 +172,11 @@ func setDefaults() {
 	viper.SetDefault("game.card_deck_size", 16)
 	viper.SetDefault("game.minimum_players_to_start", 2)
 	viper.SetDefault("game.idle_game_expiry", 24)
+	viper.SetDefault("game.board_packs_dir", "boards")
+	viper.SetDefault("game.market_bull_chance", 16)
+	viper.SetDefault("game.market_crash_chance", 16)
+	viper.SetDefault("game.market_bull_rounds", 1)
+	viper.SetDefault("game.market_crash_rounds", 1)
 
 	// Solana defaults
 	viper.SetDefault("solana.rpc_url", "") // Empty means use the default mainnet
@@ -0,0 +1,245 @@
+package board
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+//go:embed default.json
+var defaultBoardJSON []byte
+
+// SpaceType identifies what happens when a player lands on a space
+type SpaceType string
+
+const (
+	SpaceTypeJail     SpaceType = "JAIL"
+	SpaceTypeStart    SpaceType = "START"
+	SpaceTypeProperty SpaceType = "PROPERTY"
+	SpaceTypeCard     SpaceType = "CARD"
+	SpaceTypeCollect  SpaceType = "COLLECT"
+	SpaceTypeFree     SpaceType = "FREE"
+	SpaceTypeSpecial  SpaceType = "SPECIAL"
+)
+
+// regularRentTiers is the length of a regular property's rent table: the rent
+// without buildings, with one to four engagements and with a blue checkmark
+const regularRentTiers = 6
+
+// Board is a versioned definition of the spaces and properties of a Kekopoly board
+type Board struct {
+	ID         string        `json:"id"`
+	Version    int           `json:"version"`
+	Name       string        `json:"name"`
+	Spaces     []Space       `json:"spaces"`
+	Properties []PropertyDef `json:"properties"`
+}
+
+// Space is a single square on the board
+type Space struct {
+	Position   int             `json:"position"`
+	Name       string          `json:"name"`
+	Type       SpaceType       `json:"type"`
+	PropertyID string          `json:"propertyId,omitempty"` // Set for PROPERTY spaces
+	Deck       models.CardType `json:"deck,omitempty"`       // Card deck drawn from on CARD spaces
+	Amount     int             `json:"amount,omitempty"`     // Kekels paid out on COLLECT spaces
+	Effect     string          `json:"effect,omitempty"`     // Effect of a SPECIAL space, e.g. skip_turn or shadowban
+}
+
+// PropertyDef is the static definition of a property; ownership and buildings live in the game
+type PropertyDef struct {
+	ID            string              `json:"id"`
+	Name          string              `json:"name"`
+	Type          models.PropertyType `json:"type"`
+	Group         string              `json:"group"`
+	Price         int                 `json:"price"`
+	RentTable     []int               `json:"rentTable"`
+	BuildCost     int                 `json:"buildCost,omitempty"`
+	MortgageValue int                 `json:"mortgageValue"`
+}
+
+var defaultBoard = mustParse(defaultBoardJSON)
+
+// Default returns the built-in Kekopoly board
+func Default() *Board {
+	return defaultBoard
+}
+
+// Parse decodes and validates a board definition
+func Parse(data []byte) (*Board, error) {
+	var b Board
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to decode board: %w", err)
+	}
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func mustParse(data []byte) *Board {
+	b, err := Parse(data)
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded board: %v", err))
+	}
+	return b
+}
+
+// Validate checks that a board is internally consistent: positions run contiguously
+// from 0, there is at least one START and one JAIL, every property belongs to a group
+// and has a rent for each of its tiers, every property space refers to exactly one
+// property definition and every card space to a known deck
+func (b *Board) Validate() error {
+	if b.ID == "" {
+		return fmt.Errorf("board has no id")
+	}
+	if b.Version <= 0 {
+		return fmt.Errorf("board %s has invalid version %d", b.ID, b.Version)
+	}
+	if len(b.Spaces) == 0 {
+		return fmt.Errorf("board %s has no spaces", b.ID)
+	}
+
+	defs := make(map[string]bool, len(b.Properties))
+	for _, def := range b.Properties {
+		if def.ID == "" {
+			return fmt.Errorf("board %s has a property without an id", b.ID)
+		}
+		if defs[def.ID] {
+			return fmt.Errorf("board %s defines property %s twice", b.ID, def.ID)
+		}
+		if def.Price <= 0 {
+			return fmt.Errorf("property %s has invalid price %d", def.ID, def.Price)
+		}
+		if len(def.RentTable) == 0 {
+			return fmt.Errorf("property %s has no rent table", def.ID)
+		}
+		if def.Type == models.PropertyTypeRegular && len(def.RentTable) != regularRentTiers {
+			return fmt.Errorf("property %s has %d rents, want %d", def.ID, len(def.RentTable), regularRentTiers)
+		}
+		if def.Group == "" {
+			return fmt.Errorf("property %s has no group", def.ID)
+		}
+		defs[def.ID] = true
+	}
+
+	positions := make([]bool, len(b.Spaces))
+	placed := make(map[string]bool, len(b.Properties))
+	starts, jails := 0, 0
+	for _, space := range b.Spaces {
+		if space.Position < 0 || space.Position >= len(b.Spaces) {
+			return fmt.Errorf("space %q is at position %d, outside the board", space.Name, space.Position)
+		}
+		if positions[space.Position] {
+			return fmt.Errorf("position %d is defined twice", space.Position)
+		}
+		positions[space.Position] = true
+
+		switch space.Type {
+		case SpaceTypeProperty:
+			if !defs[space.PropertyID] {
+				return fmt.Errorf("space %d refers to unknown property %q", space.Position, space.PropertyID)
+			}
+			if placed[space.PropertyID] {
+				return fmt.Errorf("property %s is placed on the board twice", space.PropertyID)
+			}
+			placed[space.PropertyID] = true
+		case SpaceTypeCard:
+			switch space.Deck {
+			case models.CardTypeMeme, models.CardTypeRedpill, models.CardTypeEegi:
+			case "":
+				return fmt.Errorf("card space %d has no deck", space.Position)
+			default:
+				return fmt.Errorf("card space %d has unknown deck %q", space.Position, space.Deck)
+			}
+		case SpaceTypeStart:
+			starts++
+		case SpaceTypeJail:
+			jails++
+		case SpaceTypeCollect, SpaceTypeFree, SpaceTypeSpecial:
+		default:
+			return fmt.Errorf("space %d has unknown type %q", space.Position, space.Type)
+		}
+	}
+
+	if starts == 0 {
+		return fmt.Errorf("board %s has no START space", b.ID)
+	}
+	if jails == 0 {
+		return fmt.Errorf("board %s has no JAIL space", b.ID)
+	}
+
+	for id := range defs {
+		if !placed[id] {
+			return fmt.Errorf("property %s is not placed on the board", id)
+		}
+	}
+	return nil
+}
+
+// Size returns the number of spaces on the board
+func (b *Board) Size() int {
+	return len(b.Spaces)
+}
+
+// SpaceAt returns the space at a board position, or nil if there is none
+func (b *Board) SpaceAt(position int) *Space {
+	for i := range b.Spaces {
+		if b.Spaces[i].Position == position {
+			return &b.Spaces[i]
+		}
+	}
+	return nil
+}
+
+// StartPosition returns the position of the first START space
+func (b *Board) StartPosition() int {
+	return b.firstOfType(SpaceTypeStart)
+}
+
+// JailPosition returns the position of the first JAIL space
+func (b *Board) JailPosition() int {
+	return b.firstOfType(SpaceTypeJail)
+}
+
+func (b *Board) firstOfType(spaceType SpaceType) int {
+	position := -1
+	for _, space := range b.Spaces {
+		if space.Type == spaceType && (position < 0 || space.Position < position) {
+			position = space.Position
+		}
+	}
+	return position
+}
+
+// NewProperties builds a fresh, unowned set of game properties from the board definition
+func (b *Board) NewProperties() []models.Property {
+	defs := make(map[string]PropertyDef, len(b.Properties))
+	for _, def := range b.Properties {
+		defs[def.ID] = def
+	}
+
+	properties := make([]models.Property, 0, len(b.Properties))
+	for _, space := range b.Spaces {
+		if space.Type != SpaceTypeProperty {
+			continue
+		}
+		def := defs[space.PropertyID]
+		properties = append(properties, models.Property{
+			ID:            def.ID,
+			Name:          def.Name,
+			Type:          def.Type,
+			Group:         def.Group,
+			Position:      space.Position,
+			Price:         def.Price,
+			RentBase:      def.RentTable[0],
+			RentCurrent:   def.RentTable[0],
+			RentTable:     append([]int(nil), def.RentTable...),
+			BuildCost:     def.BuildCost,
+			MortgageValue: def.MortgageValue,
+		})
+	}
+	return properties
+}
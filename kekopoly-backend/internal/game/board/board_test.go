@@ -0,0 +1,75 @@
+package board
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+func TestDefaultBoard(t *testing.T) {
+	b := Default()
+
+	require.Equal(t, 26, b.Size())
+	assert.Equal(t, SpaceTypeJail, b.SpaceAt(0).Type)
+	assert.Equal(t, SpaceTypeStart, b.SpaceAt(1).Type)
+	assert.Equal(t, "RARE PEPE PLAZA", b.SpaceAt(25).Name)
+	assert.Nil(t, b.SpaceAt(26))
+}
+
+func TestNewProperties(t *testing.T) {
+	properties := Default().NewProperties()
+	require.Len(t, properties, 13)
+
+	byID := make(map[string]models.Property, len(properties))
+	for _, p := range properties {
+		byID[p.ID] = p
+	}
+
+	colmer := byID["prop_colmer_corner"]
+	assert.Equal(t, 2, colmer.Position)
+	assert.Equal(t, 60, colmer.Price)
+	assert.Equal(t, 2, colmer.RentBase)
+	assert.Equal(t, []int{2, 10, 30, 90, 160, 250}, colmer.RentTable)
+	assert.Equal(t, 50, colmer.BuildCost)
+	assert.Equal(t, 30, colmer.MortgageValue)
+	assert.Empty(t, colmer.OwnerID)
+
+	assert.Equal(t, models.PropertyTypeTransit, byID["prop_rage_train"].Type)
+	assert.Equal(t, 19, byID["prop_pepe_train"].Position)
+
+	// Each game gets its own copy of the rent tables
+	properties[0].RentTable[0] = 999
+	assert.NotEqual(t, 999, Default().NewProperties()[0].RentTable[0])
+}
+
+func TestParseRejectsInvalidBoards(t *testing.T) {
+	tests := map[string]string{
+		"missing id":       `{"version":1,"spaces":[{"position":0,"name":"START","type":"START"}]}`,
+		"duplicate space":  `{"id":"b","version":1,"spaces":[{"position":0,"name":"A","type":"FREE"},{"position":0,"name":"B","type":"FREE"}]}`,
+		"unknown property": `{"id":"b","version":1,"spaces":[{"position":0,"name":"A","type":"PROPERTY","propertyId":"nope"}]}`,
+		"unplaced property": `{"id":"b","version":1,"spaces":[{"position":0,"name":"A","type":"FREE"}],
+			"properties":[{"id":"p","name":"P","type":"REGULAR","price":10,"rentTable":[1]}]}`,
+		"card without deck": `{"id":"b","version":1,"spaces":[{"position":0,"name":"A","type":"CARD"}]}`,
+		"unknown deck":      `{"id":"b","version":1,"spaces":[{"position":0,"name":"A","type":"CARD","deck":"TAROT"}]}`,
+		"unknown type":      `{"id":"b","version":1,"spaces":[{"position":0,"name":"A","type":"LAVA"}]}`,
+		"gap in positions":  `{"id":"b","version":1,"spaces":[{"position":0,"name":"J","type":"JAIL"},{"position":2,"name":"S","type":"START"}]}`,
+		"no jail":           `{"id":"b","version":1,"spaces":[{"position":0,"name":"S","type":"START"}]}`,
+		"no start":          `{"id":"b","version":1,"spaces":[{"position":0,"name":"J","type":"JAIL"}]}`,
+		"property without group": `{"id":"b","version":1,"spaces":[{"position":0,"name":"J","type":"JAIL"},{"position":1,"name":"S","type":"START"},
+			{"position":2,"name":"P","type":"PROPERTY","propertyId":"p"}],
+			"properties":[{"id":"p","name":"P","type":"REGULAR","price":10,"rentTable":[1]}]}`,
+		"short rent table": `{"id":"b","version":1,"spaces":[{"position":0,"name":"J","type":"JAIL"},{"position":1,"name":"S","type":"START"},
+			{"position":2,"name":"P","type":"PROPERTY","propertyId":"p"}],
+			"properties":[{"id":"p","name":"P","type":"REGULAR","group":"g","price":10,"rentTable":[1,5]}]}`,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
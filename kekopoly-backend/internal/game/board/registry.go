@@ -0,0 +1,131 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrUnknownBoard is returned when a board pack is not installed
+	ErrUnknownBoard = errors.New("unknown board")
+	// ErrDefaultBoard is returned when a board pack would replace the built-in default board
+	ErrDefaultBoard = errors.New("board pack cannot replace the default board")
+)
+
+// Summary describes an installed board pack
+type Summary struct {
+	ID         string `json:"id"`
+	Version    int    `json:"version"`
+	Name       string `json:"name"`
+	Spaces     int    `json:"spaces"`
+	Properties int    `json:"properties"`
+	Default    bool   `json:"default"`
+}
+
+// Registry holds the board packs available to new games. It always contains the
+// built-in default board.
+type Registry struct {
+	mutex  sync.RWMutex
+	boards map[string]*Board
+}
+
+// NewRegistry creates a registry containing only the default board
+func NewRegistry() *Registry {
+	return &Registry{
+		boards: map[string]*Board{defaultBoard.ID: defaultBoard},
+	}
+}
+
+// Register installs a board pack, replacing any pack with the same ID. The default
+// board is built in and cannot be replaced.
+func (r *Registry) Register(b *Board) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+	if b.ID == defaultBoard.ID {
+		return fmt.Errorf("%w: %s", ErrDefaultBoard, b.ID)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.boards[b.ID] = b
+	return nil
+}
+
+// LoadDir installs every *.json board pack in a directory. Valid packs are installed
+// even if others fail; the returned error describes every pack that was rejected.
+func (r *Registry) LoadDir(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read board pack directory: %w", err)
+	}
+
+	loaded := 0
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".json") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+
+		b, err := Parse(data)
+		if err == nil {
+			err = r.Register(b)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		loaded++
+	}
+
+	return loaded, errors.Join(errs...)
+}
+
+// Get returns the board pack with the given ID; an empty ID selects the default board
+func (r *Registry) Get(id string) (*Board, error) {
+	if id == "" {
+		return defaultBoard, nil
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	b, ok := r.boards[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBoard, id)
+	}
+	return b, nil
+}
+
+// List returns a summary of every installed board pack, ordered by ID
+func (r *Registry) List() []Summary {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	summaries := make([]Summary, 0, len(r.boards))
+	for _, b := range r.boards {
+		summaries = append(summaries, Summary{
+			ID:         b.ID,
+			Version:    b.Version,
+			Name:       b.Name,
+			Spaces:     b.Size(),
+			Properties: len(b.Properties),
+			Default:    b.ID == defaultBoard.ID,
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].ID < summaries[j].ID
+	})
+	return summaries
+}
@@ -0,0 +1,72 @@
+package board
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const quickBoardJSON = `{
+  "id": "quick",
+  "version": 2,
+  "name": "Quick Game",
+  "spaces": [
+    { "position": 0, "name": "JAIL", "type": "JAIL" },
+    { "position": 1, "name": "START", "type": "START" },
+    { "position": 2, "name": "A", "type": "PROPERTY", "propertyId": "a" },
+    { "position": 3, "name": "FREE", "type": "FREE" }
+  ],
+  "properties": [
+    { "id": "a", "name": "A", "type": "REGULAR", "group": "brown", "price": 60, "rentTable": [2, 10, 30, 90, 160, 250], "mortgageValue": 30 }
+  ]
+}`
+
+func TestRegistryDefaultBoard(t *testing.T) {
+	r := NewRegistry()
+
+	b, err := r.Get("")
+	require.NoError(t, err)
+	assert.Equal(t, Default().ID, b.ID)
+
+	_, err = r.Get("missing")
+	assert.ErrorIs(t, err, ErrUnknownBoard)
+
+	replacement := *Default()
+	replacement.Name = "Impostor"
+	assert.ErrorIs(t, r.Register(&replacement), ErrDefaultBoard)
+	b, err = r.Get(Default().ID)
+	require.NoError(t, err)
+	assert.Same(t, Default(), b)
+}
+
+func TestRegistryLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "quick.json"), []byte(quickBoardJSON), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"id":"broken"}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a board"), 0o644))
+
+	r := NewRegistry()
+	loaded, err := r.LoadDir(dir)
+	assert.Equal(t, 1, loaded)
+	assert.ErrorContains(t, err, "broken.json")
+
+	b, err := r.Get("quick")
+	require.NoError(t, err)
+	assert.Equal(t, 4, b.Size())
+	assert.Equal(t, 1, b.StartPosition())
+	assert.Equal(t, 0, b.JailPosition())
+
+	summaries := r.List()
+	require.Len(t, summaries, 2)
+	assert.Equal(t, Default().ID, summaries[0].ID)
+	assert.True(t, summaries[0].Default)
+	assert.Equal(t, Summary{ID: "quick", Version: 2, Name: "Quick Game", Spaces: 4, Properties: 1}, summaries[1])
+}
+
+func TestRegistryLoadDirMissing(t *testing.T) {
+	_, err := NewRegistry().LoadDir(filepath.Join(t.TempDir(), "nope"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,284 @@
+package bot
+
+import (
+	"github.com/kekopoly/backend/internal/game/cards"
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rules"
+)
+
+// cardValue is what a bot reckons a card in hand is worth when weighing a trade
+const cardValue = 50
+
+// Profile is a bot's playing style. Bots decide one move at a time and play through the
+// same rules engine as people, so a move the rules reject is simply not made.
+type Profile struct {
+	Name        string
+	Reserve     int  // Kekels the bot keeps back when buying, building or bidding
+	Builds      bool // Whether the bot puts engagements on groups it owns
+	MaxBid      int  // Most the bot bids in an auction, as a percentage of the market price; zero never bids
+	Trades      bool // Whether the bot answers trade offers; otherwise they are left to expire
+	TradeMargin int  // Value a trade must gain the bot, as a percentage of what it gives up
+}
+
+var (
+	// Conservative stands in for players who are away: it plays their turns out, only
+	// buying what leaves a comfortable reserve, and never builds, bids or trades
+	Conservative = Profile{Name: "conservative", Reserve: 300}
+	// Cautious keeps a large reserve and only takes trades well in its favour
+	Cautious = Profile{Name: "cautious", Reserve: 400, Builds: true, MaxBid: 80, Trades: true, TradeMargin: 25}
+	// Balanced buys and builds steadily and bids up to the market price
+	Balanced = Profile{Name: "balanced", Reserve: 200, Builds: true, MaxBid: 100, Trades: true, TradeMargin: 10}
+	// Aggressive spends down to its last kekels and overbids for what it wants
+	Aggressive = Profile{Name: "aggressive", Reserve: 50, Builds: true, MaxBid: 130, Trades: true}
+)
+
+// Difficulties are the profiles of the AI opponents a host can add to a game
+var Difficulties = map[models.BotDifficulty]Profile{
+	models.BotDifficultyCautious:   Cautious,
+	models.BotDifficultyBalanced:   Balanced,
+	models.BotDifficultyAggressive: Aggressive,
+}
+
+// For returns the profile that plays for a player: their difficulty for an AI
+// opponent, and the conservative stand-in for a person who is away
+func For(player *models.Player) Profile {
+	if profile, ok := Difficulties[player.BotDifficulty]; ok && player.IsBot {
+		return profile
+	}
+	return Conservative
+}
+
+// NextMove returns the next move of any player a bot plays for, as reported by plays.
+// The player whose turn it is goes first, then any bot with a trade to answer or a bid
+// to place.
+func NextMove(game *models.Game, plays func(*models.Player) bool) (models.GameAction, bool) {
+	if current := findPlayer(game, game.CurrentTurn); current != nil && plays(current) {
+		if action, ok := For(current).NextAction(game, current.ID); ok {
+			return action, true
+		}
+	}
+	for i := range game.Players {
+		player := &game.Players[i]
+		if player.ID == game.CurrentTurn || !plays(player) {
+			continue
+		}
+		if action, ok := For(player).NextAction(game, player.ID); ok {
+			return action, true
+		}
+	}
+	return models.GameAction{}, false
+}
+
+// NextAction returns the bot's next move for a player, or false when it has nothing to
+// do. Outside its own turn a bot only answers trades and bids in auctions.
+func (p Profile) NextAction(game *models.Game, playerID string) (models.GameAction, bool) {
+	if game.Status != models.GameStatusActive {
+		return models.GameAction{}, false
+	}
+	player := findPlayer(game, playerID)
+	if player == nil || player.Status == models.PlayerStatusBankrupt || player.Status == models.PlayerStatusForfeited {
+		return models.GameAction{}, false
+	}
+
+	move := func(actionType models.ActionType, payload map[string]interface{}) (models.GameAction, bool) {
+		return models.GameAction{Type: actionType, PlayerID: playerID, GameID: game.ID.Hex(), Payload: payload}, true
+	}
+
+	// A shadowbanned bot cannot trade, so offers wait until its ban is lifted
+	if p.Trades && player.ShadowbanRemainingTurns == 0 {
+		for i := range game.Trades {
+			trade := &game.Trades[i]
+			if trade.RecipientID != playerID || trade.Status != models.TradeStatusPending {
+				continue
+			}
+			response := rules.TradeResponseReject
+			if p.wantsTrade(game, player, trade) {
+				response = rules.TradeResponseAccept
+			}
+			return move(models.ActionTypeTrade, map[string]interface{}{"tradeId": trade.ID, "response": response})
+		}
+	}
+
+	turn := game.TurnState
+	if turn.Auction != nil {
+		if amount, ok := p.nextBid(game, player, turn.Auction); ok {
+			return move(models.ActionTypePlaceBid, map[string]interface{}{"amount": amount})
+		}
+		return models.GameAction{}, false
+	}
+	if game.CurrentTurn != playerID {
+		return models.GameAction{}, false
+	}
+
+	switch {
+	case turn.PendingCard != nil:
+		// Cards aimed at the bot itself cannot be passed on; the only one that waits
+		// for a choice makes it mortgage a property
+		if def, ok := cards.Lookup(turn.PendingCard.ID); ok && !def.Kept && !def.Optional() {
+			payload := map[string]interface{}{}
+			if property := mortgageable(game, playerID); property != nil {
+				payload["propertyId"] = property.ID
+			}
+			return move(models.ActionTypeUseCard, payload)
+		}
+		// Pass on other cards that need choices, and keep the hand as it is when it is full
+		return move(models.ActionTypeUseCard, map[string]interface{}{"pass": true})
+	case turn.PendingDraw != "":
+		return move(models.ActionTypeDrawCard, nil)
+	case turn.PendingRent != nil:
+		if player.Balance >= turn.PendingRent.Amount {
+			return move(models.ActionTypePayRent, nil)
+		}
+		if property := mortgageable(game, playerID); property != nil {
+			return move(models.ActionTypeMortgageProperty, map[string]interface{}{"propertyId": property.ID})
+		}
+		return move(models.ActionTypeDeclareBankruptcy, nil)
+	case turn.PendingPurchase != "":
+		property := findProperty(game, turn.PendingPurchase)
+		if property != nil && player.Balance-rules.MarketPrice(game, property) >= p.Reserve {
+			return move(models.ActionTypeBuyProperty, map[string]interface{}{"propertyId": property.ID})
+		}
+		return move(models.ActionTypeDeclineProperty, nil)
+	case !turn.HasRolled:
+		return move(models.ActionTypeRollDice, nil)
+	}
+
+	if p.Builds {
+		if property := p.buildable(game, player); property != nil {
+			return move(models.ActionTypeBuildEngagement, map[string]interface{}{"propertyId": property.ID})
+		}
+	}
+	return move(models.ActionTypeEndTurn, nil)
+}
+
+// nextBid returns the bid the bot places in a running auction, if any: the smallest
+// raise, as long as it stays within the bot's limit and reserve
+func (p Profile) nextBid(game *models.Game, player *models.Player, auction *models.Auction) (int, bool) {
+	if p.MaxBid == 0 {
+		return 0, false
+	}
+	property := findProperty(game, auction.PropertyID)
+	if property == nil {
+		return 0, false
+	}
+	amount := auction.MinIncrement
+	if len(auction.Bids) > 0 {
+		last := auction.Bids[len(auction.Bids)-1]
+		if last.PlayerID == player.ID {
+			return 0, false
+		}
+		amount = last.Amount + auction.MinIncrement
+	}
+	if amount > rules.MarketPrice(game, property)*p.MaxBid/100 || player.Balance-amount < p.Reserve {
+		return 0, false
+	}
+	return amount, true
+}
+
+// buildable returns a property the bot can put an engagement on while keeping its reserve
+func (p Profile) buildable(game *models.Game, player *models.Player) *models.Property {
+	for i := range game.BoardState.Properties {
+		property := &game.BoardState.Properties[i]
+		if property.OwnerID != player.ID {
+			continue
+		}
+		if cost, err := rules.CanBuildEngagement(game, player, property); err == nil && player.Balance-cost >= p.Reserve {
+			return property
+		}
+	}
+	return nil
+}
+
+// wantsTrade weighs a trade offered to the bot: it must go through, leave the reserve
+// intact and gain at least the bot's margin on what it gives up
+func (p Profile) wantsTrade(game *models.Game, player *models.Player, trade *models.Trade) bool {
+	if rules.CanAcceptTrade(game, trade) != nil {
+		return false
+	}
+	if player.Balance-trade.Requested.Kekels+trade.Offered.Kekels < p.Reserve {
+		return false
+	}
+	gain := offerValue(game, player.ID, trade.Offered, trade.Offered.Properties)
+	cost := offerValue(game, player.ID, trade.Requested, nil)
+	return gain*100 >= cost*(100+p.TradeMargin)
+}
+
+// offerValue is what one side of a trade is worth to a player. Properties count at
+// their market price, halved when mortgaged and doubled when they complete (or would
+// break up) a whole group the player owns along with the incoming properties.
+func offerValue(game *models.Game, playerID string, offer models.TradeOffer, incoming []string) int {
+	value := offer.Kekels + len(offer.Cards)*cardValue
+	for _, propertyID := range offer.Properties {
+		property := findProperty(game, propertyID)
+		if property == nil {
+			continue
+		}
+		worth := rules.MarketPrice(game, property)
+		if property.Mortgaged {
+			worth /= 2
+		}
+		if ownsGroupWith(game, playerID, property.Group, incoming) {
+			worth *= 2
+		}
+		value += worth
+	}
+	return value
+}
+
+// ownsGroupWith reports whether a player owns every regular property in a group,
+// counting the properties they are about to receive
+func ownsGroupWith(game *models.Game, playerID, group string, incoming []string) bool {
+	if group == "" {
+		return false
+	}
+	found := false
+	for i := range game.BoardState.Properties {
+		property := &game.BoardState.Properties[i]
+		if property.Group != group || property.Type != models.PropertyTypeRegular {
+			continue
+		}
+		if property.OwnerID != playerID && !contains(incoming, property.ID) {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// mortgageable returns a property the player could mortgage to raise money
+func mortgageable(game *models.Game, playerID string) *models.Property {
+	for i := range game.BoardState.Properties {
+		property := &game.BoardState.Properties[i]
+		if property.OwnerID == playerID && rules.CanMortgage(game, property) == nil {
+			return property
+		}
+	}
+	return nil
+}
+
+func contains(ids []string, id string) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
+func findPlayer(game *models.Game, playerID string) *models.Player {
+	for i := range game.Players {
+		if game.Players[i].ID == playerID {
+			return &game.Players[i]
+		}
+	}
+	return nil
+}
+
+func findProperty(game *models.Game, propertyID string) *models.Property {
+	for i := range game.BoardState.Properties {
+		if game.BoardState.Properties[i].ID == propertyID {
+			return &game.BoardState.Properties[i]
+		}
+	}
+	return nil
+}
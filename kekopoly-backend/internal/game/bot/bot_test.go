@@ -0,0 +1,178 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rules"
+)
+
+func newGame() *models.Game {
+	return &models.Game{
+		ID:          primitive.NewObjectID(),
+		Status:      models.GameStatusActive,
+		CurrentTurn: "p1",
+		TurnOrder:   []string{"p1", "p2"},
+		Players: []models.Player{
+			{ID: "p1", Balance: 1500, Status: models.PlayerStatusActive},
+			{ID: "p2", Balance: 1500, Status: models.PlayerStatusActive},
+		},
+		BoardState: models.BoardState{
+			Properties: []models.Property{
+				{ID: "prop-4", Position: 4, Type: models.PropertyTypeRegular, Group: "brown", Price: 100, RentBase: 10},
+				{ID: "prop-6", Position: 6, Type: models.PropertyTypeRegular, Group: "blue", Price: 200, RentBase: 20},
+			},
+		},
+	}
+}
+
+func nextType(t *testing.T, game *models.Game, playerID string) models.ActionType {
+	t.Helper()
+	action, ok := Conservative.NextAction(game, playerID)
+	require.True(t, ok)
+	assert.Equal(t, playerID, action.PlayerID)
+	return action.Type
+}
+
+func TestConservativeBotPlaysItsTurnOut(t *testing.T) {
+	game := newGame()
+
+	_, ok := Conservative.NextAction(game, "p2")
+	assert.False(t, ok)
+
+	assert.Equal(t, models.ActionTypeRollDice, nextType(t, game, "p1"))
+
+	game.TurnState.HasRolled = true
+	game.TurnState.PendingDraw = models.CardTypeMeme
+	assert.Equal(t, models.ActionTypeDrawCard, nextType(t, game, "p1"))
+
+	game.TurnState.PendingDraw = ""
+	game.TurnState.PendingCard = &models.Card{ID: "meme_01"}
+	assert.Equal(t, models.ActionTypeUseCard, nextType(t, game, "p1"))
+
+	game.TurnState.PendingCard = nil
+	assert.Equal(t, models.ActionTypeEndTurn, nextType(t, game, "p1"))
+
+	game.TurnState.Auction = &models.Auction{PropertyID: "prop-4"}
+	_, ok = Conservative.NextAction(game, "p1")
+	assert.False(t, ok)
+}
+
+func TestConservativeBotKeepsAReserve(t *testing.T) {
+	game := newGame()
+	game.TurnState = models.TurnState{HasRolled: true, PendingPurchase: "prop-6"}
+
+	assert.Equal(t, models.ActionTypeBuyProperty, nextType(t, game, "p1"))
+
+	game.Players[0].Balance = Conservative.Reserve + 199
+	assert.Equal(t, models.ActionTypeDeclineProperty, nextType(t, game, "p1"))
+}
+
+func TestConservativeBotRaisesMoneyForRent(t *testing.T) {
+	game := newGame()
+	game.Players[0].Balance = 30
+	game.BoardState.Properties[0].OwnerID = "p1"
+	game.TurnState = models.TurnState{HasRolled: true, PendingRent: &models.Debt{CreditorID: "p2", Amount: 60}}
+
+	action, ok := Conservative.NextAction(game, "p1")
+	require.True(t, ok)
+	assert.Equal(t, models.ActionTypeMortgageProperty, action.Type)
+	assert.Equal(t, map[string]interface{}{"propertyId": "prop-4"}, action.Payload)
+
+	game.BoardState.Properties[0].Mortgaged = true
+	assert.Equal(t, models.ActionTypeDeclareBankruptcy, nextType(t, game, "p1"))
+
+	game.Players[0].Balance = 60
+	assert.Equal(t, models.ActionTypePayRent, nextType(t, game, "p1"))
+}
+
+func TestDifficultyPicksTheProfile(t *testing.T) {
+	assert.Equal(t, Aggressive, For(&models.Player{IsBot: true, BotDifficulty: models.BotDifficultyAggressive}))
+	assert.Equal(t, Conservative, For(&models.Player{AFK: true}))
+}
+
+func TestBotsBidUpToTheirLimit(t *testing.T) {
+	game := newGame()
+	game.TurnState = models.TurnState{HasRolled: true, Auction: &models.Auction{PropertyID: "prop-6", MinIncrement: 10}}
+
+	// Bots bid in auctions whoever's turn it is
+	action, ok := Balanced.NextAction(game, "p2")
+	require.True(t, ok)
+	assert.Equal(t, models.ActionTypePlaceBid, action.Type)
+	assert.Equal(t, map[string]interface{}{"amount": 10}, action.Payload)
+
+	game.TurnState.Auction.Bids = []models.Bid{{PlayerID: "p2", Amount: 10}}
+	_, ok = Balanced.NextAction(game, "p2")
+	assert.False(t, ok, "the highest bidder does not raise itself")
+
+	game.TurnState.Auction.Bids = []models.Bid{{PlayerID: "p1", Amount: 200}}
+	_, ok = Balanced.NextAction(game, "p2")
+	assert.False(t, ok, "balanced bots stop at the market price")
+	action, ok = Aggressive.NextAction(game, "p2")
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"amount": 210}, action.Payload)
+}
+
+func TestBotsBuildOnWholeGroups(t *testing.T) {
+	game := newGame()
+	game.BoardState.Properties[0].OwnerID = "p1"
+	game.TurnState.HasRolled = true
+
+	action, ok := Balanced.NextAction(game, "p1")
+	require.True(t, ok)
+	assert.Equal(t, models.ActionTypeBuildEngagement, action.Type)
+	assert.Equal(t, map[string]interface{}{"propertyId": "prop-4"}, action.Payload)
+
+	game.Players[0].Balance = Cautious.Reserve + 59
+	action, ok = Cautious.NextAction(game, "p1")
+	require.True(t, ok)
+	assert.Equal(t, models.ActionTypeEndTurn, action.Type)
+}
+
+func TestBotsWeighTradeOffers(t *testing.T) {
+	game := newGame()
+	game.BoardState.Properties[1].OwnerID = "p1"
+	game.Trades = []models.Trade{{
+		ID:          "trade-1",
+		ProposerID:  "p1",
+		RecipientID: "p2",
+		Offered:     models.TradeOffer{Properties: []string{"prop-6"}},
+		Requested:   models.TradeOffer{Kekels: 210},
+		Status:      models.TradeStatusPending,
+	}}
+
+	respond := func(profile Profile) string {
+		action, ok := profile.NextAction(game, "p2")
+		require.True(t, ok)
+		assert.Equal(t, models.ActionTypeTrade, action.Type)
+		return action.Payload.(map[string]interface{})["response"].(string)
+	}
+
+	// prop-6 completes the blue group for p2, which doubles its worth to them
+	assert.Equal(t, rules.TradeResponseAccept, respond(Cautious))
+	game.Trades[0].Requested.Kekels = 360
+	assert.Equal(t, rules.TradeResponseReject, respond(Cautious))
+	assert.Equal(t, rules.TradeResponseAccept, respond(Balanced))
+
+	_, ok := Conservative.NextAction(game, "p2")
+	assert.False(t, ok, "stand-ins leave trades to expire")
+
+	game.Players[1].ShadowbanRemainingTurns = 2
+	_, ok = Balanced.NextAction(game, "p2")
+	assert.False(t, ok, "shadowbanned bots cannot trade")
+}
+
+func TestBotsPlayCardsAimedAtThemselves(t *testing.T) {
+	game := newGame()
+	game.BoardState.Properties[0].OwnerID = "p1"
+	game.TurnState = models.TurnState{HasRolled: true, PendingCard: &models.Card{ID: "meme_08"}}
+
+	action, ok := Conservative.NextAction(game, "p1")
+	require.True(t, ok)
+	assert.Equal(t, models.ActionTypeUseCard, action.Type)
+	assert.Equal(t, map[string]interface{}{"propertyId": "prop-4"}, action.Payload)
+}
@@ -0,0 +1,289 @@
+// Package cards holds the Kekopoly card catalog and the decks cards are drawn from.
+// What a card does to a game is decided by the rules engine.
+package cards
+
+import (
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// Effect identifies what a card does when it is played
+type Effect string
+
+const (
+	EffectCollectFromAll            Effect = "COLLECT_FROM_ALL"
+	EffectAdvanceToStart            Effect = "ADVANCE_TO_START"
+	EffectPayNextPlayer             Effect = "PAY_NEXT_PLAYER"
+	EffectCollectFromPlayer         Effect = "COLLECT_FROM_PLAYER"
+	EffectAdvanceToAnyProperty      Effect = "ADVANCE_TO_ANY_PROPERTY"
+	EffectGoBack                    Effect = "GO_BACK"
+	EffectPropertyImmunity          Effect = "PROPERTY_IMMUNITY"
+	EffectForceMortgage             Effect = "FORCE_MORTGAGE"
+	EffectCollectPerProperty        Effect = "COLLECT_PER_PROPERTY"
+	EffectMustBuyNext               Effect = "MUST_BUY_NEXT"
+	EffectTeleport                  Effect = "TELEPORT"
+	EffectSwapPosition              Effect = "SWAP_POSITION"
+	EffectVoteForfeit               Effect = "VOTE_FORFEIT"
+	EffectCopyLastCard              Effect = "COPY_LAST_CARD"
+	EffectLowestRollPays            Effect = "LOWEST_ROLL_PAYS"
+	EffectCollectForBuildings       Effect = "COLLECT_FOR_BUILDINGS"
+	EffectPayPerEngagement          Effect = "PAY_PER_ENGAGEMENT"
+	EffectDoubleRentAgainst         Effect = "DOUBLE_RENT_AGAINST"
+	EffectBuyAnyProperty            Effect = "BUY_ANY_PROPERTY"
+	EffectGoToShadowban             Effect = "GO_TO_SHADOWBAN"
+	EffectLoseHalfKekels            Effect = "LOSE_HALF_KEKELS"
+	EffectDoubleRent                Effect = "DOUBLE_RENT"
+	EffectForcePositionSwap         Effect = "FORCE_POSITION_SWAP"
+	EffectPayPercentage             Effect = "PAY_PERCENTAGE"
+	EffectGetOutOfShadowban         Effect = "GET_OUT_OF_SHADOWBAN"
+	EffectRollCollect               Effect = "ROLL_COLLECT"
+	EffectAllForfeitPercentage      Effect = "ALL_FORFEIT_PERCENTAGE"
+	EffectStealAndShadowban         Effect = "STEAL_AND_SHADOWBAN"
+	EffectMoveRandom                Effect = "MOVE_RANDOM"
+	EffectCollectPerPropertyFromAll Effect = "COLLECT_PER_PROPERTY_FROM_ALL"
+	EffectMoveToAnySpace            Effect = "MOVE_TO_ANY_SPACE"
+	EffectPayPerProperty            Effect = "PAY_PER_PROPERTY"
+	EffectFreezePlayers             Effect = "FREEZE_PLAYERS"
+	EffectExchangeProperty          Effect = "EXCHANGE_PROPERTY"
+	EffectFreeEngagement            Effect = "FREE_ENGAGEMENT"
+	EffectBuyAnyPropertyDiscount    Effect = "BUY_ANY_PROPERTY_DISCOUNT"
+	EffectRemoveAllEngagements      Effect = "REMOVE_ALL_ENGAGEMENTS"
+	EffectViewCards                 Effect = "VIEW_CARDS"
+	EffectCollectAllRent            Effect = "COLLECT_ALL_RENT"
+	EffectStealCard                 Effect = "STEAL_CARD"
+)
+
+// Target says who a card aimed at a single other player affects
+type Target string
+
+const (
+	TargetSelf     Target = "SELF"     // The player who played the card
+	TargetPrevious Target = "PREVIOUS" // The previous player in turn order
+	TargetChosen   Target = "CHOSEN"   // A player chosen when the card is played
+)
+
+// Timing says when a card kept in a player's hand may be played
+type Timing string
+
+const (
+	TimingOwnTurn Timing = "OWN_TURN" // Only during the holder's turn
+	TimingAnyTime Timing = "ANY_TIME" // At any moment, including other players' turns
+	TimingRentDue Timing = "RENT_DUE" // During the holder's turn, or as a reaction while another player owes them rent
+)
+
+// Params are the numbers a card's effect works with. Which fields apply depends on the effect.
+type Params struct {
+	Amount       int    `json:"amount,omitempty"`       // Flat amount of kekels
+	PerUnit      int    `json:"perUnit,omitempty"`      // Kekels per property, building, engagement or pip
+	Percent      int    `json:"percent,omitempty"`      // Share of a balance
+	PricePercent int    `json:"pricePercent,omitempty"` // Share of a property's price paid to take it
+	Rounds       int    `json:"rounds,omitempty"`       // How long a lasting effect stays active
+	Spaces       int    `json:"spaces,omitempty"`       // Spaces to move
+	Dice         int    `json:"dice,omitempty"`         // Dice rolled
+	Count        int    `json:"count,omitempty"`        // Cards revealed
+	Target       Target `json:"target,omitempty"`
+}
+
+// Definition is a card in the catalog
+type Definition struct {
+	ID          string            `json:"cardId"`
+	Name        string            `json:"name"`
+	Type        models.CardType   `json:"type"`
+	Rarity      models.CardRarity `json:"rarity"`
+	Effect      Effect            `json:"effect"`
+	Description string            `json:"description"`
+	Params      Params            `json:"params"`
+	Kept        bool              `json:"kept,omitempty"`   // Goes into the player's hand when drawn instead of taking effect
+	Timing      Timing            `json:"timing,omitempty"` // When a kept card may be played
+}
+
+// Card returns the card as it is held in a player's hand
+func (d Definition) Card() models.Card {
+	return models.Card{
+		ID:          d.ID,
+		Name:        d.Name,
+		Type:        d.Type,
+		Rarity:      d.Rarity,
+		Effect:      string(d.Effect),
+		Description: d.Description,
+		Timing:      string(d.Timing),
+	}
+}
+
+// choiceEffects are the effects that need the player to pick a player, property,
+// space, deck or card before they can be applied
+var choiceEffects = map[Effect]bool{
+	EffectAdvanceToAnyProperty:   true,
+	EffectForceMortgage:          true,
+	EffectTeleport:               true,
+	EffectSwapPosition:           true,
+	EffectCopyLastCard:           true,
+	EffectBuyAnyProperty:         true,
+	EffectForcePositionSwap:      true,
+	EffectMoveToAnySpace:         true,
+	EffectExchangeProperty:       true,
+	EffectFreeEngagement:         true,
+	EffectBuyAnyPropertyDiscount: true,
+	EffectViewCards:              true,
+	EffectStealCard:              true,
+}
+
+// RequiresChoice reports whether the card waits for the player's choices before it is applied
+func (d Definition) RequiresChoice() bool {
+	return choiceEffects[d.Effect]
+}
+
+// Optional reports whether the player may pass on the card instead of applying it.
+// Cards aimed at the player themselves must be applied.
+func (d Definition) Optional() bool {
+	return d.Params.Target != TargetSelf
+}
+
+// catalog is every card in the game, matching the frontend's CardManager
+var catalog = []Definition{
+	{ID: "meme_01", Name: "Viral Meme", Type: models.CardTypeMeme, Rarity: models.CardRarityRare, Effect: EffectCollectFromAll,
+		Description: "Collect 50 Kekels from each player", Params: Params{Amount: 50}},
+	{ID: "meme_02", Name: "Stonks", Type: models.CardTypeMeme, Rarity: models.CardRarityCommon, Effect: EffectAdvanceToStart,
+		Description: "Advance to START and collect 200 Kekels", Params: Params{Amount: 200}},
+	{ID: "meme_03", Name: "Wojak Panic", Type: models.CardTypeMeme, Rarity: models.CardRarityCommon, Effect: EffectPayNextPlayer,
+		Description: "Pay 50 Kekels to the next player", Params: Params{Amount: 50}},
+	{ID: "meme_04", Name: "Doge WOW", Type: models.CardTypeMeme, Rarity: models.CardRarityRare, Effect: EffectCollectFromPlayer,
+		Description: "Collect 200 Kekels from the previous player", Params: Params{Amount: 200, Target: TargetPrevious}},
+	{ID: "meme_05", Name: "Chad Yes", Type: models.CardTypeMeme, Rarity: models.CardRarityRare, Effect: EffectAdvanceToAnyProperty,
+		Description: "Advance to any property and buy it if unowned"},
+	{ID: "meme_06", Name: "Pepe Sad", Type: models.CardTypeMeme, Rarity: models.CardRarityCommon, Effect: EffectGoBack,
+		Description: "Go back 3 spaces", Params: Params{Spaces: 3}},
+	{ID: "meme_07", Name: "Diamond Hands", Type: models.CardTypeMeme, Rarity: models.CardRarityLegendary, Effect: EffectPropertyImmunity,
+		Description: "All your properties are immune to being stolen or devalued for 3 rounds", Params: Params{Rounds: 3},
+		Kept: true, Timing: TimingAnyTime},
+	{ID: "meme_08", Name: "Paper Hands", Type: models.CardTypeMeme, Rarity: models.CardRarityCommon, Effect: EffectForceMortgage,
+		Description: "You must mortgage one property if possible", Params: Params{Target: TargetSelf}},
+	{ID: "meme_09", Name: "NFT Collection", Type: models.CardTypeMeme, Rarity: models.CardRarityRare, Effect: EffectCollectPerProperty,
+		Description: "Collect 25 Kekels for each property you own", Params: Params{PerUnit: 25}},
+	{ID: "meme_10", Name: "FOMO", Type: models.CardTypeMeme, Rarity: models.CardRarityCommon, Effect: EffectMustBuyNext,
+		Description: "You must buy the next unowned property you land on"},
+	{ID: "meme_11", Name: "Galaxy Brain", Type: models.CardTypeMeme, Rarity: models.CardRarityLegendary, Effect: EffectTeleport,
+		Description: "Choose any space on the board and teleport there"},
+	{ID: "meme_12", Name: "Bait and Switch", Type: models.CardTypeMeme, Rarity: models.CardRarityRare, Effect: EffectSwapPosition,
+		Description: "Swap positions with any player"},
+	{ID: "meme_13", Name: "Ratio'd", Type: models.CardTypeMeme, Rarity: models.CardRarityCommon, Effect: EffectVoteForfeit,
+		Description: "All players vote on a player who must forfeit 50 Kekels", Params: Params{Amount: 50}},
+	{ID: "meme_14", Name: "Copypasta", Type: models.CardTypeMeme, Rarity: models.CardRarityRare, Effect: EffectCopyLastCard,
+		Description: "Copy the effect of any card played in the last round"},
+	{ID: "meme_15", Name: "Shitposting", Type: models.CardTypeMeme, Rarity: models.CardRarityCommon, Effect: EffectLowestRollPays,
+		Description: "All players must roll a die - lowest number pays 50 Kekels to you", Params: Params{Amount: 50}},
+	{ID: "meme_16", Name: "Meme Review", Type: models.CardTypeMeme, Rarity: models.CardRarityLegendary, Effect: EffectCollectForBuildings,
+		Description: "Collect 100 Kekels and an additional 25 for each Engagement/Blue Checkmark you own", Params: Params{Amount: 100, PerUnit: 25}},
+
+	{ID: "redpill_01", Name: "Based", Type: models.CardTypeRedpill, Rarity: models.CardRarityRare, Effect: EffectCollectFromAll,
+		Description: "Collect 150 Kekels from all the players", Params: Params{Amount: 150}},
+	{ID: "redpill_02", Name: "Cringe", Type: models.CardTypeRedpill, Rarity: models.CardRarityCommon, Effect: EffectPayNextPlayer,
+		Description: "Pay 150 Kekels to the next player", Params: Params{Amount: 150}},
+	{ID: "redpill_03", Name: "Server Maintenance", Type: models.CardTypeRedpill, Rarity: models.CardRarityCommon, Effect: EffectPayPerEngagement,
+		Description: "Pay 40 Kekels for each Engagement you own", Params: Params{PerUnit: 40}},
+	{ID: "redpill_04", Name: "Doxx'd", Type: models.CardTypeRedpill, Rarity: models.CardRarityCommon, Effect: EffectDoubleRentAgainst,
+		Description: "Your position is revealed to all players who can charge you double rent for 2 rounds", Params: Params{Rounds: 2}},
+	{ID: "redpill_05", Name: "Crypto Whale", Type: models.CardTypeRedpill, Rarity: models.CardRarityLegendary, Effect: EffectBuyAnyProperty,
+		Description: "Take ownership of any one property by paying twice its value to the owner", Params: Params{PricePercent: 200}},
+	{ID: "redpill_06", Name: "Shadowbanned", Type: models.CardTypeRedpill, Rarity: models.CardRarityCommon, Effect: EffectGoToShadowban,
+		Description: "Go directly to Shadowban"},
+	{ID: "redpill_07", Name: "Crypto Winter", Type: models.CardTypeRedpill, Rarity: models.CardRarityRare, Effect: EffectLoseHalfKekels,
+		Description: "Lose half your Kekels (rounded down)"},
+	{ID: "redpill_08", Name: "HODL", Type: models.CardTypeRedpill, Rarity: models.CardRarityRare, Effect: EffectDoubleRent,
+		Description: "All your properties generate double rent for one round", Params: Params{Rounds: 1},
+		Kept: true, Timing: TimingRentDue},
+	{ID: "redpill_09", Name: "Trollface", Type: models.CardTypeRedpill, Rarity: models.CardRarityCommon, Effect: EffectForcePositionSwap,
+		Description: "Force another player to swap positions with any player of your choice"},
+	{ID: "redpill_10", Name: "Ratio'd", Type: models.CardTypeRedpill, Rarity: models.CardRarityCommon, Effect: EffectPayPercentage,
+		Description: "Pay 10% of your Kekels to the player with the least amount", Params: Params{Percent: 10}},
+	{ID: "redpill_11", Name: "Verification Check", Type: models.CardTypeRedpill, Rarity: models.CardRarityRare, Effect: EffectGetOutOfShadowban,
+		Description: "Get out of Shadowban free card", Kept: true, Timing: TimingAnyTime},
+	{ID: "redpill_12", Name: "Airdrop", Type: models.CardTypeRedpill, Rarity: models.CardRarityCommon, Effect: EffectRollCollect,
+		Description: "Roll a die and collect that many x25 Kekels", Params: Params{Dice: 1, PerUnit: 25}},
+	{ID: "redpill_13", Name: "Flash Crash", Type: models.CardTypeRedpill, Rarity: models.CardRarityLegendary, Effect: EffectAllForfeitPercentage,
+		Description: "All players must forfeit 20% of their cash", Params: Params{Percent: 20}},
+	{ID: "redpill_14", Name: "Exit Scam", Type: models.CardTypeRedpill, Rarity: models.CardRarityRare, Effect: EffectStealAndShadowban,
+		Description: "Steal 50 Kekels from each player but go to Shadowban immediately", Params: Params{Amount: 50}},
+	{ID: "redpill_15", Name: "Token Unlock", Type: models.CardTypeRedpill, Rarity: models.CardRarityCommon, Effect: EffectCollectFromAll,
+		Description: "Collect 50 Kekels from all the players", Params: Params{Amount: 50}},
+	{ID: "redpill_16", Name: "Rugpull", Type: models.CardTypeRedpill, Rarity: models.CardRarityLegendary, Effect: EffectForceMortgage,
+		Description: "Force one player to mortgage one of their properties of your choice", Params: Params{Target: TargetChosen}},
+
+	{ID: "eegi_01", Name: "AI Generated", Type: models.CardTypeEegi, Rarity: models.CardRarityCommon, Effect: EffectMoveRandom,
+		Description: "Move to a random space on the board"},
+	{ID: "eegi_02", Name: "Neural Network", Type: models.CardTypeEegi, Rarity: models.CardRarityRare, Effect: EffectCollectPerPropertyFromAll,
+		Description: "Collect 10 Kekels from each player for each property you own", Params: Params{PerUnit: 10}},
+	{ID: "eegi_03", Name: "Prompt Engineering", Type: models.CardTypeEegi, Rarity: models.CardRarityRare, Effect: EffectMoveToAnySpace,
+		Description: "Choose any space on the board and move there"},
+	{ID: "eegi_04", Name: "GPT Hallucination", Type: models.CardTypeEegi, Rarity: models.CardRarityCommon, Effect: EffectPayNextPlayer,
+		Description: "Pay 100 Kekels to the next player", Params: Params{Amount: 100}},
+	{ID: "eegi_05", Name: "LLM Genius", Type: models.CardTypeEegi, Rarity: models.CardRarityRare, Effect: EffectCollectFromPlayer,
+		Description: "Collect 200 Kekels from the previous player", Params: Params{Amount: 200, Target: TargetPrevious}},
+	{ID: "eegi_06", Name: "Token Limit", Type: models.CardTypeEegi, Rarity: models.CardRarityCommon, Effect: EffectPayPerProperty,
+		Description: "Pay 10 Kekels per property you own", Params: Params{PerUnit: 10}},
+	{ID: "eegi_07", Name: "Stable Diffusion", Type: models.CardTypeEegi, Rarity: models.CardRarityLegendary, Effect: EffectFreezePlayers,
+		Description: "All players' tokens freeze in place for one round", Params: Params{Rounds: 1}},
+	{ID: "eegi_08", Name: "Transformer", Type: models.CardTypeEegi, Rarity: models.CardRarityRare, Effect: EffectExchangeProperty,
+		Description: "Exchange one property with any player"},
+	{ID: "eegi_09", Name: "Fine-Tuning", Type: models.CardTypeEegi, Rarity: models.CardRarityCommon, Effect: EffectFreeEngagement,
+		Description: "Add one Engagement to any property you own for free"},
+	{ID: "eegi_10", Name: "Generative Art", Type: models.CardTypeEegi, Rarity: models.CardRarityRare, Effect: EffectBuyAnyPropertyDiscount,
+		Description: "Go to any property and buy it at 75% of its listed price", Params: Params{PricePercent: 75}},
+	{ID: "eegi_11", Name: "Zero-shot Learning", Type: models.CardTypeEegi, Rarity: models.CardRarityRare, Effect: EffectGetOutOfShadowban,
+		Description: "Get out of Shadowban free", Kept: true, Timing: TimingAnyTime},
+	{ID: "eegi_12", Name: "Multimodal", Type: models.CardTypeEegi, Rarity: models.CardRarityCommon, Effect: EffectRollCollect,
+		Description: "Roll again and collect 20 Kekels for each dot shown", Params: Params{Dice: 2, PerUnit: 20}},
+	{ID: "eegi_13", Name: "Model Collapse", Type: models.CardTypeEegi, Rarity: models.CardRarityLegendary, Effect: EffectRemoveAllEngagements,
+		Description: "All players must remove one Engagement from each of their properties"},
+	{ID: "eegi_14", Name: "Training Data", Type: models.CardTypeEegi, Rarity: models.CardRarityCommon, Effect: EffectViewCards,
+		Description: "View the top 3 cards from any deck", Params: Params{Count: 3}},
+	{ID: "eegi_15", Name: "AGI Breakthrough", Type: models.CardTypeEegi, Rarity: models.CardRarityLegendary, Effect: EffectCollectAllRent,
+		Description: "Collect rent from all properties as if you owned them for one round", Params: Params{Rounds: 1}},
+	{ID: "eegi_16", Name: "Superintelligence", Type: models.CardTypeEegi, Rarity: models.CardRarityRare, Effect: EffectStealCard,
+		Description: "Look at all cards in any player's hand and take one"},
+}
+
+var byID = func() map[string]Definition {
+	m := make(map[string]Definition, len(catalog))
+	for _, def := range catalog {
+		m[def.ID] = def
+	}
+	return m
+}()
+
+// All returns every card in the catalog
+func All() []Definition {
+	return append([]Definition(nil), catalog...)
+}
+
+// Lookup finds a card in the catalog by ID
+func Lookup(id string) (Definition, bool) {
+	def, ok := byID[id]
+	return def, ok
+}
+
+// OfType returns the cards that make up one deck
+func OfType(cardType models.CardType) []Definition {
+	var defs []Definition
+	for _, def := range catalog {
+		if def.Type == cardType {
+			defs = append(defs, def)
+		}
+	}
+	return defs
+}
+
+// Types lists the decks in the game
+func Types() []models.CardType {
+	return []models.CardType{models.CardTypeMeme, models.CardTypeRedpill, models.CardTypeEegi}
+}
+
+// Weight is how likely a card of a rarity is to be drawn relative to the others
+func Weight(rarity models.CardRarity) int {
+	switch rarity {
+	case models.CardRarityLegendary:
+		return 1
+	case models.CardRarityRare:
+		return 3
+	}
+	return 6
+}
@@ -0,0 +1,90 @@
+package cards
+
+import (
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rng"
+)
+
+// Shuffle orders card IDs into a draw pile. Each position is filled by a weighted
+// draw from the cards not yet placed, so common cards tend to sit near the top of
+// the pile and legendary cards near the bottom.
+func Shuffle(r rng.Randomizer, ids []string) []string {
+	remaining := append([]string(nil), ids...)
+	pile := make([]string, 0, len(ids))
+
+	for len(remaining) > 0 {
+		total := 0
+		for _, id := range remaining {
+			total += weightOf(id)
+		}
+
+		pick := r.Intn(total)
+		i := 0
+		for ; i < len(remaining)-1; i++ {
+			pick -= weightOf(remaining[i])
+			if pick < 0 {
+				break
+			}
+		}
+
+		pile = append(pile, remaining[i])
+		remaining = append(remaining[:i], remaining[i+1:]...)
+	}
+	return pile
+}
+
+// weightOf returns the draw weight of a card by ID. Unknown cards count as common.
+func weightOf(id string) int {
+	if def, ok := Lookup(id); ok {
+		return Weight(def.Rarity)
+	}
+	return Weight(models.CardRarityCommon)
+}
+
+// NewDeck returns a freshly shuffled deck holding every card of a type
+func NewDeck(cardType models.CardType, r rng.Randomizer) *models.CardDeck {
+	defs := OfType(cardType)
+	ids := make([]string, len(defs))
+	for i, def := range defs {
+		ids[i] = def.ID
+	}
+	return &models.CardDeck{DrawPile: Shuffle(r, ids), DiscardPile: []string{}}
+}
+
+// Draw takes the top card of a deck. When the draw pile runs out the discard pile
+// is shuffled to form a new one, which Draw reports. It returns false when every
+// card of the deck is out in players' hands.
+func Draw(deck *models.CardDeck, r rng.Randomizer) (def Definition, reshuffled bool, ok bool) {
+	if len(deck.DrawPile) == 0 {
+		if len(deck.DiscardPile) == 0 {
+			return Definition{}, false, false
+		}
+		deck.DrawPile = Shuffle(r, deck.DiscardPile)
+		deck.DiscardPile = []string{}
+		reshuffled = true
+	}
+
+	id := deck.DrawPile[0]
+	deck.DrawPile = deck.DrawPile[1:]
+	def, ok = Lookup(id)
+	return def, reshuffled, ok
+}
+
+// Discard puts a played card on its deck's discard pile
+func Discard(deck *models.CardDeck, id string) {
+	deck.DiscardPile = append(deck.DiscardPile, id)
+}
+
+// Peek returns up to n cards from the top of a deck without drawing them
+func Peek(deck *models.CardDeck, n int) []Definition {
+	var defs []Definition
+	for _, id := range deck.DrawPile {
+		if len(defs) == n {
+			break
+		}
+		if def, ok := Lookup(id); ok {
+			defs = append(defs, def)
+		}
+	}
+	return defs
+}
@@ -0,0 +1,85 @@
+package cards
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rng"
+)
+
+func TestCatalogHasSixteenCardsPerDeck(t *testing.T) {
+	for _, cardType := range Types() {
+		assert.Len(t, OfType(cardType), 16, cardType)
+	}
+	for _, def := range All() {
+		assert.NotEmpty(t, def.Effect, def.ID)
+		found, ok := Lookup(def.ID)
+		require.True(t, ok, def.ID)
+		assert.Equal(t, def, found)
+	}
+}
+
+func TestNewDeckHoldsEveryCardOnce(t *testing.T) {
+	deck := NewDeck(models.CardTypeMeme, rng.NewSeeded(1))
+
+	var want []string
+	for _, def := range OfType(models.CardTypeMeme) {
+		want = append(want, def.ID)
+	}
+	got := append([]string(nil), deck.DrawPile...)
+	sort.Strings(got)
+	sort.Strings(want)
+	assert.Equal(t, want, got)
+	assert.Empty(t, deck.DiscardPile)
+}
+
+func TestShuffleIsReproducible(t *testing.T) {
+	first := NewDeck(models.CardTypeEegi, rng.NewSeeded(7))
+	second := NewDeck(models.CardTypeEegi, rng.NewSeeded(7))
+	assert.Equal(t, first.DrawPile, second.DrawPile)
+}
+
+func TestShuffleFavoursCommonCards(t *testing.T) {
+	r := rng.NewSeeded(3)
+	tops := map[models.CardRarity]int{}
+	for i := 0; i < 2000; i++ {
+		def, ok := Lookup(NewDeck(models.CardTypeRedpill, r).DrawPile[0])
+		require.True(t, ok)
+		tops[def.Rarity]++
+	}
+	assert.Greater(t, tops[models.CardRarityCommon], tops[models.CardRarityRare])
+	assert.Greater(t, tops[models.CardRarityRare], tops[models.CardRarityLegendary])
+}
+
+func TestDrawReshufflesDiscardPile(t *testing.T) {
+	deck := &models.CardDeck{DrawPile: []string{"meme_01"}, DiscardPile: []string{"meme_02", "meme_03"}}
+	r := rng.NewScripted(0)
+
+	def, reshuffled, ok := Draw(deck, r)
+	require.True(t, ok)
+	assert.False(t, reshuffled)
+	assert.Equal(t, "meme_01", def.ID)
+
+	_, reshuffled, ok = Draw(deck, r)
+	require.True(t, ok)
+	assert.True(t, reshuffled)
+	assert.Len(t, deck.DrawPile, 1)
+	assert.Empty(t, deck.DiscardPile)
+
+	Draw(deck, r)
+	_, _, ok = Draw(deck, r)
+	assert.False(t, ok)
+}
+
+func TestPeekLeavesDeckUntouched(t *testing.T) {
+	deck := &models.CardDeck{DrawPile: []string{"eegi_01", "eegi_02", "eegi_03", "eegi_04"}}
+
+	top := Peek(deck, 3)
+	require.Len(t, top, 3)
+	assert.Equal(t, "eegi_01", top[0].ID)
+	assert.Len(t, deck.DrawPile, 4)
+}
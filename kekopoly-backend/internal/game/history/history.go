@@ -0,0 +1,197 @@
+// Package history keeps the append-only record of a game: every accepted action and
+// countdown with the changes it made, numbered in order, so a client can catch up from
+// the last entry it saw and the whole game can be rebuilt by replaying the entries.
+package history
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rules"
+)
+
+// Kind says what an entry in a game's history records
+type Kind string
+
+const (
+	// KindSnapshot holds the whole game, as a starting point for the entries after it
+	KindSnapshot Kind = "SNAPSHOT"
+	// KindAction holds an action the rules accepted
+	KindAction Kind = "ACTION"
+	// KindDeadline holds a countdown that ran out, such as a turn clock or an auction
+	KindDeadline Kind = "DEADLINE"
+)
+
+// Event is one entry in a game's history
+type Event struct {
+	GameID    string             `bson:"gameId" json:"gameId"`
+	Seq       int                `bson:"seq" json:"seq"` // Position in the game's history, counting from one
+	Kind      Kind               `bson:"kind" json:"kind"`
+	Action    *models.GameAction `bson:"action,omitempty" json:"action,omitempty"`   // The accepted action of an ACTION entry
+	Changes   []rules.Event      `bson:"changes,omitempty" json:"changes,omitempty"` // What the action or countdown changed, as broadcast
+	State     *models.Game       `bson:"state,omitempty" json:"state,omitempty"`     // The whole game after a SNAPSHOT entry
+	Timestamp time.Time          `bson:"timestamp" json:"timestamp"`
+}
+
+// ErrNoSnapshot is returned when folding entries that do not start with a snapshot
+var ErrNoSnapshot = errors.New("history does not start with a snapshot")
+
+// Snapshot records the whole game as it stands. The state is copied, so later moves do
+// not change the entry.
+func Snapshot(game *models.Game, timestamp time.Time) (Event, error) {
+	state, err := copyGame(game)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{GameID: game.ID.Hex(), Kind: KindSnapshot, State: state, Timestamp: timestamp}, nil
+}
+
+// copyGame makes a deep copy of a game, as it would be stored
+func copyGame(game *models.Game) (*models.Game, error) {
+	data, err := bson.Marshal(game)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy game %s: %w", game.ID.Hex(), err)
+	}
+	var copied models.Game
+	if err := bson.Unmarshal(data, &copied); err != nil {
+		return nil, fmt.Errorf("failed to copy game %s: %w", game.ID.Hex(), err)
+	}
+	return &copied, nil
+}
+
+// Record turns the outcome of an accepted action into an entry. A nil action records a
+// countdown that ran out at the given time.
+func Record(gameID string, action *models.GameAction, result *rules.Result, timestamp time.Time) Event {
+	event := Event{GameID: gameID, Kind: KindDeadline, Changes: result.Events, Timestamp: timestamp}
+	if action != nil {
+		event.Kind = KindAction
+		event.Action = action
+	}
+	return event
+}
+
+// EngineFactory creates the rules engine that replays the entries after a snapshot,
+// with its randomizer resumed from the snapshot's draws
+type EngineFactory func(game *models.Game) (*rules.Engine, error)
+
+// Fold rebuilds a game by replaying its history in order. Every snapshot replaces the
+// game and its engine; every action and countdown is applied as it was when it happened.
+// The entries themselves are left untouched, so they can be folded again.
+func Fold(events []Event, newEngine EngineFactory) (*models.Game, error) {
+	if len(events) == 0 || events[0].Kind != KindSnapshot {
+		return nil, ErrNoSnapshot
+	}
+
+	var game *models.Game
+	var engine *rules.Engine
+	for i := range events {
+		event := &events[i]
+		if i > 0 && event.Seq != events[i-1].Seq+1 {
+			return nil, fmt.Errorf("history skips from %d to %d", events[i-1].Seq, event.Seq)
+		}
+
+		switch event.Kind {
+		case KindSnapshot:
+			if event.State == nil {
+				return nil, fmt.Errorf("snapshot %d has no state", event.Seq)
+			}
+			var err error
+			if game, err = copyGame(event.State); err != nil {
+				return nil, err
+			}
+			if engine, err = newEngine(game); err != nil {
+				return nil, fmt.Errorf("snapshot %d: %w", event.Seq, err)
+			}
+		case KindAction:
+			if event.Action == nil {
+				return nil, fmt.Errorf("action %d has no action", event.Seq)
+			}
+			if _, err := engine.Apply(game, *event.Action); err != nil {
+				return nil, fmt.Errorf("action %d: %w", event.Seq, err)
+			}
+		case KindDeadline:
+			result, err := engine.Tick(game, event.Timestamp)
+			if err != nil {
+				return nil, fmt.Errorf("deadline %d: %w", event.Seq, err)
+			}
+			if result == nil {
+				return nil, fmt.Errorf("deadline %d: nothing was due", event.Seq)
+			}
+		default:
+			return nil, fmt.Errorf("entry %d has unknown kind %q", event.Seq, event.Kind)
+		}
+		game.EventSeq = event.Seq
+	}
+	return game, nil
+}
+
+// VisibleTo returns the entries as a player may see them, without the private changes
+// meant for other players or the other players' hands in snapshots
+func VisibleTo(events []Event, playerID string) []Event {
+	visible := make([]Event, 0, len(events))
+	for _, event := range events {
+		if event.State != nil {
+			event.State = event.State.VisibleTo(playerID)
+		}
+		changes := make([]rules.Event, 0, len(event.Changes))
+		for _, change := range event.Changes {
+			if change.Recipient == "" || change.Recipient == playerID {
+				changes = append(changes, change)
+			}
+		}
+		event.Changes = changes
+		visible = append(visible, event)
+	}
+	return visible
+}
+
+// Restore turns the documents, arrays and numbers the database hands back in action
+// payloads and change data into the plain maps, slices and ints the rules and clients
+// expect, as if the values had just arrived over the wire
+func (e *Event) Restore() {
+	if e.Action != nil {
+		e.Action.Payload = plain(e.Action.Payload)
+	}
+	for i := range e.Changes {
+		if e.Changes[i].Data != nil {
+			e.Changes[i].Data = plain(e.Changes[i].Data).(map[string]interface{})
+		}
+	}
+}
+
+func plain(value interface{}) interface{} {
+	switch v := value.(type) {
+	case primitive.D:
+		m := make(map[string]interface{}, len(v))
+		for _, elem := range v {
+			m[elem.Key] = plain(elem.Value)
+		}
+		return m
+	case primitive.M:
+		return plain(map[string]interface{}(v))
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = plain(item)
+		}
+		return m
+	case primitive.A:
+		return plain([]interface{}(v))
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, item := range v {
+			s[i] = plain(item)
+		}
+		return s
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	}
+	return value
+}
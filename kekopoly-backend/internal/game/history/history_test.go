@@ -0,0 +1,258 @@
+package history
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/kekopoly/backend/internal/game/board"
+	"github.com/kekopoly/backend/internal/game/bot"
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rng"
+	"github.com/kekopoly/backend/internal/game/rules"
+)
+
+var testSeed = []byte("history-test-seed")
+
+func testBoard(t *testing.T) *board.Board {
+	b, err := board.NewRegistry().Get("")
+	require.NoError(t, err)
+	return b
+}
+
+func newEngineFactory(b *board.Board) EngineFactory {
+	return func(game *models.Game) (*rules.Engine, error) {
+		return rules.NewEngine(b, rng.NewHMAC(testSeed, game.RNGDraws)), nil
+	}
+}
+
+// newBotGame starts a game between three bots, as the game manager would
+func newBotGame(b *board.Board, engine *rules.Engine, now time.Time) *models.Game {
+	game := &models.Game{
+		ID:     primitive.NewObjectID(),
+		Status: models.GameStatusActive,
+		BoardState: models.BoardState{
+			Properties:     b.NewProperties(),
+			BuildingSupply: rules.NewBuildingSupply(),
+		},
+		MarketCondition:    models.MarketConditionNormal,
+		TurnTimeoutSeconds: 120,
+		Round:              1,
+	}
+	for _, id := range []string{"p1", "p2", "p3"} {
+		game.Players = append(game.Players, models.Player{
+			ID:            id,
+			Status:        models.PlayerStatusActive,
+			Balance:       1500,
+			Position:      b.StartPosition(),
+			Cards:         []models.Card{},
+			Properties:    []string{},
+			IsBot:         true,
+			BotDifficulty: models.BotDifficultyBalanced,
+		})
+		game.TurnOrder = append(game.TurnOrder, id)
+	}
+	engine.SetupDecks(game)
+	game.CurrentTurn = game.TurnOrder[0]
+	rules.StartClock(game, now)
+	return game
+}
+
+// recorder keeps a game's history as the game manager does, storing every entry the
+// way the database would hand it back
+type recorder struct {
+	t      *testing.T
+	game   *models.Game
+	events []Event
+}
+
+func (r *recorder) append(event Event) {
+	r.game.EventSeq++
+	event.Seq = r.game.EventSeq
+	if event.State != nil {
+		event.State.EventSeq = event.Seq
+	}
+
+	data, err := bson.Marshal(event)
+	require.NoError(r.t, err)
+	var stored Event
+	require.NoError(r.t, bson.Unmarshal(data, &stored))
+	stored.Restore()
+	r.events = append(r.events, stored)
+}
+
+func (r *recorder) snapshot(now time.Time) {
+	event, err := Snapshot(r.game, now)
+	require.NoError(r.t, err)
+	r.append(event)
+}
+
+func jsonOf(t *testing.T, game *models.Game) string {
+	data, err := json.Marshal(game)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestFoldRebuildsGame(t *testing.T) {
+	b := testBoard(t)
+	engine := newEngineFactory(b)
+	now := time.Unix(1700000000, 0).UTC()
+	live, err := engine(&models.Game{})
+	require.NoError(t, err)
+	game := newBotGame(b, live, now)
+	log := &recorder{t: t, game: game}
+	log.snapshot(now)
+
+	// A trade carries nested payload values and an ID its answer refers back to
+	now = now.Add(time.Second)
+	gift := models.GameAction{Type: models.ActionTypeTrade, PlayerID: "p1", GameID: game.ID.Hex(), Timestamp: now, Payload: map[string]interface{}{
+		"recipientId": "p2",
+		"offered":     map[string]interface{}{"kekels": float64(10)},
+		"requested":   map[string]interface{}{},
+	}}
+	result, err := live.Apply(game, gift)
+	require.NoError(t, err)
+	log.append(Record(game.ID.Hex(), &gift, result, now))
+
+	isBot := func(player *models.Player) bool { return player.IsBot }
+	actions, deadlines := 0, 0
+	for step := 0; step < 400 && game.Status == models.GameStatusActive; step++ {
+		if step == 200 {
+			// A change made outside the rules is recorded as a new starting point
+			game.Players[2].Timeouts = 0
+			log.snapshot(now)
+		}
+
+		if action, ok := bot.NextMove(game, isBot); ok {
+			now = now.Add(time.Second)
+			action.Timestamp = now
+			action.ByBot = true
+			if result, err := live.Apply(game, action); err == nil {
+				log.append(Record(game.ID.Hex(), &action, result, now))
+				actions++
+				continue
+			}
+		}
+
+		deadline, ok := rules.NextDeadline(game)
+		require.True(t, ok, "game stalled")
+		if deadline.After(now) {
+			now = deadline
+		}
+		result, err := live.Tick(game, now)
+		require.NoError(t, err)
+		if result != nil {
+			log.append(Record(game.ID.Hex(), nil, result, now))
+			deadlines++
+		}
+	}
+	require.Positive(t, actions)
+
+	rebuilt, err := Fold(log.events, engine)
+	require.NoError(t, err)
+	assert.Equal(t, jsonOf(t, game), jsonOf(t, rebuilt))
+	assert.Equal(t, len(log.events), rebuilt.EventSeq)
+
+	// Folding from the later snapshot gives the same game
+	var later []Event
+	for i, event := range log.events {
+		if i > 0 && event.Kind == KindSnapshot {
+			later = log.events[i:]
+		}
+	}
+	require.NotEmpty(t, later)
+	rebuilt, err = Fold(later, engine)
+	require.NoError(t, err)
+	assert.Equal(t, jsonOf(t, game), jsonOf(t, rebuilt))
+}
+
+func TestFoldRejectsBrokenHistory(t *testing.T) {
+	engine := newEngineFactory(testBoard(t))
+	game := &models.Game{ID: primitive.NewObjectID()}
+	action := &models.GameAction{Type: models.ActionTypeRollDice, PlayerID: "p1"}
+	snapshot, err := Snapshot(game, time.Now())
+	require.NoError(t, err)
+	snapshot.Seq = 1
+
+	_, err = Fold(nil, engine)
+	assert.ErrorIs(t, err, ErrNoSnapshot)
+
+	_, err = Fold([]Event{{Seq: 1, Kind: KindAction, Action: action}}, engine)
+	assert.ErrorIs(t, err, ErrNoSnapshot)
+
+	_, err = Fold([]Event{snapshot, {Seq: 3, Kind: KindAction, Action: action}}, engine)
+	assert.ErrorContains(t, err, "skips from 1 to 3")
+}
+
+func TestSnapshotCopiesGame(t *testing.T) {
+	game := &models.Game{ID: primitive.NewObjectID(), Round: 3, Players: []models.Player{{ID: "p1", Balance: 100}}}
+	event, err := Snapshot(game, time.Now())
+	require.NoError(t, err)
+
+	game.Players[0].Balance = 50
+	assert.Equal(t, KindSnapshot, event.Kind)
+	assert.Equal(t, game.ID.Hex(), event.GameID)
+	assert.Equal(t, 3, event.State.Round)
+	assert.Equal(t, 100, event.State.Players[0].Balance)
+}
+
+func TestVisibleToHidesOtherPlayersChanges(t *testing.T) {
+	events := []Event{{Seq: 1, Kind: KindAction, Changes: []rules.Event{
+		{Type: "card_drawn"},
+		{Type: "card_received", Recipient: "p1"},
+		{Type: "card_received", Recipient: "p2"},
+	}}}
+
+	visible := VisibleTo(events, "p1")
+	require.Len(t, visible, 1)
+	require.Len(t, visible[0].Changes, 2)
+	assert.Equal(t, "", visible[0].Changes[0].Recipient)
+	assert.Equal(t, "p1", visible[0].Changes[1].Recipient)
+	assert.Len(t, events[0].Changes, 3, "the stored entries are left alone")
+}
+
+func TestVisibleToHidesOtherPlayersHandsInSnapshots(t *testing.T) {
+	game := &models.Game{ID: primitive.NewObjectID(), Players: []models.Player{
+		{ID: "p1", Cards: []models.Card{{ID: "meme_07"}}},
+		{ID: "p2", Cards: []models.Card{{ID: "redpill_05"}, {ID: "eegi_04"}}},
+	}}
+	snapshot, err := Snapshot(game, time.Now())
+	require.NoError(t, err)
+
+	// A spectator sees no hand at all
+	visible := VisibleTo([]Event{snapshot}, "")
+	for _, player := range visible[0].State.Players {
+		assert.Empty(t, player.Cards)
+	}
+	assert.Equal(t, 2, visible[0].State.Players[1].HandSize)
+
+	visible = VisibleTo([]Event{snapshot}, "p1")
+	assert.Len(t, visible[0].State.Players[0].Cards, 1)
+	assert.Empty(t, visible[0].State.Players[1].Cards)
+	assert.Len(t, snapshot.State.Players[1].Cards, 2, "the stored entries are left alone")
+}
+
+func TestRestoreGivesPlainValues(t *testing.T) {
+	event := Event{
+		Action: &models.GameAction{Payload: primitive.D{
+			{Key: "amount", Value: int32(40)},
+			{Key: "offered", Value: primitive.D{{Key: "properties", Value: primitive.A{"prop-4"}}}},
+		}},
+		Changes: []rules.Event{{Type: "bid_placed", Data: map[string]interface{}{"amount": int64(40), "bid": primitive.M{"playerId": "p1"}}}},
+	}
+	event.Restore()
+
+	assert.Equal(t, map[string]interface{}{
+		"amount":  40,
+		"offered": map[string]interface{}{"properties": []interface{}{"prop-4"}},
+	}, event.Action.Payload)
+	assert.Equal(t, map[string]interface{}{
+		"amount": 40,
+		"bid":    map[string]interface{}{"playerId": "p1"},
+	}, event.Changes[0].Data)
+}
@@ -0,0 +1,266 @@
+package manager
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/kekopoly/backend/internal/game/board"
+	"github.com/kekopoly/backend/internal/game/history"
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rng"
+	"github.com/kekopoly/backend/internal/game/rules"
+)
+
+// ProcessGameAction validates a player action with the rules engine, applies it to the
+// live game, persists the new state and broadcasts the resulting events to the game.
+// Actions rejected by the rules return an error for which rules.IsRuleViolation is true.
+func (gm *GameManager) ProcessGameAction(action models.GameAction) (*rules.Result, error) {
+	gm.logger.Infof("Processing game action: %s for game %s, player %s", action.Type, action.GameID, action.PlayerID)
+
+	session, err := gm.lookupSession(action.GameID)
+	if err != nil {
+		return nil, err
+	}
+
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	return gm.applyAction(session, action)
+}
+
+// applyAction runs an action through the rules engine. The caller must hold the session lock.
+func (gm *GameManager) applyAction(session *GameSession, action models.GameAction) (*rules.Result, error) {
+	if action.Timestamp.IsZero() {
+		action.Timestamp = time.Now()
+	}
+	// The game's history keeps times to the millisecond, so the rules must see the
+	// time the action will be replayed at
+	action.Timestamp = action.Timestamp.Truncate(time.Millisecond)
+
+	engine, err := gm.engineFor(session)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := engine.Apply(session.Game, action)
+	if err != nil {
+		if rules.IsRuleViolation(err) {
+			gm.logger.Debugf("Rejected action %s from player %s in game %s: %v", action.Type, action.PlayerID, session.Game.ID.Hex(), err)
+		} else {
+			gm.logger.Errorf("Failed to apply action %s from player %s in game %s: %v", action.Type, action.PlayerID, session.Game.ID.Hex(), err)
+		}
+		return nil, err
+	}
+
+	gm.commitResult(session, &action, result, action.Timestamp)
+	return result, nil
+}
+
+// commitResult records, saves and broadcasts the outcome of an accepted action, or of
+// a countdown that ran out when action is nil. The caller must hold the session lock.
+func (gm *GameManager) commitResult(session *GameSession, action *models.GameAction, result *rules.Result, timestamp time.Time) {
+	session.Game.UpdatedAt = timestamp
+	session.Game.LastActivity = timestamp
+	gm.appendEvent(session, history.Record(session.Game.ID.Hex(), action, result, timestamp))
+
+	// The in-memory game is authoritative once the rules accepted the action, so a
+	// persistence failure is logged rather than reported back as a failed move.
+	if err := gm.persistGame(session.Game); err != nil {
+		gm.logger.Errorf("Failed to persist game %s after action %s: %v", session.Game.ID.Hex(), result.Action, err)
+	}
+	gm.recordTransactions(result.Transactions)
+	gm.saveTrades(result.Trades)
+	gm.broadcastResult(session.Game.ID.Hex(), session.Game.EventSeq, result)
+	gm.announceTimeoutTakeovers(session.Game.ID.Hex(), result.Events)
+	gm.scheduleDeadline(session)
+	gm.scheduleBotMove(session)
+}
+
+// engineFor returns the rules engine of a session, creating it from the game's seed
+// on first use. The caller must hold the session lock.
+func (gm *GameManager) engineFor(session *GameSession) (*rules.Engine, error) {
+	if session.engine != nil {
+		return session.engine, nil
+	}
+
+	// Games created before seeds were introduced get one the first time they need it
+	if session.Game.RNGSeed == "" {
+		if err := assignSeed(session.Game); err != nil {
+			return nil, err
+		}
+	}
+
+	engine, err := gm.newEngine(session.Game)
+	if err != nil {
+		return nil, err
+	}
+	session.engine = engine
+	return session.engine, nil
+}
+
+// newEngine creates a rules engine for a game from its seed, resuming its randomizer
+// after the values the game has already drawn
+func (gm *GameManager) newEngine(game *models.Game) (*rules.Engine, error) {
+	seed, err := hex.DecodeString(game.RNGSeed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid random seed for game %s: %w", game.ID.Hex(), err)
+	}
+
+	gameBoard, err := gm.boardFor(game)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := rules.NewEngine(gameBoard, gm.newRandomizer(seed, game.RNGDraws))
+	engine.SetMarketSettings(gm.market)
+	return engine, nil
+}
+
+// boardFor returns the board pack a game is played on. Games created before board
+// packs existed have no board ID and are played on the default board.
+func (gm *GameManager) boardFor(game *models.Game) (*board.Board, error) {
+	gameBoard, err := gm.boards.Get(game.BoardID)
+	if err != nil {
+		return nil, fmt.Errorf("game %s: %w", game.ID.Hex(), err)
+	}
+	if game.BoardVersion != 0 && game.BoardVersion != gameBoard.Version {
+		gm.logger.Warnf("Game %s was created on board %s version %d but version %d is installed",
+			game.ID.Hex(), gameBoard.ID, game.BoardVersion, gameBoard.Version)
+	}
+	return gameBoard, nil
+}
+
+// assignSeed gives a game a fresh random seed and publishes its commitment
+func assignSeed(game *models.Game) error {
+	seed, err := rng.NewSeed()
+	if err != nil {
+		return err
+	}
+	game.RNGSeed = hex.EncodeToString(seed)
+	game.RNGCommitment = rng.Commitment(seed)
+	game.RNGDraws = 0
+	return nil
+}
+
+// lookupSession finds the active session for a game ID or room code
+func (gm *GameManager) lookupSession(gameID string) (*GameSession, error) {
+	normalizedGameID := strings.ToLower(gameID)
+
+	gm.activeGamesMutex.RLock()
+	session, exists := gm.activeGames[normalizedGameID]
+	gm.activeGamesMutex.RUnlock()
+	if exists {
+		return session, nil
+	}
+
+	// Fall back to the room code, as the other entry points do
+	if len(normalizedGameID) == 6 && gm.mongoClient != nil {
+		if game, err := gm.GetGameByRoomCode(normalizedGameID); err == nil {
+			gm.activeGamesMutex.RLock()
+			session, exists = gm.activeGames[game.ID.Hex()]
+			gm.activeGamesMutex.RUnlock()
+			if exists {
+				return session, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("game session not found")
+}
+
+// persistGame writes the full game document to the database
+func (gm *GameManager) persistGame(game *models.Game) error {
+	if gm.mongoClient == nil {
+		return nil
+	}
+
+	collection := gm.mongoClient.Database(gm.dbName).Collection("games")
+	_, err := collection.UpdateOne(gm.ctx, bson.M{"_id": game.ID}, bson.M{"$set": game})
+	if err != nil {
+		return fmt.Errorf("failed to update game in database: %w", err)
+	}
+	return nil
+}
+
+// recordTransactions stores the transactions produced by an action for balance auditing
+func (gm *GameManager) recordTransactions(transactions []models.Transaction) {
+	if gm.mongoClient == nil || len(transactions) == 0 {
+		return
+	}
+
+	docs := make([]interface{}, 0, len(transactions))
+	for _, tx := range transactions {
+		docs = append(docs, tx)
+	}
+
+	collection := gm.mongoClient.Database(gm.dbName).Collection("transactions")
+	if _, err := collection.InsertMany(gm.ctx, docs); err != nil {
+		gm.logger.Errorf("Failed to record %d transactions: %v", len(transactions), err)
+	}
+}
+
+// saveTrades writes the trades created or resolved by an action to the trades collection
+func (gm *GameManager) saveTrades(trades []models.Trade) {
+	if gm.mongoClient == nil || len(trades) == 0 {
+		return
+	}
+
+	collection := gm.mongoClient.Database(gm.dbName).Collection("trades")
+	for _, trade := range trades {
+		_, err := collection.ReplaceOne(gm.ctx, bson.M{"_id": trade.ID}, trade, options.Replace().SetUpsert(true))
+		if err != nil {
+			gm.logger.Errorf("Failed to save trade %s in game %s: %v", trade.ID, trade.GameID, err)
+		}
+	}
+}
+
+// broadcastResult sends every event produced by an action to the clients in the game,
+// tagged with the sequence number of the action's entry in the game's history.
+// Private events only go to their recipient.
+func (gm *GameManager) broadcastResult(gameID string, seq int, result *rules.Result) {
+	if gm.wsHub == nil {
+		gm.logger.Warnf("WebSocket hub is nil, cannot broadcast result of %s in game %s", result.Action, gameID)
+		return
+	}
+
+	now := time.Now()
+	for _, event := range result.Events {
+		msg := event.Message(gameID, now)
+		msg["action"] = result.Action
+		msg["seq"] = seq
+		if result.ByBot {
+			msg["byBot"] = true
+		}
+
+		msgBytes, err := json.Marshal(msg)
+		if err != nil {
+			gm.logger.Errorf("Failed to marshal %s event for game %s: %v", event.Type, gameID, err)
+			continue
+		}
+		if event.Recipient != "" {
+			gm.wsHub.SendToPlayer(gameID, event.Recipient, msgBytes)
+			continue
+		}
+		gm.wsHub.BroadcastToGame(gameID, msgBytes)
+	}
+}
+
+// broadcastWithPlayers sends a message holding the game's players to every player in
+// the game, each seeing only their own hand. The caller must check the hub is set.
+func (gm *GameManager) broadcastWithPlayers(gameID string, players []models.Player, msg map[string]interface{}) {
+	for _, player := range players {
+		msg["players"] = models.PlayersVisibleTo(players, player.ID)
+		msgBytes, err := json.Marshal(msg)
+		if err != nil {
+			gm.logger.Errorf("Failed to marshal %v message for game %s: %v", msg["type"], gameID, err)
+			return
+		}
+		gm.wsHub.SendToPlayer(gameID, player.ID, msgBytes)
+	}
+}
@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// recordingHub keeps the messages sent to each player
+type recordingHub struct {
+	sent map[string][]byte
+}
+
+func (h *recordingHub) BroadcastToGame(gameID string, message []byte) {}
+
+func (h *recordingHub) BroadcastToLobby(message []byte) {}
+
+func (h *recordingHub) SendToPlayer(gameID, playerID string, message []byte) bool {
+	h.sent[playerID] = message
+	return true
+}
+
+func TestBroadcastWithPlayersHidesOtherHands(t *testing.T) {
+	hub := &recordingHub{sent: make(map[string][]byte)}
+	gm := newTestManager()
+	gm.wsHub = hub
+
+	players := []models.Player{
+		{ID: "p1", Cards: []models.Card{{ID: "rugpull", Name: "Rug Pull"}}},
+		{ID: "p2", Cards: []models.Card{{ID: "moon", Name: "To The Moon"}, {ID: "hodl", Name: "HODL"}}},
+	}
+	gm.broadcastWithPlayers("game-1", players, map[string]interface{}{"type": "game_state"})
+
+	require.Contains(t, hub.sent, "p2")
+	assert.NotContains(t, string(hub.sent["p2"]), "rugpull", "an opponent's hand must not be serialized")
+
+	var msg struct {
+		Players []models.Player `json:"players"`
+	}
+	require.NoError(t, json.Unmarshal(hub.sent["p2"], &msg))
+	require.Len(t, msg.Players, 2)
+	assert.Empty(t, msg.Players[0].Cards)
+	assert.Equal(t, 1, msg.Players[0].HandSize)
+	assert.Len(t, msg.Players[1].Cards, 2)
+	assert.Equal(t, 2, msg.Players[1].HandSize)
+
+	// The game itself keeps every hand
+	assert.Len(t, players[0].Cards, 1)
+}
@@ -0,0 +1,324 @@
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kekopoly/backend/internal/game/bot"
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rules"
+)
+
+// botMoveDelay spaces out bot moves so the other players can follow them
+const botMoveDelay = time.Second
+
+// defaultDisconnectionTimeout matches the default of config.GameConfig.DisconnectionTimeout
+const defaultDisconnectionTimeout = 180 * time.Second
+
+// Reasons a bot takes over a player
+const (
+	takeoverDisconnected = "disconnected"
+	takeoverTimedOut     = "timed_out"
+)
+
+// Reasons the host cannot add an AI opponent to a game
+var (
+	ErrNotHost              = errors.New("only the host can add bots")
+	ErrGameNotInLobby       = errors.New("bots can only join a game in the lobby")
+	ErrGameFull             = errors.New("game is full")
+	ErrInvalidBotDifficulty = errors.New("unknown bot difficulty")
+)
+
+// SetDisconnectionTimeout sets how long a player may be disconnected from a running game
+// before a bot plays for them
+func (gm *GameManager) SetDisconnectionTimeout(timeout time.Duration) {
+	gm.activeGamesMutex.Lock()
+	defer gm.activeGamesMutex.Unlock()
+
+	gm.disconnectionTimeout = timeout
+	gm.logger.Infof("Disconnection timeout set to %s", timeout)
+}
+
+// AddBot seats an AI opponent in a lobby game at the host's request. An empty
+// difficulty selects a balanced bot.
+func (gm *GameManager) AddBot(gameID, requestingPlayerID string, difficulty models.BotDifficulty) (*models.Player, error) {
+	if difficulty == "" {
+		difficulty = models.BotDifficultyBalanced
+	}
+	difficulty = models.BotDifficulty(strings.ToUpper(string(difficulty)))
+	if _, ok := bot.Difficulties[difficulty]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBotDifficulty, difficulty)
+	}
+
+	session, err := gm.lookupSession(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	game := session.Game
+	if requestingPlayerID != game.HostID {
+		return nil, ErrNotHost
+	}
+	if game.Status != models.GameStatusLobby {
+		return nil, ErrGameNotInLobby
+	}
+	if len(game.Players) >= game.MaxPlayers {
+		return nil, ErrGameFull
+	}
+
+	gameBoard, err := gm.boardFor(game)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bots have no user account or connection; the server plays for them
+	botPlayer := models.Player{
+		ID:            "bot-" + uuid.New().String()[:8],
+		Status:        models.PlayerStatusActive,
+		Balance:       1500,
+		Position:      gameBoard.StartPosition(),
+		Cards:         []models.Card{},
+		Properties:    []string{},
+		NetWorth:      1500,
+		IsBot:         true,
+		BotDifficulty: difficulty,
+	}
+	game.Players = append(game.Players, botPlayer)
+	game.TurnOrder = append(game.TurnOrder, botPlayer.ID)
+	game.UpdatedAt = time.Now()
+	game.LastActivity = game.UpdatedAt
+
+	if err := gm.persistGame(game); err != nil {
+		return nil, err
+	}
+	gm.logger.Infof("Host %s added a %s bot %s to game %s", requestingPlayerID, difficulty, botPlayer.ID, game.ID.Hex())
+
+	if gm.wsHub != nil {
+		gm.broadcastWithPlayers(game.ID.Hex(), game.Players, map[string]interface{}{
+			"type":      "bot_added",
+			"gameId":    game.ID.Hex(),
+			"player":    botPlayer,
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+		gm.broadcastLobbyUpdate()
+	}
+
+	return &botPlayer, nil
+}
+
+// controlledByBot reports whether the bot plays for a player: they are an AI opponent,
+// were flagged AFK after timing out, or stayed disconnected too long
+func controlledByBot(player *models.Player) bool {
+	return player.IsBot || player.AFK || player.BotControlled
+}
+
+// nextBotMove returns the next move of a player the bot plays for
+func nextBotMove(game *models.Game) (models.GameAction, bool) {
+	return bot.NextMove(game, controlledByBot)
+}
+
+// markDisconnected keeps the seat of a player who dropped out of a running game and
+// hands it to the bot if they do not come back in time. The caller must hold the
+// session lock.
+func (gm *GameManager) markDisconnected(session *GameSession, playerID string) {
+	player := findPlayer(session.Game, playerID)
+	if player == nil {
+		return
+	}
+	now := time.Now()
+	player.DisconnectedAt = &now
+	if sessionID, ok := session.ConnectedPlayers[playerID]; ok {
+		delete(session.PlayerConnections, sessionID)
+		delete(session.ConnectedPlayers, playerID)
+	}
+	if err := gm.persistGame(session.Game); err != nil {
+		gm.logger.Errorf("Failed to save disconnection of player %s in game %s: %v", playerID, session.Game.ID.Hex(), err)
+	}
+
+	gm.activeGamesMutex.RLock()
+	timeout := gm.disconnectionTimeout
+	gm.activeGamesMutex.RUnlock()
+
+	gameID := session.Game.ID.Hex()
+	gm.stopTakeover(session, playerID)
+	if session.takeoverTimers == nil {
+		session.takeoverTimers = make(map[string]*time.Timer)
+	}
+	session.takeoverTimers[playerID] = time.AfterFunc(timeout, func() {
+		gm.takeOverDisconnected(gameID, playerID)
+	})
+	gm.logger.Infof("Player %s disconnected from running game %s; a bot takes over in %s", playerID, gameID, timeout)
+}
+
+// takeOverDisconnected lets the bot play for a player who is still disconnected
+func (gm *GameManager) takeOverDisconnected(gameID, playerID string) {
+	session, err := gm.lookupSession(gameID)
+	if err != nil {
+		return
+	}
+
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	delete(session.takeoverTimers, playerID)
+	player := findPlayer(session.Game, playerID)
+	if session.Game.Status != models.GameStatusActive || player == nil || player.DisconnectedAt == nil || player.BotControlled {
+		return
+	}
+	player.BotControlled = true
+	if err := gm.persistGame(session.Game); err != nil {
+		gm.logger.Errorf("Failed to save bot takeover of player %s in game %s: %v", playerID, gameID, err)
+	}
+	gm.broadcastBotControl(gameID, "bot_takeover", playerID, takeoverDisconnected)
+	gm.scheduleBotMove(session)
+}
+
+// returnToSeat gives a player who rejoins a running game their seat back, taking it off
+// the bot. It reports whether the player had a seat in the game, and whether giving it
+// back changed what the rules see: the bot's control or the player's timeouts. The
+// caller must hold the session lock.
+func (gm *GameManager) returnToSeat(session *GameSession, playerID string) (seated, changed bool) {
+	player := findPlayer(session.Game, playerID)
+	if player == nil {
+		return false, false
+	}
+	gm.stopTakeover(session, playerID)
+
+	wasBot := controlledByBot(player)
+	changed = player.BotControlled || player.AFK || player.Timeouts != 0
+	player.DisconnectedAt = nil
+	player.BotControlled = false
+	player.AFK = false
+	player.Timeouts = 0
+	if wasBot {
+		gm.broadcastBotControl(session.Game.ID.Hex(), "bot_released", playerID, "")
+	}
+	return true, changed
+}
+
+// stopTakeover cancels a pending bot takeover. The caller must hold the session lock.
+func (gm *GameManager) stopTakeover(session *GameSession, playerID string) {
+	if timer, ok := session.takeoverTimers[playerID]; ok {
+		timer.Stop()
+		delete(session.takeoverTimers, playerID)
+	}
+}
+
+// announceTimeoutTakeovers tells the game when the bot starts playing for players the
+// rules just flagged AFK
+func (gm *GameManager) announceTimeoutTakeovers(gameID string, events []rules.Event) {
+	for _, event := range events {
+		if event.Type != "player_afk" {
+			continue
+		}
+		if playerID, ok := event.Data["playerId"].(string); ok {
+			gm.broadcastBotControl(gameID, "bot_takeover", playerID, takeoverTimedOut)
+		}
+	}
+}
+
+// scheduleBotMove arranges for the bot to make the next move when a player it plays
+// for has one to make. The caller must hold the session lock.
+func (gm *GameManager) scheduleBotMove(session *GameSession) {
+	if session.botTimer != nil {
+		return
+	}
+	if _, ok := nextBotMove(session.Game); !ok {
+		return
+	}
+	gameID := session.Game.ID.Hex()
+	session.botTimer = time.AfterFunc(botMoveDelay, func() {
+		gm.playBotMove(gameID)
+	})
+}
+
+// playBotMove makes the next bot move. Each accepted move schedules the one after it; a
+// rejected move leaves the turn to the turn clock.
+func (gm *GameManager) playBotMove(gameID string) {
+	session, err := gm.lookupSession(gameID)
+	if err != nil {
+		return
+	}
+
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	session.botTimer = nil
+	action, ok := nextBotMove(session.Game)
+	if !ok {
+		return
+	}
+	action.ByBot = true
+	if _, err := gm.applyAction(session, action); err != nil {
+		gm.logger.Warnf("Bot move %s for player %s in game %s was rejected: %v", action.Type, action.PlayerID, gameID, err)
+	}
+}
+
+// broadcastBotControl tells the game that the bot took over or handed back a player
+func (gm *GameManager) broadcastBotControl(gameID, messageType, playerID, reason string) {
+	if gm.wsHub == nil {
+		return
+	}
+	msg := map[string]interface{}{
+		"type":      messageType,
+		"gameId":    gameID,
+		"playerId":  playerID,
+		"timestamp": time.Now().Format(time.RFC3339),
+	}
+	if reason != "" {
+		msg["reason"] = reason
+	}
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		gm.logger.Errorf("Failed to marshal %s message for game %s: %v", messageType, gameID, err)
+		return
+	}
+	gm.wsHub.BroadcastToGame(gameID, msgBytes)
+}
+
+// findPlayer returns the player with the given ID in a game
+func findPlayer(game *models.Game, playerID string) *models.Player {
+	for i := range game.Players {
+		if game.Players[i].ID == playerID {
+			return &game.Players[i]
+		}
+	}
+	return nil
+}
+
+// hasPeople reports whether anyone other than bots is seated in a game
+func hasPeople(game *models.Game) bool {
+	for _, player := range game.Players {
+		if !player.IsBot {
+			return true
+		}
+	}
+	return false
+}
+
+// nextHost picks the person who takes over as host: the first one in turn order from
+// the departed host's place, wrapping around
+func nextHost(game *models.Game, from int) string {
+	if from < 0 {
+		from = 0
+	}
+	for i := range game.TurnOrder {
+		if player := findPlayer(game, game.TurnOrder[(from+i)%len(game.TurnOrder)]); player != nil && !player.IsBot {
+			return player.ID
+		}
+	}
+	for _, player := range game.Players {
+		if !player.IsBot {
+			return player.ID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/kekopoly/backend/internal/game/history"
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/replay"
+)
+
+// maxEventsPerRequest caps the history entries returned at once; a client catching up
+// asks again from the last one it received
+const maxEventsPerRequest = 500
+
+// ErrNoHistory is returned when a game has no history to rebuild it from
+var ErrNoHistory = errors.New("game has no recorded history")
+
+// appendEvent numbers an entry as the next one in the game's history and stores it in
+// the game_events collection. The caller must hold the session lock.
+func (gm *GameManager) appendEvent(session *GameSession, event history.Event) {
+	session.Game.EventSeq++
+	event.Seq = session.Game.EventSeq
+	if event.State != nil {
+		event.State.EventSeq = event.Seq
+	}
+	if gm.mongoClient == nil {
+		return
+	}
+
+	collection := gm.mongoClient.Database(gm.dbName).Collection("game_events")
+	if _, err := collection.InsertOne(gm.ctx, event); err != nil {
+		gm.logger.Errorf("Failed to record event %d of game %s: %v", event.Seq, event.GameID, err)
+	}
+}
+
+// recordSnapshot appends the whole game to its history, as the starting point for the
+// entries after it. It is used when the game changes outside the rules engine.
+// The caller must hold the session lock.
+func (gm *GameManager) recordSnapshot(session *GameSession, timestamp time.Time) {
+	event, err := history.Snapshot(session.Game, timestamp)
+	if err != nil {
+		gm.logger.Errorf("Failed to record snapshot of game %s: %v", session.Game.ID.Hex(), err)
+		return
+	}
+	gm.appendEvent(session, event)
+}
+
+// GetGameEvents returns the entries of a game's history after the since sequence
+// number, oldest first and as the player may see them. At most maxEventsPerRequest
+// entries are returned at once.
+func (gm *GameManager) GetGameEvents(gameID, playerID string, since int) ([]history.Event, error) {
+	game, err := gm.GetGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"gameId": game.ID.Hex(), "seq": bson.M{"$gt": since}}
+	findOptions := options.Find().SetSort(bson.D{{Key: "seq", Value: 1}}).SetLimit(maxEventsPerRequest)
+	events, err := gm.loadEvents(filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	return history.VisibleTo(events, playerID), nil
+}
+
+// RebuildGame replays a game's history from its latest snapshot, giving the game as it
+// stood after the last recorded entry
+func (gm *GameManager) RebuildGame(gameID string) (*models.Game, error) {
+	game, err := gm.GetGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	if gm.mongoClient == nil {
+		return nil, ErrNoHistory
+	}
+
+	id := game.ID.Hex()
+	var latest history.Event
+	collection := gm.mongoClient.Database(gm.dbName).Collection("game_events")
+	err = collection.FindOne(gm.ctx,
+		bson.M{"gameId": id, "kind": history.KindSnapshot},
+		options.FindOne().SetSort(bson.D{{Key: "seq", Value: -1}}).SetProjection(bson.M{"seq": 1}),
+	).Decode(&latest)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNoHistory
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load history of game %s: %w", id, err)
+	}
+
+	events, err := gm.loadEvents(
+		bson.M{"gameId": id, "seq": bson.M{"$gte": latest.Seq}},
+		options.Find().SetSort(bson.D{{Key: "seq", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return history.Fold(events, gm.newEngine)
+}
+
+// GetReplay returns the recorded timeline of a completed game, to be played back to
+// viewers without touching the live game
+func (gm *GameManager) GetReplay(gameID string) (*replay.Timeline, error) {
+	game, err := gm.GetGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.Status != models.GameStatusCompleted {
+		return nil, ErrGameNotCompleted
+	}
+
+	events, err := gm.loadEvents(
+		bson.M{"gameId": game.ID.Hex()},
+		options.Find().SetSort(bson.D{{Key: "seq", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, ErrNoHistory
+	}
+	return replay.NewTimeline(events, gm.newEngine)
+}
+
+// loadEvents reads history entries from the game_events collection
+func (gm *GameManager) loadEvents(filter bson.M, findOptions *options.FindOptions) ([]history.Event, error) {
+	events := []history.Event{}
+	if gm.mongoClient == nil {
+		return events, nil
+	}
+
+	collection := gm.mongoClient.Database(gm.dbName).Collection("game_events")
+	cursor, err := collection.Find(gm.ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load game events: %w", err)
+	}
+	if err := cursor.All(gm.ctx, &events); err != nil {
+		return nil, fmt.Errorf("failed to load game events: %w", err)
+	}
+	for i := range events {
+		events[i].Restore()
+	}
+	return events, nil
+}
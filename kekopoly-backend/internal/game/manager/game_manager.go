@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math/rand"
 	"strings"
 	"sync"
 	"time"
@@ -17,7 +16,10 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 
+	"github.com/kekopoly/backend/internal/game/board"
 	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rng"
+	"github.com/kekopoly/backend/internal/game/rules"
 	"github.com/kekopoly/backend/internal/game/utils"
 )
 
@@ -40,12 +42,30 @@ type GameManager struct {
 	storage          Storage
 	wsHub            WebSocketHub
 	messageQueue     MessageQueue
+	newRandomizer    rng.Factory
+	boards           *board.Registry
+	market           rules.MarketSettings
+	turnTimeout      time.Duration // Time each player has for their turn in new games
+	// Time a player may be disconnected from a running game before the bot plays for them
+	disconnectionTimeout time.Duration
+}
+
+// defaultTurnTimeout matches the default of config.GameConfig.TurnTimeout
+const defaultTurnTimeout = 120 * time.Second
+
+// GameOptions holds the optional settings chosen when a game is created
+type GameOptions struct {
+	BoardID          string          // Board pack to play on; empty selects the default board
+	Mode             models.GameMode // How the game ends; empty selects the classic game
+	TimeLimitMinutes int             // Length of a timed game; zero selects the default
+	RoundLimit       int             // Rounds in a round-capped game; zero selects the default
 }
 
 // WebSocketHub defines the interface for broadcasting messages to clients
 type WebSocketHub interface {
 	BroadcastToGame(gameID string, message []byte)
 	BroadcastToLobby(message []byte)
+	SendToPlayer(gameID, playerID string, message []byte) bool
 }
 
 // MessageQueue defines the interface for the message queue
@@ -61,6 +81,10 @@ type GameSession struct {
 	ConnectedPlayers  map[string]string // playerID -> sessionID
 	PlayerConnections map[string]PlayerConnection
 	mutex             sync.RWMutex
+	engine            *rules.Engine          // Created on first use from the game's seed; guarded by mutex
+	deadlineTimer     *time.Timer            // Applies the next auction or debt deadline when it passes; guarded by mutex
+	botTimer          *time.Timer            // Makes the bot's next move for a player it controls; guarded by mutex
+	takeoverTimers    map[string]*time.Timer // Player ID to the bot takeover of a disconnected player; guarded by mutex
 }
 
 // PlayerConnection holds a player's connection information
@@ -74,15 +98,20 @@ type PlayerConnection struct {
 // NewGameManager creates a new game manager instance
 func NewGameManager(ctx context.Context, mongoClient *mongo.Client, redisClient *redis.Client, logger *zap.SugaredLogger, wsHub WebSocketHub, messageQueue MessageQueue) *GameManager {
 	manager := &GameManager{
-		ctx:          ctx,
-		mongoClient:  mongoClient,
-		redisClient:  redisClient,
-		logger:       logger,
-		activeGames:  make(map[string]*GameSession),
-		dbName:       "kekopoly", // This would come from config in a real implementation
-		games:        make(map[string]*models.Game),
-		wsHub:        wsHub,
-		messageQueue: messageQueue,
+		ctx:                  ctx,
+		mongoClient:          mongoClient,
+		redisClient:          redisClient,
+		logger:               logger,
+		activeGames:          make(map[string]*GameSession),
+		dbName:               "kekopoly", // This would come from config in a real implementation
+		games:                make(map[string]*models.Game),
+		wsHub:                wsHub,
+		messageQueue:         messageQueue,
+		newRandomizer:        rng.HMACFactory,
+		boards:               board.NewRegistry(),
+		market:               rules.DefaultMarketSettings(),
+		turnTimeout:          defaultTurnTimeout,
+		disconnectionTimeout: defaultDisconnectionTimeout,
 	}
 
 	// First cleanup lobby games immediately on server start (synchronously)
@@ -107,6 +136,58 @@ func (gm *GameManager) SetMessageQueue(queue MessageQueue) {
 	gm.logger.Info("Message queue set for game manager")
 }
 
+// SetBoardRegistry sets the board packs that games can be created with
+func (gm *GameManager) SetBoardRegistry(boards *board.Registry) {
+	gm.boards = boards
+	gm.logger.Info("Board registry set for game manager")
+}
+
+// Boards returns the board packs that games can be created with
+func (gm *GameManager) Boards() *board.Registry {
+	return gm.boards
+}
+
+// SetRandomizerFactory replaces the source of randomness used for new and loaded games,
+// e.g. with a seeded or scripted randomizer in tests
+func (gm *GameManager) SetRandomizerFactory(factory rng.Factory) {
+	gm.activeGamesMutex.Lock()
+	defer gm.activeGamesMutex.Unlock()
+
+	gm.newRandomizer = factory
+	for _, session := range gm.activeGames {
+		session.mutex.Lock()
+		session.engine = nil
+		session.mutex.Unlock()
+	}
+	gm.logger.Info("Randomizer factory set for game manager")
+}
+
+// SetTurnTimeout sets how long each player has for their turn in games created from now
+// on. Zero turns the turn clock off.
+func (gm *GameManager) SetTurnTimeout(timeout time.Duration) {
+	gm.activeGamesMutex.Lock()
+	defer gm.activeGamesMutex.Unlock()
+
+	gm.turnTimeout = timeout
+	gm.logger.Infof("Turn timeout set to %s for new games", timeout)
+}
+
+// SetMarketSettings sets the odds and length of bull and crash markets for every game
+func (gm *GameManager) SetMarketSettings(settings rules.MarketSettings) {
+	gm.activeGamesMutex.Lock()
+	defer gm.activeGamesMutex.Unlock()
+
+	gm.market = settings
+	for _, session := range gm.activeGames {
+		session.mutex.Lock()
+		if session.engine != nil {
+			session.engine.SetMarketSettings(settings)
+		}
+		session.mutex.Unlock()
+	}
+	gm.logger.Info("Market settings set for game manager")
+}
+
 // cleanupLobbyGamesAndLoadActive ensures lobby games are cleaned up before loading active games
 func (gm *GameManager) cleanupLobbyGamesAndLoadActive() {
 	gm.logger.Info("Cleaning up lobby games and loading active games")
@@ -186,9 +267,17 @@ func (gm *GameManager) loadActiveGamesFromDB() {
 		return
 	}
 
-	for _, game := range games {
+	gm.restoreSessions(games)
+	gm.logger.Infof("Loaded %d active games", len(games))
+}
+
+// restoreSessions puts games loaded from the database back into play, picking up the
+// countdowns and bot moves they were waiting on
+func (gm *GameManager) restoreSessions(games []models.Game) {
+	for i := range games {
+		game := &games[i]
 		gameSession := &GameSession{
-			Game:              &game,
+			Game:              game,
 			ConnectedPlayers:  make(map[string]string),
 			PlayerConnections: make(map[string]PlayerConnection),
 		}
@@ -197,10 +286,14 @@ func (gm *GameManager) loadActiveGamesFromDB() {
 		gm.activeGames[game.ID.Hex()] = gameSession
 		gm.activeGamesMutex.Unlock()
 
+		// An auction that was running when the server stopped still needs to close
+		gameSession.mutex.Lock()
+		gm.scheduleDeadline(gameSession)
+		gm.scheduleBotMove(gameSession)
+		gameSession.mutex.Unlock()
+
 		gm.logger.Infof("Loaded game %s with status %s", game.ID.Hex(), game.Status)
 	}
-
-	gm.logger.Infof("Loaded %d active games", len(games))
 }
 
 // runCleanupTask periodically cleans up expired game sessions
@@ -265,7 +358,12 @@ func (gm *GameManager) cleanupExpiredSessions() {
 }
 
 // CreateGame creates a new game
-func (gm *GameManager) CreateGame(hostPlayerID, gameName string, maxPlayers int) (string, error) {
+func (gm *GameManager) CreateGame(hostPlayerID, gameName string, maxPlayers int, options GameOptions) (string, error) {
+	gameBoard, err := gm.boards.Get(options.BoardID)
+	if err != nil {
+		return "", err
+	}
+
 	gameID := primitive.NewObjectID()
 	now := time.Now()
 
@@ -308,17 +406,20 @@ func (gm *GameManager) CreateGame(hostPlayerID, gameName string, maxPlayers int)
 	}
 
 	game := &models.Game{
-		ID:         gameID,
-		Code:       roomCode, // Set the room code
-		Name:       gameName,
-		Status:     models.GameStatusLobby,
-		CreatedAt:  now,
-		UpdatedAt:  now,
-		Players:    []models.Player{},
-		HostID:     hostPlayerID, // Explicitly set the host ID
-		MaxPlayers: maxPlayers,   // Set the maximum players
+		ID:           gameID,
+		Code:         roomCode, // Set the room code
+		Name:         gameName,
+		Status:       models.GameStatusLobby,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Players:      []models.Player{},
+		HostID:       hostPlayerID, // Explicitly set the host ID
+		MaxPlayers:   maxPlayers,   // Set the maximum players
+		BoardID:      gameBoard.ID,
+		BoardVersion: gameBoard.Version,
 		BoardState: models.BoardState{
-			Properties: []models.Property{},
+			Properties:     gameBoard.NewProperties(),
+			BuildingSupply: rules.NewBuildingSupply(),
 			CardsRemaining: models.CardCount{
 				Meme:    16,
 				Redpill: 16,
@@ -329,13 +430,22 @@ func (gm *GameManager) CreateGame(hostPlayerID, gameName string, maxPlayers int)
 		MarketCondition:  models.MarketConditionNormal,
 		SettlementStatus: models.SettlementStatusPending,
 	}
+	if err := rules.ConfigureMode(game, options.Mode, options.TimeLimitMinutes, options.RoundLimit); err != nil {
+		return "", err
+	}
+	gm.activeGamesMutex.RLock()
+	game.TurnTimeoutSeconds = int(gm.turnTimeout / time.Second)
+	gm.activeGamesMutex.RUnlock()
+	if err := assignSeed(game); err != nil {
+		return "", err
+	}
 
 	// Create host player
 	hostPlayer := models.Player{
 		ID:             hostPlayerID,
 		Status:         models.PlayerStatusActive,
 		Balance:        1500, // Initial balance, should come from config
-		Position:       0,    // Start position
+		Position:       gameBoard.StartPosition(),
 		Cards:          []models.Card{},
 		Properties:     []string{},
 		InitialDeposit: 0,    // No deposit yet
@@ -499,12 +609,17 @@ func (gm *GameManager) JoinGame(gameID, playerID string) (string, error) {
 		return "", fmt.Errorf("game is full")
 	}
 
+	gameBoard, err := gm.boardFor(session.Game)
+	if err != nil {
+		return "", err
+	}
+
 	// Create new player
 	newPlayer := models.Player{
 		ID:             playerID,
 		Status:         models.PlayerStatusActive,
 		Balance:        1500, // Initial balance, should come from config
-		Position:       0,    // Start position
+		Position:       gameBoard.StartPosition(),
 		Cards:          []models.Card{},
 		Properties:     []string{},
 		InitialDeposit: 0,    // No deposit yet
@@ -630,19 +745,29 @@ func (gm *GameManager) StartGame(gameID string, requestingPlayerID string) error
 		}
 	}
 
+	// Shuffle with the game's own randomizer so the turn order and the card decks can
+	// be reproduced from its seed
+	engine, err := gm.engineFor(session)
+	if err != nil {
+		return err
+	}
+
 	// Set game status to ACTIVE
 	// Randomize turn order before starting
 	if len(session.Game.TurnOrder) > 1 {
-		// Use a more modern approach for random shuffling
-		r := rand.New(rand.NewSource(time.Now().UnixNano()))
-		r.Shuffle(len(session.Game.TurnOrder), func(i, j int) {
+		engine.Shuffle(session.Game, len(session.Game.TurnOrder), func(i, j int) {
 			session.Game.TurnOrder[i], session.Game.TurnOrder[j] = session.Game.TurnOrder[j], session.Game.TurnOrder[i]
 		})
 	}
+	engine.SetupDecks(session.Game)
 	session.Game.Status = models.GameStatusActive
 	session.Game.CurrentTurn = session.Game.TurnOrder[0]
-	session.Game.UpdatedAt = time.Now()
-	session.Game.LastActivity = time.Now()
+	session.Game.Round = 1
+	// Kept to the millisecond, as the game's history stores it
+	session.Game.UpdatedAt = time.Now().Truncate(time.Millisecond)
+	session.Game.LastActivity = session.Game.UpdatedAt
+	rules.StartClock(session.Game, session.Game.UpdatedAt)
+	gm.recordSnapshot(session, session.Game.UpdatedAt)
 
 	// Update game in database
 	objID, err := primitive.ObjectIDFromHex(gameID)
@@ -656,18 +781,29 @@ func (gm *GameManager) StartGame(gameID string, requestingPlayerID string) error
 		bson.M{"_id": objID},
 		bson.M{
 			"$set": bson.M{
-				"status":       session.Game.Status,
-				"currentTurn":  session.Game.CurrentTurn,
-				"turnOrder":    session.Game.TurnOrder,
-				"players":      session.Game.Players,
-				"updatedAt":    session.Game.UpdatedAt,
-				"lastActivity": session.Game.LastActivity,
+				"status":           session.Game.Status,
+				"currentTurn":      session.Game.CurrentTurn,
+				"turnOrder":        session.Game.TurnOrder,
+				"round":            session.Game.Round,
+				"players":          session.Game.Players,
+				"boardState":       session.Game.BoardState,
+				"updatedAt":        session.Game.UpdatedAt,
+				"lastActivity":     session.Game.LastActivity,
+				"rngSeed":          session.Game.RNGSeed,
+				"rngCommitment":    session.Game.RNGCommitment,
+				"rngDraws":         session.Game.RNGDraws,
+				"endsAt":           session.Game.EndsAt,
+				"timeWarningsSent": session.Game.TimeWarningsSent,
+				"turnState":        session.Game.TurnState,
+				"eventSeq":         session.Game.EventSeq,
 			},
 		},
 	)
 	if updateErr != nil {
 		return fmt.Errorf("failed to update game: %w", updateErr)
 	}
+	gm.scheduleDeadline(session)
+	gm.scheduleBotMove(session)
 
 	gm.logger.Infof("Game %s started with %d players", gameID, len(session.Game.Players))
 
@@ -679,8 +815,10 @@ func (gm *GameManager) StartGame(gameID string, requestingPlayerID string) error
 			"gameId":      gameID,
 			"status":      string(session.Game.Status),
 			"currentTurn": session.Game.CurrentTurn,
-			"players":     session.Game.Players,
 			"turnOrder":   session.Game.TurnOrder,
+			"mode":        session.Game.Mode,
+			"roundLimit":  session.Game.RoundLimit,
+			"endsAt":      session.Game.EndsAt,
 			"timestamp":   time.Now().Format(time.RFC3339),
 		}
 
@@ -694,23 +832,18 @@ func (gm *GameManager) StartGame(gameID string, requestingPlayerID string) error
 			}
 		}
 
-		// Marshal to JSON
-		msgBytes, jsonErr := json.Marshal(gameState)
-		if jsonErr != nil {
-			gm.logger.Errorf("Failed to marshal game_started message: %v", jsonErr)
-		} else {
-			// Broadcast to all clients in the game
-			gm.wsHub.BroadcastToGame(gameID, msgBytes)
-			gm.logger.Infof("Broadcasted game_started event to all clients in game %s", gameID)
-		}
+		// Send to every player in the game, each seeing only their own hand
+		gm.broadcastWithPlayers(gameID, session.Game.Players, gameState)
+		gm.logger.Infof("Broadcasted game_started event to all clients in game %s", gameID)
 
 		// Immediately broadcast the first turn
 		turnMsg := map[string]interface{}{
-			"type":        "game_turn",
-			"currentTurn": session.Game.CurrentTurn,
-			"turnOrder":   session.Game.TurnOrder,
-			"gameId":      gameID,
-			"timestamp":   time.Now().Format(time.RFC3339),
+			"type":         "game_turn",
+			"currentTurn":  session.Game.CurrentTurn,
+			"turnOrder":    session.Game.TurnOrder,
+			"turnDeadline": session.Game.TurnState.Deadline,
+			"gameId":       gameID,
+			"timestamp":    time.Now().Format(time.RFC3339),
 		}
 		if turnBytes, err := json.Marshal(turnMsg); err == nil {
 			gm.wsHub.BroadcastToGame(gameID, turnBytes)
@@ -739,6 +872,13 @@ func (gm *GameManager) PlayerDisconnected(gameID, playerID string) (string, erro
 	session.mutex.Lock()
 	defer session.mutex.Unlock()
 
+	// A player who drops out of a running game keeps their seat, and a bot plays for
+	// them if they stay away
+	if session.Game.Status == models.GameStatusActive {
+		gm.markDisconnected(session, playerID)
+		return "", nil
+	}
+
 	// Find the player and remove them
 	playerIndex := -1
 	for i, p := range session.Game.Players {
@@ -770,20 +910,19 @@ func (gm *GameManager) PlayerDisconnected(gameID, playerID string) (string, erro
 		session.Game.TurnOrder = append(session.Game.TurnOrder[:turnOrderIndex], session.Game.TurnOrder[turnOrderIndex+1:]...)
 	}
 
+	// Bots cannot keep a lobby going on their own
+	if !hasPeople(session.Game) {
+		session.Game.Players = []models.Player{}
+		session.Game.TurnOrder = []string{}
+	}
+
 	newHostID := ""
 	// If the disconnected player was the host, assign a new host.
 	if session.Game.HostID == playerID {
 		if len(session.Game.Players) > 0 {
-			// Assign the next player in the original turn order as the new host.
-			// If the host was the last in turn order, assign the first player.
-			if turnOrderIndex != -1 && turnOrderIndex < len(session.Game.TurnOrder) {
-				newHostID = session.Game.TurnOrder[turnOrderIndex]
-			} else if len(session.Game.TurnOrder) > 0 {
-				newHostID = session.Game.TurnOrder[0]
-			} else {
-				// Fallback to the first player in the remaining player list
-				newHostID = session.Game.Players[0].ID
-			}
+			// Assign the next person in the original turn order as the new host.
+			// If the host was the last in turn order, start again from the first player.
+			newHostID = nextHost(session.Game, turnOrderIndex)
 			session.Game.HostID = newHostID
 			gm.logger.Infof("Host %s disconnected from game %s. New host is %s.", playerID, gameID, newHostID)
 		} else {
@@ -1119,30 +1258,6 @@ func (gm *GameManager) ListAvailableGames() ([]models.Game, error) {
 	return games, nil
 }
 
-// ProcessGameAction handles incoming game actions from players.
-func (gm *GameManager) ProcessGameAction(action models.GameAction) error {
-	gm.logger.Infof("Processing game action: %s for game %s, player %s", action.Type, action.GameID, action.PlayerID)
-
-	// Here you would have a switch statement or other logic to handle different action types.
-	// For example:
-	switch action.Type {
-	case "roll_dice":
-		// Logic for rolling dice
-		gm.logger.Infof("Player %s is rolling the dice.", action.PlayerID)
-	case "buy_property":
-		// Logic for buying a property
-		gm.logger.Infof("Player %s is buying a property.", action.PlayerID)
-	default:
-		return fmt.Errorf("unknown game action type: %s", action.Type)
-	}
-
-	// After processing the action, you might need to broadcast the new game state.
-	// For example:
-	// gm.broadcastGameState(action.GameID)
-
-	return nil
-}
-
 // broadcastLobbyUpdate sends the current list of available games to all lobby clients
 func (gm *GameManager) broadcastLobbyUpdate() {
 	games, err := gm.ListAvailableGames()
@@ -1222,21 +1337,35 @@ func (gm *GameManager) RejoinGame(gameID, playerID, sessionID string) error {
 		return fmt.Errorf("player is already in the game")
 	}
 
-	// Add the player back to the game
-	player := models.Player{
-		ID:             playerID,
-		Status:         models.PlayerStatusActive,
-		Balance:        1500, // Reset balance, or fetch from saved state
-		Position:       0,    // Reset position, or fetch from saved state
-		Cards:          []models.Card{},
-		Properties:     []string{},
-		InitialDeposit: 0,    // No deposit yet
-		NetWorth:       1500, // Same as initial balance
-	}
+	// A player who kept their seat gets it back, taking it off the bot
+	seated, changed := gm.returnToSeat(session, playerID)
+	if !seated {
+		gameBoard, err := gm.boardFor(session.Game)
+		if err != nil {
+			return err
+		}
 
-	session.Game.Players = append(session.Game.Players, player)
-	session.Game.TurnOrder = append(session.Game.TurnOrder, playerID)
+		// Add the player back to the game
+		player := models.Player{
+			ID:             playerID,
+			Status:         models.PlayerStatusActive,
+			Balance:        1500,                      // Reset balance, or fetch from saved state
+			Position:       gameBoard.StartPosition(), // Reset position, or fetch from saved state
+			Cards:          []models.Card{},
+			Properties:     []string{},
+			InitialDeposit: 0,    // No deposit yet
+			NetWorth:       1500, // Same as initial balance
+		}
+
+		session.Game.Players = append(session.Game.Players, player)
+		session.Game.TurnOrder = append(session.Game.TurnOrder, playerID)
+		changed = true
+	}
 	session.Game.LastActivity = time.Now()
+	// A rejoin that only reconnects the player leaves the game's history untouched
+	if changed {
+		gm.recordSnapshot(session, session.Game.LastActivity)
+	}
 
 	// Update the game state in the database
 	collection := gm.mongoClient.Database(gm.dbName).Collection("games")
@@ -1248,6 +1377,7 @@ func (gm *GameManager) RejoinGame(gameID, playerID, sessionID string) error {
 				"players":      session.Game.Players,
 				"turnOrder":    session.Game.TurnOrder,
 				"lastActivity": session.Game.LastActivity,
+				"eventSeq":     session.Game.EventSeq,
 			},
 		},
 	)
@@ -1272,20 +1402,13 @@ func (gm *GameManager) RejoinGame(gameID, playerID, sessionID string) error {
 		"gameId":      gameID,
 		"status":      string(session.Game.Status),
 		"currentTurn": session.Game.CurrentTurn,
-		"players":     session.Game.Players,
 		"turnOrder":   session.Game.TurnOrder,
 		"timestamp":   time.Now().Format(time.RFC3339),
 	}
 
-	// Marshal to JSON
-	msgBytes, err := json.Marshal(gameState)
-	if err != nil {
-		return fmt.Errorf("failed to marshal game state message: %w", err)
-	}
-
 	// Send the current game state to the rejoining player
 	if gm.wsHub != nil {
-		gm.wsHub.BroadcastToGame(gameID, msgBytes)
+		gm.broadcastWithPlayers(gameID, session.Game.Players, gameState)
 		gm.logger.Infof("Broadcasted game state to rejoining player %s in game %s", playerID, gameID)
 	} else {
 		gm.logger.Warnf("WebSocket hub is nil, cannot send game state to rejoining player")
@@ -0,0 +1,41 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// newTestManager returns a game manager without a database, hub or background tasks
+func newTestManager() *GameManager {
+	return &GameManager{
+		ctx:         context.Background(),
+		logger:      zap.NewNop().Sugar(),
+		activeGames: make(map[string]*GameSession),
+		games:       make(map[string]*models.Game),
+		dbName:      "kekopoly",
+	}
+}
+
+func TestRestoreSessionsKeepsEachGame(t *testing.T) {
+	gm := newTestManager()
+	games := []models.Game{
+		{ID: primitive.NewObjectID(), Status: models.GameStatusActive, CurrentTurn: "p1"},
+		{ID: primitive.NewObjectID(), Status: models.GameStatusActive, CurrentTurn: "p2"},
+	}
+
+	gm.restoreSessions(games)
+	require.Len(t, gm.activeGames, 2)
+	for _, game := range games {
+		session, err := gm.lookupSession(game.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, game.ID, session.Game.ID)
+		assert.Equal(t, game.CurrentTurn, session.Game.CurrentTurn)
+	}
+}
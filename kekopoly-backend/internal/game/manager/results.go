@@ -0,0 +1,91 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// ErrGameNotCompleted is returned when asking for the results of a game still being played
+var ErrGameNotCompleted = errors.New("game is not completed")
+
+// GameResults is the outcome of a completed game
+type GameResults struct {
+	GameID      string               `json:"gameId"`
+	WinnerID    string               `json:"winnerId"`
+	EndReason   models.GameEndReason `json:"endReason"`
+	CompletedAt *time.Time           `json:"completedAt,omitempty"`
+	Rounds      int                  `json:"rounds"`
+	Players     []PlayerResult       `json:"players"`
+	RNGSeed     string               `json:"rngSeed"` // Seed of the game's randomizer, revealed so the dice and draws can be checked against the commitment
+}
+
+// PlayerResult is a player's final standing along with the rent they paid and collected
+type PlayerResult struct {
+	models.Standing
+	RentCollected int `json:"rentCollected"`
+	RentPaid      int `json:"rentPaid"`
+}
+
+// GetGameResults returns the final standings of a completed game
+func (gm *GameManager) GetGameResults(gameID string) (*GameResults, error) {
+	game, err := gm.GetGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.Status != models.GameStatusCompleted {
+		return nil, ErrGameNotCompleted
+	}
+
+	collected, paid, err := gm.rentTotals(game.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	results := &GameResults{
+		GameID:      game.ID.Hex(),
+		WinnerID:    game.WinnerID,
+		EndReason:   game.EndReason,
+		CompletedAt: game.CompletedAt,
+		Rounds:      game.Round,
+		RNGSeed:     game.RNGSeed,
+		Players:     make([]PlayerResult, 0, len(game.Standings)),
+	}
+	for _, standing := range game.Standings {
+		results.Players = append(results.Players, PlayerResult{
+			Standing:      standing,
+			RentCollected: collected[standing.PlayerID],
+			RentPaid:      paid[standing.PlayerID],
+		})
+	}
+	return results, nil
+}
+
+// rentTotals adds up the rent each player of a game collected and paid
+func (gm *GameManager) rentTotals(gameID string) (collected, paid map[string]int, err error) {
+	collected = map[string]int{}
+	paid = map[string]int{}
+	if gm.mongoClient == nil {
+		return collected, paid, nil
+	}
+
+	collection := gm.mongoClient.Database(gm.dbName).Collection("transactions")
+	cursor, err := collection.Find(gm.ctx, bson.M{"gameId": gameID, "type": models.TransactionTypeRent})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load transactions: %w", err)
+	}
+	var transactions []models.Transaction
+	if err := cursor.All(gm.ctx, &transactions); err != nil {
+		return nil, nil, fmt.Errorf("failed to load transactions: %w", err)
+	}
+
+	for _, tx := range transactions {
+		collected[tx.ToPlayerID] += tx.Amount
+		paid[tx.FromPlayerID] += tx.Amount
+	}
+	return collected, paid, nil
+}
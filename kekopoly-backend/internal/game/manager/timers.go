@@ -0,0 +1,57 @@
+package manager
+
+import (
+	"time"
+
+	"github.com/kekopoly/backend/internal/game/rules"
+)
+
+// scheduleDeadline arranges for the next deadline of a game, such as the end of an
+// auction or of a liquidation window, to be applied when it passes. It replaces any
+// earlier timer since late bids and new debts move the deadline.
+// The caller must hold the session lock.
+func (gm *GameManager) scheduleDeadline(session *GameSession) {
+	if session.deadlineTimer != nil {
+		session.deadlineTimer.Stop()
+		session.deadlineTimer = nil
+	}
+
+	deadline, ok := rules.NextDeadline(session.Game)
+	if !ok {
+		return
+	}
+	gameID := session.Game.ID.Hex()
+	session.deadlineTimer = time.AfterFunc(time.Until(deadline), func() {
+		gm.applyDeadline(gameID)
+	})
+}
+
+// applyDeadline applies whatever has fallen due in a game. It does not depend on any
+// player being connected, so a bidder or debtor who drops out cannot stall the game.
+func (gm *GameManager) applyDeadline(gameID string) {
+	session, err := gm.lookupSession(gameID)
+	if err != nil {
+		return
+	}
+
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	engine, err := gm.engineFor(session)
+	if err != nil {
+		gm.logger.Errorf("Failed to apply deadline in game %s: %v", gameID, err)
+		return
+	}
+	now := time.Now().Truncate(time.Millisecond)
+	result, err := engine.Tick(session.Game, now)
+	if err != nil {
+		gm.logger.Errorf("Failed to apply deadline in game %s: %v", gameID, err)
+		return
+	}
+	if result == nil {
+		// Woken early or the deadline moved; wait for the next one
+		gm.scheduleDeadline(session)
+		return
+	}
+	gm.commitResult(session, nil, result, now)
+}
@@ -19,18 +19,137 @@ type Game struct {
 	MaxPlayers                    int                `bson:"maxPlayers" json:"maxPlayers"` // Maximum number of players allowed
 	CurrentTurn                   string             `bson:"currentTurn" json:"currentTurn"`
 	TurnOrder                     []string           `bson:"turnOrder" json:"turnOrder"`
+	BoardID                       string             `bson:"boardId" json:"boardId"`           // Board pack the game is played on
+	BoardVersion                  int                `bson:"boardVersion" json:"boardVersion"` // Version of the board pack when the game was created
 	BoardState                    BoardState         `bson:"boardState" json:"boardState"`
 	LastActivity                  time.Time          `bson:"lastActivity" json:"lastActivity"`
 	MarketCondition               MarketCondition    `bson:"marketCondition" json:"marketCondition"`
-	MarketConditionRemainingTurns int                `bson:"marketConditionRemainingTurns" json:"marketConditionRemainingTurns"`
+	MarketConditionRemainingTurns int                `bson:"marketConditionRemainingTurns" json:"marketConditionRemainingTurns"` // Rounds left before the market returns to NORMAL
+	Round                         int                `bson:"round" json:"round"`                                                 // Current round; a round ends when play wraps around the turn order
 	WinnerID                      string             `bson:"winnerId,omitempty" json:"winnerId,omitempty"`
+	Mode                          GameMode           `bson:"mode,omitempty" json:"mode,omitempty"`                             // How the game ends; empty is the same as CLASSIC
+	TimeLimitMinutes              int                `bson:"timeLimitMinutes,omitempty" json:"timeLimitMinutes,omitempty"`     // Length of a TIMED game
+	RoundLimit                    int                `bson:"roundLimit,omitempty" json:"roundLimit,omitempty"`                 // Rounds after which the game ends by net worth; zero for no limit
+	EndsAt                        *time.Time         `bson:"endsAt,omitempty" json:"endsAt,omitempty"`                         // Time at which the game ends by net worth, set when a timed game starts
+	TimeWarningsSent              int                `bson:"timeWarningsSent,omitempty" json:"-"`                              // Warnings already given that a timed game is ending
+	TurnTimeoutSeconds            int                `bson:"turnTimeoutSeconds,omitempty" json:"turnTimeoutSeconds,omitempty"` // Time each player has for their turn; zero for no turn clock
+	EndReason                     GameEndReason      `bson:"endReason,omitempty" json:"endReason,omitempty"`                   // Why the game finished
+	CompletedAt                   *time.Time         `bson:"completedAt,omitempty" json:"completedAt,omitempty"`               // When the game finished
+	Standings                     []Standing         `bson:"standings,omitempty" json:"standings,omitempty"`                   // Final placements, first place first
 	SettlementStatus              SettlementStatus   `bson:"settlementStatus" json:"settlementStatus"`
+	TurnState                     TurnState          `bson:"turnState" json:"turnState"`
+	LastRoll                      *DiceRoll          `bson:"lastRoll,omitempty" json:"lastRoll,omitempty"`
+	RNGSeed                       string             `bson:"rngSeed" json:"-"`                         // Hex seed of the game's randomizer, kept secret until the game is completed and its results reveal it
+	RNGCommitment                 string             `bson:"rngCommitment" json:"rngCommitment"`       // SHA-256 of the seed, published so it can be verified later
+	RNGDraws                      int                `bson:"rngDraws" json:"rngDraws"`                 // Number of random values drawn so far
+	Trades                        []Trade            `bson:"trades,omitempty" json:"trades,omitempty"` // Trade offers still waiting for an answer
+	EventSeq                      int                `bson:"eventSeq" json:"eventSeq"`                 // Sequence number of the latest entry in the game's history
+}
+
+// Standing is a player's final placement in a completed game along with their stats
+type Standing struct {
+	PlayerID    string       `bson:"playerId" json:"playerId"`
+	Placement   int          `bson:"placement" json:"placement"`
+	Status      PlayerStatus `bson:"status" json:"status"`
+	NetWorth    int          `bson:"netWorth" json:"netWorth"`
+	Balance     int          `bson:"balance" json:"balance"`
+	Properties  int          `bson:"properties" json:"properties"`
+	Mortgaged   int          `bson:"mortgaged" json:"mortgaged"`
+	Engagements int          `bson:"engagements" json:"engagements"`
+	Checkmarks  int          `bson:"checkmarks" json:"checkmarks"`
+}
+
+// DiceRoll records a roll of the two dice made by the server
+type DiceRoll struct {
+	PlayerID     string    `bson:"playerId" json:"playerId"`
+	Dice1        int       `bson:"dice1" json:"dice1"`
+	Dice2        int       `bson:"dice2" json:"dice2"`
+	Total        int       `bson:"total" json:"total"`
+	Doubles      bool      `bson:"doubles" json:"doubles"`
+	DoublesCount int       `bson:"doublesCount" json:"doublesCount"` // Consecutive doubles rolled this turn
+	FromPosition int       `bson:"fromPosition" json:"fromPosition"`
+	ToPosition   int       `bson:"toPosition" json:"toPosition"`
+	PassedStart  bool      `bson:"passedStart" json:"passedStart"`
+	RequestID    string    `bson:"requestId,omitempty" json:"requestId,omitempty"`
+	Timestamp    time.Time `bson:"timestamp" json:"timestamp"`
+}
+
+// TurnState tracks what the current player has done and still owes during their turn
+type TurnState struct {
+	HasRolled       bool       `bson:"hasRolled" json:"hasRolled"`
+	DoublesCount    int        `bson:"doublesCount" json:"doublesCount"`                           // Consecutive doubles this turn; doubles earn another roll
+	PendingPurchase string     `bson:"pendingPurchase,omitempty" json:"pendingPurchase,omitempty"` // Property ID the player may buy
+	PendingRent     *Debt      `bson:"pendingRent,omitempty" json:"pendingRent,omitempty"`
+	PendingDraw     CardType   `bson:"pendingDraw,omitempty" json:"pendingDraw,omitempty"` // Deck the player landed on and may draw from
+	PendingCard     *Card      `bson:"pendingCard,omitempty" json:"pendingCard,omitempty"` // Drawn card waiting for the player to choose its targets
+	PendingVote     *Vote      `bson:"pendingVote,omitempty" json:"pendingVote,omitempty"`
+	Auction         *Auction   `bson:"auction,omitempty" json:"auction,omitempty"`   // Auction of a property the player declined to buy
+	Deadline        *time.Time `bson:"deadline,omitempty" json:"deadline,omitempty"` // When the turn times out and is played for the player
+	Warned          bool       `bson:"warned,omitempty" json:"-"`                    // Whether the player was warned that their turn is about to time out
+	TimedOut        bool       `bson:"timedOut,omitempty" json:"timedOut,omitempty"` // Whether the turn timed out and is being finished for the player
+}
+
+// Auction is a timed auction of a property the current player declined to buy. Every
+// late bid pushes the end back, and the highest bidder still able to pay wins.
+type Auction struct {
+	PropertyID   string    `bson:"propertyId" json:"propertyId"`
+	DeclinedBy   string    `bson:"declinedBy" json:"declinedBy"` // Player who declined the property, or went bankrupt owning it
+	MinIncrement int       `bson:"minIncrement" json:"minIncrement"`
+	Bids         []Bid     `bson:"bids" json:"bids"` // In the order they were placed, each higher than the last
+	EndsAt       time.Time `bson:"endsAt" json:"endsAt"`
+	Queue        []string  `bson:"queue,omitempty" json:"queue,omitempty"` // Properties of a bankrupt player to auction after this one
+}
+
+// Bid is a bid placed in an auction
+type Bid struct {
+	PlayerID string    `bson:"playerId" json:"playerId"`
+	Amount   int       `bson:"amount" json:"amount"`
+	PlacedAt time.Time `bson:"placedAt" json:"placedAt"`
+}
+
+// Vote is a vote called by a card on which player must forfeit kekels. It closes
+// once every player has voted or the turn ends.
+type Vote struct {
+	CardID   string            `bson:"cardId" json:"cardId"`
+	CalledBy string            `bson:"calledBy" json:"calledBy"`
+	Amount   int               `bson:"amount" json:"amount"`
+	Votes    map[string]string `bson:"votes" json:"votes"` // Voter ID to the player they voted for
+}
+
+// Debt represents an amount one player owes to another player or to the bank
+type Debt struct {
+	CreditorID string     `bson:"creditorId,omitempty" json:"creditorId,omitempty"` // Empty means the bank
+	Amount     int        `bson:"amount" json:"amount"`
+	PropertyID string     `bson:"propertyId,omitempty" json:"propertyId,omitempty"`
+	DueBy      *time.Time `bson:"dueBy,omitempty" json:"dueBy,omitempty"` // Set when the debtor cannot pay; they go bankrupt if it is still unpaid by then
 }
 
 // BoardState represents the current state of the game board
 type BoardState struct {
-	Properties     []Property `bson:"properties" json:"properties"`
-	CardsRemaining CardCount  `bson:"cardsRemaining" json:"cardsRemaining"`
+	Properties     []Property             `bson:"properties" json:"properties"`
+	CardsRemaining CardCount              `bson:"cardsRemaining" json:"cardsRemaining"`
+	BuildingSupply *BuildingSupply        `bson:"buildingSupply,omitempty" json:"buildingSupply,omitempty"`
+	Decks          map[CardType]*CardDeck `bson:"decks,omitempty" json:"decks,omitempty"`
+	PlayedCards    []PlayedCard           `bson:"playedCards,omitempty" json:"playedCards,omitempty"` // Cards played this round and last
+}
+
+// CardDeck is one of the game's card decks. The order of the draw pile is kept from clients.
+type CardDeck struct {
+	DrawPile    []string `bson:"drawPile" json:"-"`
+	DiscardPile []string `bson:"discardPile" json:"discardPile"`
+}
+
+// PlayedCard records a card that a player played
+type PlayedCard struct {
+	CardID   string `bson:"cardId" json:"cardId"`
+	PlayerID string `bson:"playerId" json:"playerId"`
+	Round    int    `bson:"round" json:"round"`
+}
+
+// BuildingSupply is the number of buildings the bank has left to sell
+type BuildingSupply struct {
+	Engagements int `bson:"engagements" json:"engagements"`
+	Checkmarks  int `bson:"checkmarks" json:"checkmarks"`
 }
 
 // CardCount represents the count of different card types remaining
@@ -42,19 +161,27 @@ type CardCount struct {
 
 // Player represents a player in the game
 type Player struct {
-	ID                      string       `bson:"playerId" json:"playerId"`
-	UserID                  string       `bson:"userId" json:"userId"`
-	CharacterToken          string       `bson:"characterToken" json:"characterToken"`
-	Position                int          `bson:"position" json:"position"`
-	Balance                 int          `bson:"balance" json:"balance"`
-	Cards                   []Card       `bson:"cards" json:"cards"`
-	Shadowbanned            bool         `bson:"shadowbanned" json:"shadowbanned"`
-	ShadowbanRemainingTurns int          `bson:"shadowbanRemainingTurns" json:"shadowbanRemainingTurns"`
-	Status                  PlayerStatus `bson:"status" json:"status"`
-	DisconnectedAt          *time.Time   `bson:"disconnectedAt,omitempty" json:"disconnectedAt,omitempty"`
-	Properties              []string     `bson:"properties" json:"properties"`
-	InitialDeposit          int          `bson:"initialDeposit" json:"initialDeposit"`
-	NetWorth                int          `bson:"netWorth" json:"netWorth"`
+	ID                      string          `bson:"playerId" json:"playerId"`
+	UserID                  string          `bson:"userId" json:"userId"`
+	CharacterToken          string          `bson:"characterToken" json:"characterToken"`
+	Position                int             `bson:"position" json:"position"`
+	Balance                 int             `bson:"balance" json:"balance"`
+	Cards                   []Card          `bson:"cards" json:"cards"`
+	HandSize                int             `bson:"-" json:"handSize,omitempty"` // Number of cards in hand, sent in place of the cards to other players
+	Shadowbanned            bool            `bson:"shadowbanned" json:"shadowbanned"`
+	ShadowbanRemainingTurns int             `bson:"shadowbanRemainingTurns" json:"shadowbanRemainingTurns"`
+	Status                  PlayerStatus    `bson:"status" json:"status"`
+	DisconnectedAt          *time.Time      `bson:"disconnectedAt,omitempty" json:"disconnectedAt,omitempty"`
+	Properties              []string        `bson:"properties" json:"properties"`
+	StatusEffects           []SpecialEffect `bson:"statusEffects,omitempty" json:"statusEffects,omitempty"` // Lasting card effects on the player
+	InitialDeposit          int             `bson:"initialDeposit" json:"initialDeposit"`
+	NetWorth                int             `bson:"netWorth" json:"netWorth"`
+	Placement               int             `bson:"placement,omitempty" json:"placement,omitempty"`         // Final placement, set when the player goes bankrupt or the game ends
+	Timeouts                int             `bson:"timeouts,omitempty" json:"timeouts,omitempty"`           // Turns in a row that timed out
+	AFK                     bool            `bson:"afk,omitempty" json:"afk,omitempty"`                     // Set after repeated timeouts, until the player acts again
+	BotControlled           bool            `bson:"botControlled,omitempty" json:"botControlled,omitempty"` // A bot is playing for the player while they are away
+	IsBot                   bool            `bson:"isBot,omitempty" json:"isBot,omitempty"`                 // An AI opponent added by the host, with no connection of its own
+	BotDifficulty           BotDifficulty   `bson:"botDifficulty,omitempty" json:"botDifficulty,omitempty"`
 	// WebSocket session ID is not stored in the database
 	SessionID string `bson:"-" json:"sessionId,omitempty"`
 	// --- Jail fields ---
@@ -73,6 +200,9 @@ type Property struct {
 	Price          int             `bson:"price" json:"price"`
 	RentBase       int             `bson:"rentBase" json:"rentBase"`
 	RentCurrent    int             `bson:"rentCurrent" json:"rentCurrent"`
+	RentTable      []int           `bson:"rentTable,omitempty" json:"rentTable,omitempty"` // Rent by development level, or by number owned for transit
+	BuildCost      int             `bson:"buildCost,omitempty" json:"buildCost,omitempty"`
+	MortgageValue  int             `bson:"mortgageValue,omitempty" json:"mortgageValue,omitempty"`
 	Mortgaged      bool            `bson:"mortgaged" json:"mortgaged"`
 	Engagements    int             `bson:"engagements" json:"engagements"`
 	BlueCheckmark  bool            `bson:"blueCheckmark" json:"blueCheckmark"`
@@ -80,7 +210,7 @@ type Property struct {
 	MemeName       string          `bson:"memeName,omitempty" json:"memeName,omitempty"`
 }
 
-// SpecialEffect represents a special effect applied to a property
+// SpecialEffect represents a special effect applied to a property or a player
 type SpecialEffect struct {
 	Type              string `bson:"type" json:"type"`
 	AppliedBy         string `bson:"appliedBy" json:"appliedBy"`
@@ -96,6 +226,7 @@ type Card struct {
 	Effect      string     `bson:"effect" json:"effect"`
 	Description string     `bson:"description" json:"description"`
 	ImageURL    string     `bson:"imageUrl" json:"imageUrl"`
+	Timing      string     `bson:"timing,omitempty" json:"timing,omitempty"` // When a card held in hand may be played
 }
 
 // Transaction represents a financial transaction in the game
@@ -114,13 +245,36 @@ type Transaction struct {
 	Signature     string          `bson:"signature,omitempty" json:"signature,omitempty"`
 }
 
+// Trade is an offer from one player to another to swap properties, kekels and held cards
+type Trade struct {
+	ID           string      `bson:"_id" json:"tradeId"`
+	GameID       string      `bson:"gameId" json:"gameId"`
+	ProposerID   string      `bson:"proposerId" json:"proposerId"`
+	RecipientID  string      `bson:"recipientId" json:"recipientId"`
+	Offered      TradeOffer  `bson:"offered" json:"offered"`     // What the proposer gives
+	Requested    TradeOffer  `bson:"requested" json:"requested"` // What the proposer asks for in return
+	Status       TradeStatus `bson:"status" json:"status"`
+	CounterOf    string      `bson:"counterOf,omitempty" json:"counterOf,omitempty"` // Trade this one answers with new terms
+	ExpiresRound int         `bson:"expiresRound" json:"expiresRound"`               // Last round in which the trade can be accepted
+	CreatedAt    time.Time   `bson:"createdAt" json:"createdAt"`
+	ResolvedAt   *time.Time  `bson:"resolvedAt,omitempty" json:"resolvedAt,omitempty"`
+}
+
+// TradeOffer is what one side of a trade hands over
+type TradeOffer struct {
+	Properties []string `bson:"properties,omitempty" json:"properties,omitempty"`
+	Kekels     int      `bson:"kekels" json:"kekels"`
+	Cards      []string `bson:"cards,omitempty" json:"cards,omitempty"` // IDs of cards held in hand
+}
+
 // GameAction represents an action in the game
 type GameAction struct {
-	Type      ActionType  `json:"type"`
-	PlayerID  string      `json:"playerId"`
-	GameID    string      `json:"gameId"`
-	Payload   interface{} `json:"payload,omitempty"`
-	Timestamp time.Time   `json:"timestamp"`
+	Type      ActionType  `bson:"type" json:"type"`
+	PlayerID  string      `bson:"playerId" json:"playerId"`
+	GameID    string      `bson:"gameId" json:"gameId"`
+	Payload   interface{} `bson:"payload,omitempty" json:"payload,omitempty"`
+	Timestamp time.Time   `bson:"timestamp" json:"timestamp"`
+	ByBot     bool        `bson:"byBot,omitempty" json:"byBot,omitempty"` // Made by the server's bot on the player's behalf
 }
 
 // GameStatus represents the status of a game
@@ -134,6 +288,45 @@ const (
 	GameStatusAbandoned GameStatus = "ABANDONED"
 )
 
+// TradeStatus represents the status of a trade
+type TradeStatus string
+
+const (
+	TradeStatusPending   TradeStatus = "PENDING"
+	TradeStatusAccepted  TradeStatus = "ACCEPTED"
+	TradeStatusRejected  TradeStatus = "REJECTED"
+	TradeStatusCountered TradeStatus = "COUNTERED"
+	TradeStatusCancelled TradeStatus = "CANCELLED"
+	TradeStatusExpired   TradeStatus = "EXPIRED"
+)
+
+// GameMode represents how a game ends
+type GameMode string
+
+const (
+	GameModeClassic     GameMode = "CLASSIC"      // Played until one player is left
+	GameModeTimed       GameMode = "TIMED"        // Ends by net worth once the time limit runs out
+	GameModeRoundCapped GameMode = "ROUND_CAPPED" // Ends by net worth after a number of rounds
+)
+
+// GameEndReason represents why a game finished
+type GameEndReason string
+
+const (
+	GameEndReasonLastPlayerStanding GameEndReason = "LAST_PLAYER_STANDING"
+	GameEndReasonRoundLimit         GameEndReason = "ROUND_LIMIT"
+	GameEndReasonTimeLimit          GameEndReason = "TIME_LIMIT"
+)
+
+// BotDifficulty represents how an AI opponent plays
+type BotDifficulty string
+
+const (
+	BotDifficultyCautious   BotDifficulty = "CAUTIOUS"   // Keeps a large reserve and rarely overpays
+	BotDifficultyBalanced   BotDifficulty = "BALANCED"   // Buys and builds steadily
+	BotDifficultyAggressive BotDifficulty = "AGGRESSIVE" // Spends down to its last kekels to grow
+)
+
 // PlayerStatus represents the status of a player
 type PlayerStatus string
 
@@ -197,13 +390,20 @@ const (
 type TransactionType string
 
 const (
-	TransactionTypePurchase       TransactionType = "PURCHASE"
-	TransactionTypeRent           TransactionType = "RENT"
-	TransactionTypeCardEffect     TransactionType = "CARD_EFFECT"
-	TransactionTypeSalary         TransactionType = "SALARY"
-	TransactionTypePenalty        TransactionType = "PENALTY"
-	TransactionTypeGameSettlement TransactionType = "GAME_SETTLEMENT"
-	TransactionTypeDeposit        TransactionType = "DEPOSIT"
+	TransactionTypePurchase         TransactionType = "PURCHASE"
+	TransactionTypeRent             TransactionType = "RENT"
+	TransactionTypeCardEffect       TransactionType = "CARD_EFFECT"
+	TransactionTypeSalary           TransactionType = "SALARY"
+	TransactionTypeCollect          TransactionType = "COLLECT"
+	TransactionTypePenalty          TransactionType = "PENALTY"
+	TransactionTypeGameSettlement   TransactionType = "GAME_SETTLEMENT"
+	TransactionTypeDeposit          TransactionType = "DEPOSIT"
+	TransactionTypeMortgage         TransactionType = "MORTGAGE"
+	TransactionTypeUnmortgage       TransactionType = "UNMORTGAGE"
+	TransactionTypeMortgageInterest TransactionType = "MORTGAGE_INTEREST"
+	TransactionTypeSpecialEffect    TransactionType = "SPECIAL_EFFECT"
+	TransactionTypeTrade            TransactionType = "TRADE"
+	TransactionTypeBankruptcy       TransactionType = "BANKRUPTCY"
 )
 
 // OnChainStatus represents the status of an on-chain transaction
@@ -221,14 +421,46 @@ type ActionType string
 const (
 	ActionTypeRollDice           ActionType = "ROLL_DICE"
 	ActionTypeBuyProperty        ActionType = "BUY_PROPERTY"
+	ActionTypeDeclineProperty    ActionType = "DECLINE_PROPERTY"
+	ActionTypePlaceBid           ActionType = "PLACE_BID"
+	ActionTypeCloseAuction       ActionType = "CLOSE_AUCTION" // Closes an auction whose countdown has run out
+	ActionTypeDeclareBankruptcy  ActionType = "DECLARE_BANKRUPTCY"
+	ActionTypeTimeout            ActionType = "TIMEOUT" // Applied by the server when a countdown runs out
 	ActionTypePayRent            ActionType = "PAY_RENT"
 	ActionTypeDrawCard           ActionType = "DRAW_CARD"
 	ActionTypeUseCard            ActionType = "USE_CARD"
+	ActionTypeCastVote           ActionType = "CAST_VOTE"
 	ActionTypeMortgageProperty   ActionType = "MORTGAGE_PROPERTY"
 	ActionTypeUnmortgageProperty ActionType = "UNMORTGAGE_PROPERTY"
 	ActionTypeBuildEngagement    ActionType = "BUILD_ENGAGEMENT"
 	ActionTypeBuildCheckmark     ActionType = "BUILD_CHECKMARK"
+	ActionTypeSellEngagement     ActionType = "SELL_ENGAGEMENT"
+	ActionTypeSellCheckmark      ActionType = "SELL_CHECKMARK"
+	ActionTypePayJailFine        ActionType = "PAY_JAIL_FINE"
+	ActionTypeUseJailCard        ActionType = "USE_JAIL_CARD"
 	ActionTypeEndTurn            ActionType = "END_TURN"
 	ActionTypeTrade              ActionType = "TRADE"
 	ActionTypeSpecial            ActionType = "SPECIAL"
 )
+
+// VisibleTo returns a copy of the game as one player may see it, with every other
+// player's hand reduced to its size. An empty viewer ID hides every hand.
+func (g *Game) VisibleTo(viewerID string) *Game {
+	visible := *g
+	visible.Players = PlayersVisibleTo(g.Players, viewerID)
+	return &visible
+}
+
+// PlayersVisibleTo returns a copy of the players as one player may see them, with
+// every other player's hand reduced to its size
+func PlayersVisibleTo(players []Player, viewerID string) []Player {
+	visible := make([]Player, len(players))
+	for i, player := range players {
+		player.HandSize = len(player.Cards)
+		if player.ID != viewerID {
+			player.Cards = []Card{}
+		}
+		visible[i] = player
+	}
+	return visible
+}
@@ -0,0 +1,283 @@
+// Package replay plays back the recorded history of a completed game to a viewer, with
+// the original timing or faster, without touching any live game.
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kekopoly/backend/internal/game/history"
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// maxGap caps the pause between two entries, so a stretch where the game sat idle does
+// not stall its replay
+const maxGap = 30 * time.Second
+
+// Speeds are the playback speeds a viewer can choose, as multiples of the original pace
+var Speeds = []int{1, 2, 4}
+
+// Controls a viewer sends to steer the playback
+const (
+	ControlSpeed  = "speed"
+	ControlSeek   = "seek"
+	ControlPause  = "pause"
+	ControlResume = "resume"
+)
+
+// ErrNoStart is returned for a history that does not start with a snapshot of the game
+var ErrNoStart = errors.New("history does not start with a snapshot of the game")
+
+// Control is a viewer's request to change the playback
+type Control struct {
+	Type  string `json:"type"`
+	Speed int    `json:"speed,omitempty"` // New speed of a speed control
+	Turn  int    `json:"turn,omitempty"`  // Turn to jump to with a seek control, counting from one
+}
+
+// Options set up a playback
+type Options struct {
+	ViewerID string // Player watching, who also sees their own private events; empty for a spectator
+	Speed    int    // One of Speeds; zero plays at the original pace
+	Turn     int    // Turn to start from; zero starts from the first
+}
+
+// Timeline is the recorded history of a game, split into turns
+type Timeline struct {
+	gameID    string
+	events    []history.Event
+	turns     []int // Index of the first entry of each turn
+	newEngine history.EngineFactory
+	after     func(time.Duration) <-chan time.Time
+}
+
+// NewTimeline splits a game's history into turns. A turn starts after the opening
+// snapshot and after every entry that ended a turn.
+func NewTimeline(events []history.Event, newEngine history.EngineFactory) (*Timeline, error) {
+	if len(events) == 0 || events[0].Kind != history.KindSnapshot {
+		return nil, ErrNoStart
+	}
+
+	t := &Timeline{
+		gameID:    events[0].GameID,
+		events:    events,
+		turns:     []int{1},
+		newEngine: newEngine,
+		after:     time.After,
+	}
+	for i, event := range events {
+		if endsTurn(event) && i+1 < len(events) {
+			t.turns = append(t.turns, i+1)
+		}
+	}
+	return t, nil
+}
+
+func endsTurn(event history.Event) bool {
+	for _, change := range event.Changes {
+		if change.Type == "turn_ended" {
+			return true
+		}
+	}
+	return false
+}
+
+// Turns returns how many turns the game lasted
+func (t *Timeline) Turns() int {
+	return len(t.turns)
+}
+
+// StateAt returns the game as it stood at the start of a turn, rebuilt from the latest
+// snapshot before it
+func (t *Timeline) StateAt(turn int) (*models.Game, error) {
+	if turn < 1 || turn > len(t.turns) {
+		return nil, fmt.Errorf("turn must be between 1 and %d", len(t.turns))
+	}
+	end := t.turns[turn-1]
+	start := 0
+	for i := end - 1; i >= 0; i-- {
+		if t.events[i].Kind == history.KindSnapshot {
+			start = i
+			break
+		}
+	}
+	return history.Fold(t.events[start:end], t.newEngine)
+}
+
+// turnOf returns the turn an entry belongs to
+func (t *Timeline) turnOf(index int) int {
+	turn := 1
+	for i, start := range t.turns {
+		if start <= index {
+			turn = i + 1
+		}
+	}
+	return turn
+}
+
+// gap returns how long to wait before playing an entry at the given speed
+func (t *Timeline) gap(index, speed int) time.Duration {
+	if index == 0 {
+		return 0
+	}
+	gap := t.events[index].Timestamp.Sub(t.events[index-1].Timestamp)
+	if gap < 0 {
+		gap = 0
+	}
+	if gap > maxGap {
+		gap = maxGap
+	}
+	return gap / time.Duration(speed)
+}
+
+// ValidSpeed reports whether a viewer may choose a playback speed
+func ValidSpeed(speed int) bool {
+	for _, s := range Speeds {
+		if s == speed {
+			return true
+		}
+	}
+	return false
+}
+
+// Play streams the timeline to a viewer through send, in the message format of the live
+// game with replay set, until the context is done or controls is closed. It starts with
+// a replay_state message holding the game at the starting turn and sends another on every
+// seek. Once the last entry has been played it sends replay_finished and waits, so the
+// viewer can still seek back.
+func (t *Timeline) Play(ctx context.Context, opts Options, controls <-chan Control, send func(map[string]interface{}) error) error {
+	speed := opts.Speed
+	if speed == 0 {
+		speed = 1
+	}
+	if !ValidSpeed(speed) {
+		return fmt.Errorf("unsupported speed %d", speed)
+	}
+	turn := opts.Turn
+	if turn == 0 {
+		turn = 1
+	}
+
+	if err := send(map[string]interface{}{
+		"type":   "replay_started",
+		"gameId": t.gameID,
+		"turns":  len(t.turns),
+		"speed":  speed,
+	}); err != nil {
+		return err
+	}
+	next, err := t.seek(turn, opts.ViewerID, send)
+	if err != nil {
+		return err
+	}
+
+	paused, finished := false, false
+	for {
+		var due <-chan time.Time
+		if next < len(t.events) && !paused {
+			due = t.after(t.gap(next, speed))
+		}
+		if next == len(t.events) && !finished {
+			finished = true
+			if err := send(map[string]interface{}{"type": "replay_finished", "gameId": t.gameID}); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-due:
+			if err := t.playEntry(next, opts.ViewerID, send); err != nil {
+				return err
+			}
+			next++
+		case control, ok := <-controls:
+			if !ok {
+				return nil
+			}
+			switch control.Type {
+			case ControlSpeed:
+				if !ValidSpeed(control.Speed) {
+					err = sendError(t.gameID, fmt.Sprintf("speed must be one of %v", Speeds), send)
+					break
+				}
+				speed = control.Speed
+			case ControlSeek:
+				if control.Turn < 1 || control.Turn > len(t.turns) {
+					err = sendError(t.gameID, fmt.Sprintf("turn must be between 1 and %d", len(t.turns)), send)
+					break
+				}
+				if next, err = t.seek(control.Turn, opts.ViewerID, send); err == nil {
+					finished = false
+				}
+			case ControlPause:
+				paused = true
+			case ControlResume:
+				paused = false
+			default:
+				err = sendError(t.gameID, fmt.Sprintf("unknown control %q", control.Type), send)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// seek sends the game as it stood at the start of a turn, as the viewer may see it, and
+// returns the index of the turn's first entry
+func (t *Timeline) seek(turn int, viewerID string, send func(map[string]interface{}) error) (int, error) {
+	game, err := t.StateAt(turn)
+	if err != nil {
+		return 0, err
+	}
+	index := t.turns[turn-1]
+	return index, send(map[string]interface{}{
+		"type":   "replay_state",
+		"gameId": t.gameID,
+		"turn":   turn,
+		"seq":    t.events[index-1].Seq,
+		"game":   game.VisibleTo(viewerID),
+	})
+}
+
+// playEntry sends what an entry changed as the viewer would have seen it live. A
+// snapshot sends the whole game again.
+func (t *Timeline) playEntry(index int, viewerID string, send func(map[string]interface{}) error) error {
+	event := history.VisibleTo(t.events[index:index+1], viewerID)[0]
+	turn := t.turnOf(index)
+
+	if event.Kind == history.KindSnapshot {
+		return send(map[string]interface{}{
+			"type":   "replay_state",
+			"gameId": t.gameID,
+			"turn":   turn,
+			"seq":    event.Seq,
+			"game":   event.State,
+		})
+	}
+
+	for _, change := range event.Changes {
+		msg := change.Message(t.gameID, event.Timestamp)
+		msg["seq"] = event.Seq
+		msg["turn"] = turn
+		msg["replay"] = true
+		if event.Action != nil {
+			msg["action"] = event.Action.Type
+			if event.Action.ByBot {
+				msg["byBot"] = true
+			}
+		}
+		if err := send(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sendError(gameID, message string, send func(map[string]interface{}) error) error {
+	return send(map[string]interface{}{"type": "error", "gameId": gameID, "message": message})
+}
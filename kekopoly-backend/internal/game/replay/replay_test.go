@@ -0,0 +1,250 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/kekopoly/backend/internal/game/board"
+	"github.com/kekopoly/backend/internal/game/bot"
+	"github.com/kekopoly/backend/internal/game/history"
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rng"
+	"github.com/kekopoly/backend/internal/game/rules"
+)
+
+var testSeed = []byte("replay-test-seed")
+
+// recordedGame is a bot game with its history and the game as it stood at the start of
+// every turn
+type recordedGame struct {
+	events    []history.Event
+	turns     []string // JSON of the game at the start of each turn
+	newEngine history.EngineFactory
+}
+
+func jsonOf(t *testing.T, game *models.Game) string {
+	data, err := json.Marshal(game)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// recordGame plays a few turns between two bots, keeping their history as the game
+// manager would
+func recordGame(t *testing.T, turns int) *recordedGame {
+	b, err := board.NewRegistry().Get("")
+	require.NoError(t, err)
+	rec := &recordedGame{newEngine: func(game *models.Game) (*rules.Engine, error) {
+		return rules.NewEngine(b, rng.NewHMAC(testSeed, game.RNGDraws)), nil
+	}}
+	engine, err := rec.newEngine(&models.Game{})
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0).UTC()
+	game := &models.Game{
+		ID:     primitive.NewObjectID(),
+		Status: models.GameStatusActive,
+		BoardState: models.BoardState{
+			Properties:     b.NewProperties(),
+			BuildingSupply: rules.NewBuildingSupply(),
+		},
+		MarketCondition: models.MarketConditionNormal,
+		Round:           1,
+	}
+	for _, id := range []string{"p1", "p2"} {
+		game.Players = append(game.Players, models.Player{
+			ID: id, Status: models.PlayerStatusActive, Balance: 1500, Position: b.StartPosition(),
+			Cards: []models.Card{}, Properties: []string{}, IsBot: true, BotDifficulty: models.BotDifficultyBalanced,
+		})
+		game.TurnOrder = append(game.TurnOrder, id)
+	}
+	engine.SetupDecks(game)
+	game.CurrentTurn = "p1"
+
+	appendEvent := func(event history.Event) {
+		game.EventSeq++
+		event.Seq = game.EventSeq
+		if event.State != nil {
+			event.State.EventSeq = event.Seq
+		}
+		rec.events = append(rec.events, event)
+	}
+	snapshot, err := history.Snapshot(game, now)
+	require.NoError(t, err)
+	appendEvent(snapshot)
+	rec.turns = append(rec.turns, jsonOf(t, game))
+
+	isBot := func(player *models.Player) bool { return player.IsBot }
+	move := func() {
+		action, ok := bot.NextMove(game, isBot)
+		require.True(t, ok)
+		now = now.Add(2 * time.Second)
+		action.Timestamp = now
+		result, err := engine.Apply(game, action)
+		require.NoError(t, err)
+		appendEvent(history.Record(game.ID.Hex(), &action, result, now))
+	}
+	for len(rec.turns) <= turns {
+		move()
+		if endsTurn(rec.events[len(rec.events)-1]) {
+			rec.turns = append(rec.turns, jsonOf(t, game))
+		}
+	}
+	// The last turn gets under way, or the timeline would not count it
+	move()
+	return rec
+}
+
+func (r *recordedGame) timeline(t *testing.T) *Timeline {
+	timeline, err := NewTimeline(r.events, r.newEngine)
+	require.NoError(t, err)
+	return timeline
+}
+
+// player runs a playback in the background and hands over what it sends
+type player struct {
+	messages chan map[string]interface{}
+	controls chan Control
+	cancel   context.CancelFunc
+	done     chan error
+}
+
+func play(timeline *Timeline, opts Options) *player {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &player{
+		messages: make(chan map[string]interface{}, 1000),
+		controls: make(chan Control),
+		cancel:   cancel,
+		done:     make(chan error, 1),
+	}
+	go func() {
+		p.done <- timeline.Play(ctx, opts, p.controls, func(msg map[string]interface{}) error {
+			p.messages <- msg
+			return nil
+		})
+	}()
+	return p
+}
+
+func (p *player) next(t *testing.T) map[string]interface{} {
+	select {
+	case msg := <-p.messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message from the replay")
+		return nil
+	}
+}
+
+func (p *player) stop(t *testing.T) {
+	p.cancel()
+	require.NoError(t, <-p.done)
+}
+
+// never stands in for a timer that does not fire, so only controls move the playback
+func never(time.Duration) <-chan time.Time {
+	return nil
+}
+
+func TestNewTimelineSplitsTurns(t *testing.T) {
+	rec := recordGame(t, 4)
+	timeline := rec.timeline(t)
+	assert.Equal(t, len(rec.turns), timeline.Turns())
+
+	_, err := NewTimeline(rec.events[1:], rec.newEngine)
+	assert.ErrorIs(t, err, ErrNoStart)
+}
+
+func TestStateAtRebuildsStartOfTurn(t *testing.T) {
+	rec := recordGame(t, 4)
+	timeline := rec.timeline(t)
+
+	for turn := 1; turn <= timeline.Turns(); turn++ {
+		game, err := timeline.StateAt(turn)
+		require.NoError(t, err)
+		assert.Equal(t, rec.turns[turn-1], jsonOf(t, game), "turn %d", turn)
+	}
+	_, err := timeline.StateAt(timeline.Turns() + 1)
+	assert.Error(t, err)
+}
+
+func TestPlayStreamsWholeGame(t *testing.T) {
+	rec := recordGame(t, 3)
+	timeline := rec.timeline(t)
+	var waits []time.Duration
+	timeline.after = func(d time.Duration) <-chan time.Time {
+		waits = append(waits, d)
+		ch := make(chan time.Time, 1)
+		ch <- time.Time{}
+		return ch
+	}
+
+	p := play(timeline, Options{Speed: 2})
+	started := p.next(t)
+	assert.Equal(t, "replay_started", started["type"])
+	assert.Equal(t, 2, started["speed"])
+	state := p.next(t)
+	assert.Equal(t, "replay_state", state["type"])
+	assert.Equal(t, 1, state["turn"])
+
+	changes := 0
+	for _, event := range rec.events[1:] {
+		changes += len(history.VisibleTo([]history.Event{event}, "")[0].Changes)
+	}
+	for i := 0; i < changes; i++ {
+		msg := p.next(t)
+		assert.Equal(t, true, msg["replay"])
+		assert.NotEmpty(t, msg["seq"])
+		assert.NotEmpty(t, msg["action"])
+	}
+	assert.Equal(t, "replay_finished", p.next(t)["type"])
+	p.stop(t)
+
+	// Moves were two seconds apart, played at twice the speed
+	require.Len(t, waits, len(rec.events)-1)
+	for _, wait := range waits {
+		assert.Equal(t, time.Second, wait)
+	}
+}
+
+func TestPlaySeeksAndChangesSpeed(t *testing.T) {
+	rec := recordGame(t, 4)
+	timeline := rec.timeline(t)
+	timeline.after = never
+
+	p := play(timeline, Options{Turn: 2})
+	assert.Equal(t, "replay_started", p.next(t)["type"])
+	state := p.next(t)
+	assert.Equal(t, 2, state["turn"])
+	assert.Equal(t, rec.turns[1], jsonOf(t, state["game"].(*models.Game)))
+
+	p.controls <- Control{Type: ControlSeek, Turn: 4}
+	state = p.next(t)
+	assert.Equal(t, "replay_state", state["type"])
+	assert.Equal(t, 4, state["turn"])
+	assert.Equal(t, rec.turns[3], jsonOf(t, state["game"].(*models.Game)))
+
+	p.controls <- Control{Type: ControlSeek, Turn: 99}
+	assert.Equal(t, "error", p.next(t)["type"])
+	p.controls <- Control{Type: ControlSpeed, Speed: 3}
+	assert.Equal(t, "error", p.next(t)["type"])
+	p.controls <- Control{Type: "roll_dice"}
+	assert.Equal(t, "error", p.next(t)["type"])
+
+	// Nothing is played while the timer has not fired, so no other message was sent
+	p.controls <- Control{Type: ControlSpeed, Speed: 4}
+	p.controls <- Control{Type: ControlPause}
+	p.stop(t)
+	assert.Empty(t, p.messages)
+}
+
+func TestPlayRejectsUnsupportedSpeed(t *testing.T) {
+	timeline := recordGame(t, 1).timeline(t)
+	err := timeline.Play(context.Background(), Options{Speed: 3}, nil, func(map[string]interface{}) error { return nil })
+	assert.Error(t, err)
+}
@@ -0,0 +1,147 @@
+package rng
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	mathrand "math/rand"
+)
+
+// SeedSize is the number of random bytes in a game seed
+const SeedSize = 32
+
+// Randomizer is the source of every random decision in a game: dice, card shuffles
+// and market condition rolls.
+type Randomizer interface {
+	// Intn returns a uniformly distributed number in [0, n). It panics if n <= 0.
+	Intn(n int) int
+}
+
+// Factory creates the randomizer for a game from its seed. Draws is the number of
+// values already taken from the game's randomizer, so a game reloaded from the
+// database continues the same sequence instead of starting over.
+type Factory func(seed []byte, draws int) Randomizer
+
+// NewSeed generates a new cryptographically random game seed
+func NewSeed() ([]byte, error) {
+	seed := make([]byte, SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, fmt.Errorf("failed to generate seed: %w", err)
+	}
+	return seed, nil
+}
+
+// Commitment returns the hex-encoded SHA-256 of a seed. It can be published while the
+// game is running and checked against the revealed seed once the game is over.
+func Commitment(seed []byte) string {
+	sum := sha256.Sum256(seed)
+	return hex.EncodeToString(sum[:])
+}
+
+// HMAC is a deterministic randomizer keyed by a secret seed. Each draw is derived from
+// HMAC-SHA256(seed, draw index), so with an unpredictable seed the output cannot be
+// guessed by players, yet the whole game can be replayed exactly once the seed is known.
+type HMAC struct {
+	seed  []byte
+	draws uint64
+}
+
+// NewHMAC creates an HMAC randomizer that resumes after the given number of draws
+func NewHMAC(seed []byte, draws int) *HMAC {
+	return &HMAC{seed: append([]byte(nil), seed...), draws: uint64(draws)}
+}
+
+// HMACFactory is the production Factory
+func HMACFactory(seed []byte, draws int) Randomizer {
+	return NewHMAC(seed, draws)
+}
+
+// Draws returns how many values have been drawn so far
+func (h *HMAC) Draws() int {
+	return int(h.draws)
+}
+
+// Intn implements Randomizer using rejection sampling so every result is equally likely
+func (h *HMAC) Intn(n int) int {
+	if n <= 0 {
+		panic("rng: invalid argument to Intn")
+	}
+
+	bound := uint64(n)
+	// Largest multiple of n that fits in a uint64; values at or above it are rejected
+	limit := ^uint64(0) - (^uint64(0) % bound)
+
+	var msg [16]byte
+	binary.BigEndian.PutUint64(msg[:8], h.draws)
+	h.draws++
+
+	for attempt := uint64(0); ; attempt++ {
+		binary.BigEndian.PutUint64(msg[8:], attempt)
+		mac := hmac.New(sha256.New, h.seed)
+		mac.Write(msg[:])
+		v := binary.BigEndian.Uint64(mac.Sum(nil))
+		if v < limit {
+			return int(v % bound)
+		}
+	}
+}
+
+// Seeded is a math/rand based randomizer for tests and simulations
+type Seeded struct {
+	r *mathrand.Rand
+}
+
+// NewSeeded creates a randomizer that always produces the same sequence for a seed
+func NewSeeded(seed int64) *Seeded {
+	return &Seeded{r: mathrand.New(mathrand.NewSource(seed))}
+}
+
+// SeededFactory returns a Factory that gives every game a PRNG with the same seed,
+// ignoring the game's own seed and draw count. It is meant for tests, where games
+// are not reloaded part way through.
+func SeededFactory(seed int64) Factory {
+	return func(_ []byte, _ int) Randomizer {
+		return NewSeeded(seed)
+	}
+}
+
+// Intn implements Randomizer
+func (s *Seeded) Intn(n int) int {
+	return s.r.Intn(n)
+}
+
+// Scripted returns a fixed sequence of values, for fixtures that need exact dice or
+// draws. Each value is reduced modulo n, and the sequence repeats once exhausted.
+type Scripted struct {
+	values []int
+	next   int
+}
+
+// NewScripted creates a randomizer that returns the given values in order
+func NewScripted(values ...int) *Scripted {
+	if len(values) == 0 {
+		values = []int{0}
+	}
+	return &Scripted{values: values}
+}
+
+// Intn implements Randomizer
+func (s *Scripted) Intn(n int) int {
+	if n <= 0 {
+		panic("rng: invalid argument to Intn")
+	}
+	v := s.values[s.next%len(s.values)]
+	s.next++
+	return ((v % n) + n) % n
+}
+
+// Shuffle randomly permutes n elements with a Fisher-Yates shuffle driven by r
+func Shuffle(r Randomizer, n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		j := r.Intn(i + 1)
+		swap(i, j)
+	}
+}
@@ -0,0 +1,77 @@
+package rng
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func draw(r Randomizer, count, n int) []int {
+	values := make([]int, count)
+	for i := range values {
+		values[i] = r.Intn(n)
+	}
+	return values
+}
+
+func TestHMACIsReproducible(t *testing.T) {
+	seed := []byte("kekopoly-test-seed")
+
+	first := draw(NewHMAC(seed, 0), 50, 6)
+	second := draw(NewHMAC(seed, 0), 50, 6)
+	assert.Equal(t, first, second)
+
+	other := draw(NewHMAC([]byte("another-seed"), 0), 50, 6)
+	assert.NotEqual(t, first, other)
+}
+
+func TestHMACResumesAfterDraws(t *testing.T) {
+	seed := []byte("kekopoly-test-seed")
+
+	full := draw(NewHMAC(seed, 0), 20, 6)
+	resumed := NewHMAC(seed, 8)
+	assert.Equal(t, full[8:], draw(resumed, 12, 6))
+	assert.Equal(t, 20, resumed.Draws())
+}
+
+func TestHMACStaysInRange(t *testing.T) {
+	r := NewHMAC([]byte("range"), 0)
+	seen := make(map[int]bool)
+	for _, v := range draw(r, 600, 6) {
+		require.GreaterOrEqual(t, v, 0)
+		require.Less(t, v, 6)
+		seen[v] = true
+	}
+	assert.Len(t, seen, 6)
+}
+
+func TestCommitment(t *testing.T) {
+	seed, err := NewSeed()
+	require.NoError(t, err)
+	require.Len(t, seed, SeedSize)
+
+	sum := sha256.Sum256(seed)
+	assert.Equal(t, hex.EncodeToString(sum[:]), Commitment(seed))
+}
+
+func TestSeededIsReproducible(t *testing.T) {
+	factory := SeededFactory(42)
+	assert.Equal(t, draw(factory(nil, 0), 20, 6), draw(factory(nil, 0), 20, 6))
+}
+
+func TestScriptedRepeatsValues(t *testing.T) {
+	r := NewScripted(0, 5, 7)
+	assert.Equal(t, []int{0, 5, 1, 0, 5}, draw(r, 5, 6))
+}
+
+func TestShuffleIsPermutation(t *testing.T) {
+	items := []string{"a", "b", "c", "d"}
+	Shuffle(NewScripted(0, 0, 0), len(items), func(i, j int) {
+		items[i], items[j] = items[j], items[i]
+	})
+	assert.ElementsMatch(t, []string{"a", "b", "c", "d"}, items)
+	assert.Equal(t, []string{"b", "c", "d", "a"}, items)
+}
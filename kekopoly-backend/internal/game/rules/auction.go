@@ -0,0 +1,150 @@
+package rules
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+const (
+	// AuctionDuration is how long an auction takes without late bids
+	AuctionDuration = 20 * time.Second
+	// AuctionExtension is the least time left after a bid; later bids reset the countdown to it
+	AuctionExtension = 5 * time.Second
+	// AuctionMinIncrement is the smallest opening bid and the least a bid must raise the last one
+	AuctionMinIncrement = 10
+)
+
+// declineProperty passes on the property the current player landed on and puts it up
+// for auction
+func (e *Engine) declineProperty(game *models.Game, player *models.Player, result *Result) error {
+	if err := requireTurn(game, player); err != nil {
+		return err
+	}
+	if game.TurnState.Auction != nil {
+		return ErrAuctionRunning
+	}
+	property := findProperty(game, game.TurnState.PendingPurchase)
+	if property == nil {
+		return ErrPropertyNotForSale
+	}
+
+	game.TurnState.PendingPurchase = ""
+	startAuction(game, property, player.ID, nil, result)
+	return nil
+}
+
+// startAuction puts a property up for auction, with more properties queued to follow
+func startAuction(game *models.Game, property *models.Property, declinedBy string, queue []string, result *Result) {
+	game.TurnState.Auction = &models.Auction{
+		PropertyID:   property.ID,
+		DeclinedBy:   declinedBy,
+		MinIncrement: AuctionMinIncrement,
+		Bids:         []models.Bid{},
+		EndsAt:       result.timestamp.Add(AuctionDuration),
+		Queue:        queue,
+	}
+
+	result.emit("auction_started", map[string]interface{}{
+		"propertyId":   property.ID,
+		"declinedBy":   declinedBy,
+		"price":        MarketPrice(game, property),
+		"minIncrement": AuctionMinIncrement,
+		"endsAt":       game.TurnState.Auction.EndsAt,
+		"queued":       len(queue),
+	})
+}
+
+// placeBid bids on the running auction. Any player still in the game may bid as long
+// as they can pay, whether or not it is their turn.
+func (e *Engine) placeBid(game *models.Game, player *models.Player, payload interface{}, result *Result) error {
+	auction := game.TurnState.Auction
+	if auction == nil {
+		return ErrNoAuction
+	}
+	now := result.timestamp
+	if !now.Before(auction.EndsAt) {
+		return ErrAuctionClosed
+	}
+
+	amount, ok := payloadInt(payload, "amount")
+	if !ok {
+		return fmt.Errorf("%w: amount is required", ErrInvalidPayload)
+	}
+	if amount < minimumBid(auction) {
+		return ErrBidTooLow
+	}
+	if player.Balance < amount {
+		return ErrInsufficientFunds
+	}
+
+	auction.Bids = append(auction.Bids, models.Bid{
+		PlayerID: player.ID,
+		Amount:   amount,
+		PlacedAt: now,
+	})
+	if auction.EndsAt.Sub(now) < AuctionExtension {
+		auction.EndsAt = now.Add(AuctionExtension)
+	}
+
+	result.emit("bid_placed", map[string]interface{}{
+		"propertyId": auction.PropertyID,
+		"playerId":   player.ID,
+		"amount":     amount,
+		"minimumBid": minimumBid(auction),
+		"endsAt":     auction.EndsAt,
+	})
+	return nil
+}
+
+// minimumBid is the least the next bid in an auction may be
+func minimumBid(auction *models.Auction) int {
+	if len(auction.Bids) == 0 {
+		return auction.MinIncrement
+	}
+	return auction.Bids[len(auction.Bids)-1].Amount + auction.MinIncrement
+}
+
+// closeAuction sells the auctioned property to the highest bidder. A bidder who has
+// left the game or can no longer pay is passed over for the next highest bid, and
+// with no valid bids the property stays with the bank. The next queued property, if
+// any, goes up for auction straight away.
+func (e *Engine) closeAuction(game *models.Game, result *Result) error {
+	auction := game.TurnState.Auction
+	if auction == nil {
+		return ErrNoAuction
+	}
+	if result.timestamp.Before(auction.EndsAt) {
+		return ErrAuctionRunning
+	}
+	property := findProperty(game, auction.PropertyID)
+	if property == nil {
+		return ErrPropertyNotFound
+	}
+	game.TurnState.Auction = nil
+
+	data := map[string]interface{}{
+		"propertyId": property.ID,
+		"bids":       len(auction.Bids),
+	}
+	for i := len(auction.Bids) - 1; i >= 0 && property.OwnerID == ""; i-- {
+		bid := auction.Bids[i]
+		bidder := findPlayer(game, bid.PlayerID)
+		if bidder == nil || !isInGame(bidder) || bidder.Balance < bid.Amount {
+			continue
+		}
+		purchase(game, bidder, property, bid.Amount, result)
+		data["winnerId"] = bidder.ID
+		data["amount"] = bid.Amount
+	}
+	result.emit("auction_ended", data)
+
+	for i, propertyID := range auction.Queue {
+		if next := findProperty(game, propertyID); next != nil && next.OwnerID == "" {
+			startAuction(game, next, auction.DeclinedBy, auction.Queue[i+1:], result)
+			break
+		}
+	}
+	return nil
+}
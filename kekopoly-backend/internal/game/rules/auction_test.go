@@ -0,0 +1,154 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+var auctionStart = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// newAuctionGame returns a game where p1 has rolled onto the unowned prop-4
+func newAuctionGame() *models.Game {
+	game := newTestGame()
+	game.TurnOrder = []string{"p1", "p2", "p3"}
+	game.Players = append(game.Players, models.Player{ID: "p3", Position: testBoard.StartPosition(), Balance: 1500, Status: models.PlayerStatusActive})
+	game.TurnState.HasRolled = true
+	game.TurnState.PendingPurchase = "prop-4"
+	return game
+}
+
+func timedAction(actionType models.ActionType, playerID string, payload interface{}, after time.Duration) models.GameAction {
+	a := action(actionType, playerID, payload)
+	a.Timestamp = auctionStart.Add(after)
+	return a
+}
+
+func bid(amount int) map[string]interface{} {
+	return map[string]interface{}{"amount": amount}
+}
+
+func TestDecliningStartsAnAuction(t *testing.T) {
+	game := newAuctionGame()
+	engine := newTestEngine()
+
+	_, err := engine.Apply(game, timedAction(models.ActionTypeDeclineProperty, "p2", nil, 0))
+	assert.ErrorIs(t, err, ErrNotYourTurn)
+
+	result, err := engine.Apply(game, timedAction(models.ActionTypeDeclineProperty, "p1", nil, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"auction_started"}, eventTypes(result))
+	require.NotNil(t, game.TurnState.Auction)
+	assert.Equal(t, "prop-4", game.TurnState.Auction.PropertyID)
+	assert.Equal(t, auctionStart.Add(AuctionDuration), game.TurnState.Auction.EndsAt)
+	assert.Empty(t, game.TurnState.PendingPurchase)
+
+	_, err = engine.Apply(game, timedAction(models.ActionTypeBuyProperty, "p1", nil, time.Second))
+	assert.ErrorIs(t, err, ErrPropertyNotForSale)
+	_, err = engine.Apply(game, timedAction(models.ActionTypeEndTurn, "p1", nil, time.Second))
+	assert.ErrorIs(t, err, ErrAuctionRunning)
+}
+
+func TestBidsMustRaiseByTheMinimumIncrement(t *testing.T) {
+	game := newAuctionGame()
+	game.Players[2].Balance = 30
+	engine := newTestEngine()
+	_, err := engine.Apply(game, timedAction(models.ActionTypeDeclineProperty, "p1", nil, 0))
+	require.NoError(t, err)
+
+	_, err = engine.Apply(game, timedAction(models.ActionTypePlaceBid, "p2", bid(AuctionMinIncrement-1), time.Second))
+	assert.ErrorIs(t, err, ErrBidTooLow)
+	_, err = engine.Apply(game, timedAction(models.ActionTypePlaceBid, "p2", bid(20), time.Second))
+	require.NoError(t, err)
+	_, err = engine.Apply(game, timedAction(models.ActionTypePlaceBid, "p1", bid(25), 2*time.Second))
+	assert.ErrorIs(t, err, ErrBidTooLow)
+	_, err = engine.Apply(game, timedAction(models.ActionTypePlaceBid, "p3", bid(40), 2*time.Second))
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// The player who declined may still bid
+	_, err = engine.Apply(game, timedAction(models.ActionTypePlaceBid, "p1", bid(30), 3*time.Second))
+	require.NoError(t, err)
+	assert.Len(t, game.TurnState.Auction.Bids, 2)
+}
+
+func TestLateBidsResetTheCountdown(t *testing.T) {
+	game := newAuctionGame()
+	engine := newTestEngine()
+	_, err := engine.Apply(game, timedAction(models.ActionTypeDeclineProperty, "p1", nil, 0))
+	require.NoError(t, err)
+
+	late := AuctionDuration - time.Second
+	_, err = engine.Apply(game, timedAction(models.ActionTypePlaceBid, "p2", bid(50), late))
+	require.NoError(t, err)
+	assert.Equal(t, auctionStart.Add(late+AuctionExtension), game.TurnState.Auction.EndsAt)
+
+	result, err := engine.Tick(game, auctionStart.Add(AuctionDuration))
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	_, err = engine.Apply(game, timedAction(models.ActionTypePlaceBid, "p3", bid(60), late+AuctionExtension))
+	assert.ErrorIs(t, err, ErrAuctionClosed)
+}
+
+func TestHighestBidderWinsTheAuction(t *testing.T) {
+	game := newAuctionGame()
+	engine := newTestEngine()
+	_, err := engine.Apply(game, timedAction(models.ActionTypeDeclineProperty, "p1", nil, 0))
+	require.NoError(t, err)
+	_, err = engine.Apply(game, timedAction(models.ActionTypePlaceBid, "p2", bid(40), time.Second))
+	require.NoError(t, err)
+	_, err = engine.Apply(game, timedAction(models.ActionTypePlaceBid, "p3", bid(70), 2*time.Second))
+	require.NoError(t, err)
+
+	result, err := engine.Tick(game, auctionStart.Add(AuctionDuration))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"property_purchased", "auction_ended"}, eventTypes(result))
+	assert.Equal(t, "p3", result.Events[1].Data["winnerId"])
+	assert.Equal(t, "p3", game.BoardState.Properties[0].OwnerID)
+	assert.Equal(t, 1430, game.Players[2].Balance)
+	assert.Nil(t, game.TurnState.Auction)
+	require.Len(t, result.Transactions, 1)
+	assert.Equal(t, models.TransactionTypePurchase, result.Transactions[0].Type)
+	assert.Equal(t, 70, result.Transactions[0].Amount)
+
+	_, err = engine.Apply(game, timedAction(models.ActionTypeEndTurn, "p1", nil, AuctionDuration))
+	require.NoError(t, err)
+}
+
+func TestAuctionPassesOverBiddersWhoCannotPay(t *testing.T) {
+	game := newAuctionGame()
+	engine := newTestEngine()
+	_, err := engine.Apply(game, timedAction(models.ActionTypeDeclineProperty, "p1", nil, 0))
+	require.NoError(t, err)
+	_, err = engine.Apply(game, timedAction(models.ActionTypePlaceBid, "p2", bid(40), time.Second))
+	require.NoError(t, err)
+	_, err = engine.Apply(game, timedAction(models.ActionTypePlaceBid, "p3", bid(70), 2*time.Second))
+	require.NoError(t, err)
+
+	// p3 goes bankrupt before the auction closes
+	game.Players[2].Status = models.PlayerStatusBankrupt
+
+	// Any player may close an auction once its countdown has run out
+	result, err := engine.Apply(game, timedAction(models.ActionTypeCloseAuction, "p2", nil, AuctionDuration))
+	require.NoError(t, err)
+	assert.Equal(t, "p2", game.BoardState.Properties[0].OwnerID)
+	assert.Equal(t, 1460, game.Players[1].Balance)
+	assert.Contains(t, eventTypes(result), "auction_ended")
+}
+
+func TestAuctionWithoutBidsLeavesPropertyWithTheBank(t *testing.T) {
+	game := newAuctionGame()
+	engine := newTestEngine()
+	_, err := engine.Apply(game, timedAction(models.ActionTypeDeclineProperty, "p1", nil, 0))
+	require.NoError(t, err)
+
+	result, err := engine.Tick(game, auctionStart.Add(AuctionDuration))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"auction_ended"}, eventTypes(result))
+	assert.Empty(t, game.BoardState.Properties[0].OwnerID)
+	assert.Empty(t, result.Transactions)
+}
@@ -0,0 +1,192 @@
+package rules
+
+import (
+	"time"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// LiquidationWindow is how long a player who cannot pay a debt has to raise the money
+// by mortgaging, selling buildings or trading before they go bankrupt
+const LiquidationWindow = 2 * time.Minute
+
+// checkDebts starts the liquidation window when the current player owes more than
+// they have. It runs after every accepted action.
+func checkDebts(game *models.Game, result *Result) {
+	debt := game.TurnState.PendingRent
+	if debt == nil || debt.DueBy != nil {
+		return
+	}
+	debtor := findPlayer(game, game.CurrentTurn)
+	if debtor == nil || debtor.Balance >= debt.Amount {
+		return
+	}
+
+	dueBy := result.timestamp.Add(LiquidationWindow)
+	debt.DueBy = &dueBy
+	result.emit("debt_unaffordable", map[string]interface{}{
+		"playerId":         debtor.ID,
+		"creditorId":       debt.CreditorID,
+		"amount":           debt.Amount,
+		"balance":          debtor.Balance,
+		"liquidationValue": liquidationValue(game, debtor),
+		"dueBy":            dueBy,
+	})
+}
+
+// settleOverdueDebt runs when the liquidation window closes: a player who raised the
+// money pays, and anyone else goes bankrupt
+func (e *Engine) settleOverdueDebt(game *models.Game, result *Result) {
+	debt := game.TurnState.PendingRent
+	debtor := findPlayer(game, game.CurrentTurn)
+	if debt == nil || debtor == nil {
+		return
+	}
+	if debtor.Balance >= debt.Amount {
+		// payRent cannot fail here: it is the debtor's turn and they can pay
+		_ = e.payRent(game, debtor, result)
+		return
+	}
+	e.bankrupt(game, debtor, result)
+}
+
+// declareBankruptcy lets a player who cannot pay their debt give up without waiting
+// for the liquidation window to close
+func (e *Engine) declareBankruptcy(game *models.Game, player *models.Player, result *Result) error {
+	if err := requireTurn(game, player); err != nil {
+		return err
+	}
+	debt := game.TurnState.PendingRent
+	if debt == nil {
+		return ErrNoRentDue
+	}
+	if player.Balance >= debt.Amount {
+		return ErrCanPayDebt
+	}
+
+	e.bankrupt(game, player, result)
+	return nil
+}
+
+// bankrupt takes a player out of the game over the debt they owe. A creditor who is a
+// player gets all their kekels and properties, mortgages and buildings included. Debts
+// to the bank send the buildings back to the bank and the properties to auction.
+func (e *Engine) bankrupt(game *models.Game, player *models.Player, result *Result) {
+	debt := game.TurnState.PendingRent
+	game.TurnState.PendingRent = nil
+
+	var creditor *models.Player
+	amount := 0
+	if debt != nil {
+		amount = debt.Amount
+		if other := findPlayer(game, debt.CreditorID); other != nil && other.ID != player.ID && isInGame(other) {
+			creditor = other
+		}
+	}
+
+	kekels := player.Balance
+	player.Balance = 0
+	creditorID := ""
+	if creditor != nil {
+		creditorID = creditor.ID
+		creditor.Balance += kekels
+	}
+	if kekels > 0 {
+		result.record(models.TransactionTypeBankruptcy, player.ID, creditorID, kekels, "")
+	}
+
+	var propertyIDs []string
+	for i := range game.BoardState.Properties {
+		property := &game.BoardState.Properties[i]
+		if property.OwnerID != player.ID {
+			continue
+		}
+		propertyIDs = append(propertyIDs, property.ID)
+		if creditor != nil {
+			property.OwnerID = creditor.ID
+			addOwnedProperty(creditor, property.ID)
+			continue
+		}
+		returnToBank(game, property)
+	}
+
+	player.Properties = []string{}
+	player.Status = models.PlayerStatusBankrupt
+	player.InJail = false
+	player.NetWorth = 0
+
+	remaining := make([]*models.Player, 0, len(game.Players))
+	for i := range game.Players {
+		if other := &game.Players[i]; isInGame(other) {
+			remaining = append(remaining, other)
+		}
+	}
+	player.Placement = len(remaining) + 1
+
+	result.emit("player_bankrupt", map[string]interface{}{
+		"playerId":    player.ID,
+		"creditorId":  creditorID,
+		"debt":        amount,
+		"kekels":      kekels,
+		"propertyIds": propertyIDs,
+	})
+
+	if len(remaining) == 1 {
+		completeGame(game, models.GameEndReasonLastPlayerStanding, result)
+		return
+	}
+
+	if game.CurrentTurn == player.ID {
+		e.passTurn(game, player, result)
+		if game.Status != models.GameStatusActive {
+			// The turn wrapped into a round past the game's round limit
+			return
+		}
+	}
+	removeFromTurnOrder(game, player.ID)
+
+	if creditor == nil && len(propertyIDs) > 0 && game.TurnState.Auction == nil {
+		startAuction(game, findProperty(game, propertyIDs[0]), player.ID, propertyIDs[1:], result)
+	}
+}
+
+// returnToBank gives a property back to the bank free of buildings, mortgage and effects
+func returnToBank(game *models.Game, property *models.Property) {
+	supply := buildingSupply(game)
+	supply.Engagements += property.Engagements
+	if property.BlueCheckmark {
+		supply.Checkmarks++
+	}
+	property.Engagements = 0
+	property.BlueCheckmark = false
+	property.Mortgaged = false
+	property.SpecialEffects = nil
+	property.OwnerID = ""
+}
+
+// removeFromTurnOrder drops a player from the turn order
+func removeFromTurnOrder(game *models.Game, playerID string) {
+	for i, id := range game.TurnOrder {
+		if id == playerID {
+			game.TurnOrder = append(game.TurnOrder[:i], game.TurnOrder[i+1:]...)
+			return
+		}
+	}
+}
+
+// liquidationValue is what a player could raise by selling every building and
+// mortgaging every property on top of their kekels
+func liquidationValue(game *models.Game, player *models.Player) int {
+	value := player.Balance
+	for i := range game.BoardState.Properties {
+		property := &game.BoardState.Properties[i]
+		if property.OwnerID != player.ID || property.Mortgaged {
+			continue
+		}
+		value += mortgageValue(property) + property.Engagements*engagementCost(property)/2
+		if property.BlueCheckmark {
+			value += checkmarkCost(property) / 2
+		}
+	}
+	return value
+}
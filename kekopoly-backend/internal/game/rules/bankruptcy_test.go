@@ -0,0 +1,143 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// newDebtGame returns a three player game where p1 owns prop-4 and p2 owns prop-7
+func newDebtGame() *models.Game {
+	game := newAuctionGame()
+	game.TurnState = models.TurnState{}
+	game.BoardState.Properties[0].OwnerID = "p1"
+	game.BoardState.Properties[1].OwnerID = "p2"
+	game.Players[0].Properties = []string{"prop-4"}
+	game.Players[1].Properties = []string{"prop-7"}
+	return game
+}
+
+func TestUnaffordableRentStartsTheLiquidationWindow(t *testing.T) {
+	game := newDebtGame()
+	game.Players[0].Balance = 5
+	engine := newTestEngine(2, 4)
+
+	result, err := engine.Apply(game, timedAction(models.ActionTypeRollDice, "p1", nil, 0))
+	require.NoError(t, err)
+	assert.Contains(t, eventTypes(result), "debt_unaffordable")
+	debt := game.TurnState.PendingRent
+	require.NotNil(t, debt)
+	require.NotNil(t, debt.DueBy)
+	assert.Equal(t, auctionStart.Add(LiquidationWindow), *debt.DueBy)
+
+	deadline, ok := NextDeadline(game)
+	require.True(t, ok)
+	assert.Equal(t, *debt.DueBy, deadline)
+
+	// Raising the money in time pays the debt when the window closes
+	_, err = engine.Apply(game, timedAction(models.ActionTypeMortgageProperty, "p1", propertyPayload("prop-4"), time.Second))
+	require.NoError(t, err)
+	result, err = engine.Tick(game, auctionStart.Add(LiquidationWindow))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rent_paid"}, eventTypes(result))
+	assert.Nil(t, game.TurnState.PendingRent)
+	assert.Equal(t, models.PlayerStatusActive, game.Players[0].Status)
+}
+
+func TestBankruptcyToAPlayerHandsOverEverything(t *testing.T) {
+	game := newDebtGame()
+	game.Players[0].Balance = 30
+	game.BoardState.Properties[0].Mortgaged = true
+	game.TurnState = models.TurnState{HasRolled: true, PendingRent: &models.Debt{CreditorID: "p2", Amount: 40, PropertyID: "prop-7"}}
+	engine := newTestEngine()
+	engine.SetMarketSettings(MarketSettings{})
+
+	_, err := engine.Apply(game, timedAction(models.ActionTypeDeclareBankruptcy, "p2", nil, 0))
+	assert.ErrorIs(t, err, ErrNotYourTurn)
+	game.Players[0].Balance = 40
+	_, err = engine.Apply(game, timedAction(models.ActionTypeDeclareBankruptcy, "p1", nil, 0))
+	assert.ErrorIs(t, err, ErrCanPayDebt)
+	game.Players[0].Balance = 30
+
+	result, err := engine.Apply(game, timedAction(models.ActionTypeDeclareBankruptcy, "p1", nil, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "player_bankrupt", result.Events[0].Type)
+	assert.Equal(t, models.PlayerStatusBankrupt, game.Players[0].Status)
+	assert.Equal(t, 0, game.Players[0].Balance)
+	assert.Empty(t, game.Players[0].Properties)
+
+	assert.Equal(t, 1530, game.Players[1].Balance)
+	assert.Equal(t, "p2", game.BoardState.Properties[0].OwnerID)
+	assert.True(t, game.BoardState.Properties[0].Mortgaged)
+	assert.ElementsMatch(t, []string{"prop-4", "prop-7"}, game.Players[1].Properties)
+	require.Len(t, result.Transactions, 1)
+	assert.Equal(t, models.TransactionTypeBankruptcy, result.Transactions[0].Type)
+
+	assert.Equal(t, []string{"p2", "p3"}, game.TurnOrder)
+	assert.Equal(t, "p2", game.CurrentTurn)
+	assert.Nil(t, game.TurnState.Auction)
+}
+
+func TestBankruptcyToTheBankAuctionsTheProperties(t *testing.T) {
+	game := newDebtGame()
+	game.Players[0].Balance = 10
+	game.BoardState.Properties[1].OwnerID = "p1"
+	game.BoardState.Properties[1].Engagements = 2
+	game.Players[0].Properties = []string{"prop-4", "prop-7"}
+	game.Players[1].Properties = nil
+	game.TurnState = models.TurnState{HasRolled: true, PendingRent: &models.Debt{Amount: JailFine}}
+	engine := newTestEngine()
+	engine.SetMarketSettings(MarketSettings{})
+	supply := buildingSupply(game).Engagements
+
+	result, err := engine.Apply(game, timedAction(models.ActionTypeDeclareBankruptcy, "p1", nil, 0))
+	require.NoError(t, err)
+	assert.Contains(t, eventTypes(result), "auction_started")
+	assert.Equal(t, supply+2, buildingSupply(game).Engagements)
+	assert.Empty(t, game.BoardState.Properties[1].OwnerID)
+	assert.Zero(t, game.BoardState.Properties[1].Engagements)
+
+	auction := game.TurnState.Auction
+	require.NotNil(t, auction)
+	assert.Equal(t, "prop-4", auction.PropertyID)
+	assert.Equal(t, []string{"prop-7"}, auction.Queue)
+	assert.Equal(t, "p2", game.CurrentTurn)
+
+	// The next property goes up as soon as the first auction ends
+	result, err = engine.Tick(game, auctionStart.Add(AuctionDuration))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"auction_ended", "auction_started"}, eventTypes(result))
+	assert.Equal(t, "prop-7", game.TurnState.Auction.PropertyID)
+}
+
+func TestUnpaidDebtEndsTheGameWithTheLastPlayerStanding(t *testing.T) {
+	game := newTestGame()
+	game.Players[0].Balance = 5
+	game.BoardState.Properties[1].OwnerID = "p2"
+	game.Players[1].Properties = []string{"prop-7"}
+	engine := newTestEngine(2, 4)
+
+	_, err := engine.Apply(game, timedAction(models.ActionTypeRollDice, "p1", nil, 0))
+	require.NoError(t, err)
+
+	result, err := engine.Tick(game, auctionStart.Add(LiquidationWindow-time.Second))
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	result, err = engine.Tick(game, auctionStart.Add(LiquidationWindow))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"player_bankrupt", "game_over"}, eventTypes(result))
+	assert.Equal(t, models.GameStatusCompleted, game.Status)
+	assert.Equal(t, "p2", game.WinnerID)
+	assert.Equal(t, models.GameEndReasonLastPlayerStanding, game.EndReason)
+	assert.Equal(t, 2, game.Players[0].Placement)
+	assert.Equal(t, 1, game.Players[1].Placement)
+	assert.Empty(t, game.CurrentTurn)
+
+	_, err = engine.Apply(game, timedAction(models.ActionTypeRollDice, "p2", nil, time.Hour))
+	assert.ErrorIs(t, err, ErrGameNotActive)
+}
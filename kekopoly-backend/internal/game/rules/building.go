@@ -0,0 +1,316 @@
+package rules
+
+import (
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+const (
+	// DefaultEngagementSupply is the number of engagements the bank holds at the start of a game
+	DefaultEngagementSupply = 32
+	// DefaultCheckmarkSupply is the number of blue checkmarks the bank holds at the start of a game
+	DefaultCheckmarkSupply = 12
+)
+
+// NewBuildingSupply returns the bank's building supply for a new game
+func NewBuildingSupply() *models.BuildingSupply {
+	return &models.BuildingSupply{
+		Engagements: DefaultEngagementSupply,
+		Checkmarks:  DefaultCheckmarkSupply,
+	}
+}
+
+// buildingSupply returns the game's building supply. Games created before the supply
+// was tracked get the default supply minus the buildings already on the board.
+func buildingSupply(game *models.Game) *models.BuildingSupply {
+	if game.BoardState.BuildingSupply == nil {
+		supply := NewBuildingSupply()
+		for _, property := range game.BoardState.Properties {
+			supply.Engagements -= property.Engagements
+			if property.BlueCheckmark {
+				supply.Checkmarks--
+			}
+		}
+		game.BoardState.BuildingSupply = supply
+	}
+	return game.BoardState.BuildingSupply
+}
+
+// buildingLevel ranks a property's development for even building: the number of
+// engagements, with a blue checkmark counting as one level above four engagements
+func buildingLevel(property *models.Property) int {
+	if property.BlueCheckmark {
+		return maxEngagements + 1
+	}
+	return property.Engagements
+}
+
+// groupProperties returns the buildable properties in a group
+func groupProperties(game *models.Game, group string) []*models.Property {
+	var properties []*models.Property
+	for i := range game.BoardState.Properties {
+		property := &game.BoardState.Properties[i]
+		if property.Group == group && property.Type == models.PropertyTypeRegular {
+			properties = append(properties, property)
+		}
+	}
+	return properties
+}
+
+// groupLevels returns the lowest and highest building level in a property's group
+func groupLevels(game *models.Game, group string) (lowest, highest int) {
+	lowest = maxEngagements + 1
+	for _, property := range groupProperties(game, group) {
+		level := buildingLevel(property)
+		if level < lowest {
+			lowest = level
+		}
+		if level > highest {
+			highest = level
+		}
+	}
+	return lowest, highest
+}
+
+// groupHasBuildings reports whether any property in a group has buildings
+func groupHasBuildings(game *models.Game, group string) bool {
+	for _, property := range groupProperties(game, group) {
+		if hasBuildings(property) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildableProperty checks that the player may develop the property named in the
+// payload: it is their own regular property and they own the whole, unmortgaged group
+func buildableProperty(game *models.Game, player *models.Player, payload interface{}) (*models.Property, error) {
+	property, err := ownedProperty(game, player, payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBuildable(game, player, property); err != nil {
+		return nil, err
+	}
+	return property, nil
+}
+
+// checkBuildable checks that a player's own property is a regular property in a
+// whole, unmortgaged group they own
+func checkBuildable(game *models.Game, player *models.Player, property *models.Property) error {
+	if property.Type != models.PropertyTypeRegular {
+		return ErrNotBuildable
+	}
+	if !ownsGroup(game, player.ID, property.Group) {
+		return ErrIncompleteGroup
+	}
+	for _, other := range groupProperties(game, property.Group) {
+		if other.Mortgaged {
+			return ErrGroupMortgaged
+		}
+	}
+	return nil
+}
+
+// CanBuildEngagement checks that the player may put an engagement on a property now
+// and returns what it costs
+func CanBuildEngagement(game *models.Game, player *models.Player, property *models.Property) (int, error) {
+	if err := requireTurn(game, player); err != nil {
+		return 0, err
+	}
+	if property.OwnerID != player.ID {
+		return 0, ErrNotPropertyOwner
+	}
+	if err := checkBuildable(game, player, property); err != nil {
+		return 0, err
+	}
+	if _, err := engagementAvailable(game, property); err != nil {
+		return 0, err
+	}
+	cost := engagementCost(property)
+	if player.Balance < cost {
+		return 0, ErrInsufficientFunds
+	}
+	return cost, nil
+}
+
+// buildEngagement adds one engagement to a property owned by the current player
+func (e *Engine) buildEngagement(game *models.Game, player *models.Player, payload interface{}, result *Result) error {
+	if err := requireTurn(game, player); err != nil {
+		return err
+	}
+	property, err := buildableProperty(game, player, payload)
+	if err != nil {
+		return err
+	}
+	supply, err := engagementAvailable(game, property)
+	if err != nil {
+		return err
+	}
+
+	cost := engagementCost(property)
+	if player.Balance < cost {
+		return ErrInsufficientFunds
+	}
+
+	player.Balance -= cost
+	addEngagement(property, supply, payload)
+
+	result.emit("engagement_built", map[string]interface{}{
+		"playerId":    player.ID,
+		"propertyId":  property.ID,
+		"engagements": property.Engagements,
+		"memeName":    property.MemeName,
+		"cost":        cost,
+		"balance":     player.Balance,
+		"supply":      *supply,
+	})
+	return nil
+}
+
+// engagementAvailable checks that one more engagement may go on a buildable property
+// under the even-building rule and that the bank has one to sell
+func engagementAvailable(game *models.Game, property *models.Property) (*models.BuildingSupply, error) {
+	if property.BlueCheckmark {
+		return nil, ErrHasCheckmark
+	}
+	if property.Engagements >= maxEngagements {
+		return nil, ErrMaxEngagements
+	}
+	if lowest, _ := groupLevels(game, property.Group); buildingLevel(property) > lowest {
+		return nil, ErrUnevenBuilding
+	}
+
+	supply := buildingSupply(game)
+	if supply.Engagements <= 0 {
+		return nil, ErrEngagementShortage
+	}
+	return supply, nil
+}
+
+// addEngagement takes an engagement from the bank and places it on a property
+func addEngagement(property *models.Property, supply *models.BuildingSupply, payload interface{}) {
+	property.Engagements++
+	supply.Engagements--
+
+	// The first engagement names the meme that players must call out when landing here
+	if property.MemeName == "" {
+		property.MemeName = payloadString(payload, "memeName")
+		if property.MemeName == "" {
+			property.MemeName = "Meme for " + property.Name
+		}
+	}
+}
+
+// buildCheckmark upgrades a property with four engagements to a blue checkmark
+func (e *Engine) buildCheckmark(game *models.Game, player *models.Player, payload interface{}, result *Result) error {
+	if err := requireTurn(game, player); err != nil {
+		return err
+	}
+	property, err := buildableProperty(game, player, payload)
+	if err != nil {
+		return err
+	}
+	if property.BlueCheckmark {
+		return ErrHasCheckmark
+	}
+	if property.Engagements < maxEngagements {
+		return ErrNeedFourEngagements
+	}
+	if lowest, _ := groupLevels(game, property.Group); lowest < maxEngagements {
+		return ErrUnevenBuilding
+	}
+
+	supply := buildingSupply(game)
+	if supply.Checkmarks <= 0 {
+		return ErrCheckmarkShortage
+	}
+
+	cost := checkmarkCost(property)
+	if player.Balance < cost {
+		return ErrInsufficientFunds
+	}
+
+	player.Balance -= cost
+	property.BlueCheckmark = true
+	property.Engagements = 0 // The checkmark replaces the engagements, which go back to the bank
+	supply.Checkmarks--
+	supply.Engagements += maxEngagements
+
+	result.emit("checkmark_built", map[string]interface{}{
+		"playerId":   player.ID,
+		"propertyId": property.ID,
+		"cost":       cost,
+		"balance":    player.Balance,
+		"supply":     *supply,
+	})
+	return nil
+}
+
+// sellEngagement sells one engagement back to the bank for half its cost
+func (e *Engine) sellEngagement(game *models.Game, player *models.Player, payload interface{}, result *Result) error {
+	property, err := ownedProperty(game, player, payload)
+	if err != nil {
+		return err
+	}
+	if property.BlueCheckmark {
+		return ErrHasCheckmark
+	}
+	if property.Engagements <= 0 {
+		return ErrNoEngagements
+	}
+	if _, highest := groupLevels(game, property.Group); buildingLevel(property) < highest {
+		return ErrUnevenBuilding
+	}
+
+	supply := buildingSupply(game)
+	value := engagementCost(property) / 2
+
+	player.Balance += value
+	property.Engagements--
+	supply.Engagements++
+
+	result.emit("engagement_sold", map[string]interface{}{
+		"playerId":    player.ID,
+		"propertyId":  property.ID,
+		"engagements": property.Engagements,
+		"amount":      value,
+		"balance":     player.Balance,
+		"supply":      *supply,
+	})
+	return nil
+}
+
+// sellCheckmark sells a blue checkmark back to the bank for half its cost. The
+// property drops back to four engagements, which must be available in the bank.
+func (e *Engine) sellCheckmark(game *models.Game, player *models.Player, payload interface{}, result *Result) error {
+	property, err := ownedProperty(game, player, payload)
+	if err != nil {
+		return err
+	}
+	if !property.BlueCheckmark {
+		return ErrNoCheckmark
+	}
+
+	supply := buildingSupply(game)
+	if supply.Engagements < maxEngagements {
+		return ErrEngagementShortage
+	}
+
+	value := checkmarkCost(property) / 2
+
+	player.Balance += value
+	property.BlueCheckmark = false
+	property.Engagements = maxEngagements
+	supply.Checkmarks++
+	supply.Engagements -= maxEngagements
+
+	result.emit("checkmark_sold", map[string]interface{}{
+		"playerId":    player.ID,
+		"propertyId":  property.ID,
+		"engagements": property.Engagements,
+		"amount":      value,
+		"balance":     player.Balance,
+		"supply":      *supply,
+	})
+	return nil
+}
@@ -0,0 +1,170 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+func propertyPayload(id string) map[string]interface{} {
+	return map[string]interface{}{"propertyId": id}
+}
+
+// newBuildingGame returns a game where p1 owns the brown group and has rolled
+func newBuildingGame() *models.Game {
+	game := newRentGame("prop_colmer_corner", "prop_wojak_street")
+	game.Players[0].Properties = []string{"prop_colmer_corner", "prop_wojak_street"}
+	game.BoardState.BuildingSupply = NewBuildingSupply()
+	return game
+}
+
+func TestBuildEngagementRequiresFullGroup(t *testing.T) {
+	game := newBuildingGame()
+	findProperty(game, "prop_wojak_street").OwnerID = "p2"
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeBuildEngagement, "p1", propertyPayload("prop_colmer_corner")))
+	assert.ErrorIs(t, err, ErrIncompleteGroup)
+}
+
+func TestBuildEngagementRejectsMortgagedGroup(t *testing.T) {
+	game := newBuildingGame()
+	findProperty(game, "prop_wojak_street").Mortgaged = true
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeBuildEngagement, "p1", propertyPayload("prop_colmer_corner")))
+	assert.ErrorIs(t, err, ErrGroupMortgaged)
+}
+
+func TestBuildEngagementEvenly(t *testing.T) {
+	game := newBuildingGame()
+	engine := newTestEngine(1, 2)
+	colmer := findProperty(game, "prop_colmer_corner")
+
+	payload := propertyPayload("prop_colmer_corner")
+	payload["memeName"] = "Distracted Boyfriend"
+	result, err := engine.Apply(game, action(models.ActionTypeBuildEngagement, "p1", payload))
+	require.NoError(t, err)
+	assert.Equal(t, 1, colmer.Engagements)
+	assert.Equal(t, "Distracted Boyfriend", colmer.MemeName)
+	assert.Equal(t, 1450, game.Players[0].Balance)
+	assert.Equal(t, DefaultEngagementSupply-1, game.BoardState.BuildingSupply.Engagements)
+	assert.Equal(t, "engagement_built", result.Events[0].Type)
+	assert.Equal(t, 10, colmer.RentCurrent)
+
+	_, err = engine.Apply(game, action(models.ActionTypeBuildEngagement, "p1", propertyPayload("prop_colmer_corner")))
+	assert.ErrorIs(t, err, ErrUnevenBuilding)
+
+	_, err = engine.Apply(game, action(models.ActionTypeBuildEngagement, "p1", propertyPayload("prop_wojak_street")))
+	require.NoError(t, err)
+	assert.Equal(t, "Meme for WOJAK STREET", findProperty(game, "prop_wojak_street").MemeName)
+}
+
+func TestBuildEngagementShortage(t *testing.T) {
+	game := newBuildingGame()
+	game.BoardState.BuildingSupply.Engagements = 0
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeBuildEngagement, "p1", propertyPayload("prop_colmer_corner")))
+	assert.ErrorIs(t, err, ErrEngagementShortage)
+	assert.Equal(t, 0, findProperty(game, "prop_colmer_corner").Engagements)
+}
+
+func TestCanBuildEngagement(t *testing.T) {
+	game := newBuildingGame()
+	colmer := findProperty(game, "prop_colmer_corner")
+
+	cost, err := CanBuildEngagement(game, &game.Players[0], colmer)
+	require.NoError(t, err)
+	assert.Equal(t, 50, cost)
+
+	_, err = CanBuildEngagement(game, &game.Players[1], colmer)
+	assert.ErrorIs(t, err, ErrNotYourTurn)
+
+	colmer.Engagements = 1
+	_, err = CanBuildEngagement(game, &game.Players[0], colmer)
+	assert.ErrorIs(t, err, ErrUnevenBuilding)
+
+	game.Players[0].Balance = 49
+	_, err = CanBuildEngagement(game, &game.Players[0], findProperty(game, "prop_wojak_street"))
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestBuildCheckmarkReturnsEngagements(t *testing.T) {
+	game := newBuildingGame()
+	game.BoardState.BuildingSupply.Engagements = DefaultEngagementSupply - 8
+	colmer := findProperty(game, "prop_colmer_corner")
+	wojak := findProperty(game, "prop_wojak_street")
+	colmer.Engagements = 4
+	wojak.Engagements = 3
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeBuildCheckmark, "p1", propertyPayload("prop_colmer_corner")))
+	assert.ErrorIs(t, err, ErrUnevenBuilding)
+
+	wojak.Engagements = 4
+	_, err = engine.Apply(game, action(models.ActionTypeBuildCheckmark, "p1", propertyPayload("prop_colmer_corner")))
+	require.NoError(t, err)
+	assert.True(t, colmer.BlueCheckmark)
+	assert.Equal(t, 0, colmer.Engagements)
+	assert.Equal(t, 1500-colmer.BuildCost, game.Players[0].Balance)
+	assert.Equal(t, DefaultEngagementSupply-4, game.BoardState.BuildingSupply.Engagements)
+	assert.Equal(t, DefaultCheckmarkSupply-1, game.BoardState.BuildingSupply.Checkmarks)
+	assert.Empty(t, colmer.SpecialEffects)
+
+	game.BoardState.BuildingSupply.Checkmarks = 0
+	_, err = engine.Apply(game, action(models.ActionTypeBuildCheckmark, "p1", propertyPayload("prop_wojak_street")))
+	assert.ErrorIs(t, err, ErrCheckmarkShortage)
+}
+
+func TestSellBuildingsAtHalfPrice(t *testing.T) {
+	game := newBuildingGame()
+	colmer := findProperty(game, "prop_colmer_corner")
+	wojak := findProperty(game, "prop_wojak_street")
+	colmer.BlueCheckmark = true
+	wojak.Engagements = 4
+	game.BoardState.BuildingSupply.Engagements = DefaultEngagementSupply - 4
+	game.BoardState.BuildingSupply.Checkmarks = DefaultCheckmarkSupply - 1
+	engine := newTestEngine(1, 2)
+
+	// Selling happens highest first, so the checkmark must go before wojak's engagements
+	_, err := engine.Apply(game, action(models.ActionTypeSellEngagement, "p2", propertyPayload("prop_wojak_street")))
+	assert.ErrorIs(t, err, ErrNotPropertyOwner)
+	_, err = engine.Apply(game, action(models.ActionTypeSellEngagement, "p1", propertyPayload("prop_wojak_street")))
+	assert.ErrorIs(t, err, ErrUnevenBuilding)
+
+	_, err = engine.Apply(game, action(models.ActionTypeSellCheckmark, "p1", propertyPayload("prop_colmer_corner")))
+	require.NoError(t, err)
+	assert.False(t, colmer.BlueCheckmark)
+	assert.Equal(t, 4, colmer.Engagements)
+	assert.Equal(t, 1500+25, game.Players[0].Balance)
+	assert.Equal(t, DefaultEngagementSupply-8, game.BoardState.BuildingSupply.Engagements)
+
+	_, err = engine.Apply(game, action(models.ActionTypeSellEngagement, "p1", propertyPayload("prop_wojak_street")))
+	require.NoError(t, err)
+	assert.Equal(t, 3, wojak.Engagements)
+	assert.Equal(t, 1500+25+25, game.Players[0].Balance)
+}
+
+func TestMortgageRejectedWhileGroupHasBuildings(t *testing.T) {
+	game := newBuildingGame()
+	findProperty(game, "prop_colmer_corner").Engagements = 1
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeMortgageProperty, "p1", propertyPayload("prop_wojak_street")))
+	assert.ErrorIs(t, err, ErrGroupHasBuildings)
+}
+
+func TestBuildingSupplyInitialisedForLegacyGames(t *testing.T) {
+	game := newBuildingGame()
+	game.BoardState.BuildingSupply = nil
+	findProperty(game, "prop_colmer_corner").Engagements = 2
+	findProperty(game, "prop_wojak_street").BlueCheckmark = true
+
+	supply := buildingSupply(game)
+	assert.Equal(t, DefaultEngagementSupply-2, supply.Engagements)
+	assert.Equal(t, DefaultCheckmarkSupply-1, supply.Checkmarks)
+}
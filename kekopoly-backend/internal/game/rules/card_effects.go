@@ -0,0 +1,712 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/kekopoly/backend/internal/game/cards"
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// Card choices are read from the USE_CARD payload:
+//
+//	targetPlayerId   the player a card is aimed at
+//	secondPlayerId   the player the target swaps places with (FORCE_POSITION_SWAP)
+//	propertyId       the property a card acts on; for EXCHANGE_PROPERTY, the player's own property
+//	targetPropertyId the other player's property (EXCHANGE_PROPERTY)
+//	position         the board position to move to
+//	deck             the deck to look at (VIEW_CARDS)
+//	copyCardId       the played card to copy (COPY_LAST_CARD)
+//	targetCardId     the card to take from the target's hand (STEAL_CARD)
+
+// applyCardEffect carries out what a card does. Every choice is validated before the
+// game is changed, so a rejected choice leaves the card waiting. It returns false when
+// the card needs another round of choices, as when STEAL_CARD has revealed a hand.
+func (e *Engine) applyCardEffect(game *models.Game, player *models.Player, def cards.Definition, payload interface{}, result *Result) (bool, error) {
+	p := def.Params
+
+	switch def.Effect {
+	case cards.EffectCollectFromAll:
+		for _, other := range opponents(game, player) {
+			cardPayment(game, other, player, p.Amount, def.ID, result)
+		}
+	case cards.EffectStealAndShadowban:
+		for _, other := range opponents(game, player) {
+			cardPayment(game, other, player, p.Amount, def.ID, result)
+		}
+		shadowban(player, DefaultShadowbanTurns, ShadowbanSourceCard, result)
+	case cards.EffectCollectPerPropertyFromAll:
+		for _, other := range opponents(game, player) {
+			cardPayment(game, other, player, p.PerUnit*len(player.Properties), def.ID, result)
+		}
+	case cards.EffectCollectFromPlayer:
+		target, err := cardTarget(game, player, p.Target, payload)
+		if err != nil {
+			return false, err
+		}
+		if target != nil {
+			cardPayment(game, target, player, p.Amount, def.ID, result)
+		}
+	case cards.EffectPayNextPlayer:
+		if next := findPlayer(game, nextPlayerID(game, player.ID)); next != nil && next.ID != player.ID {
+			cardPayment(game, player, next, p.Amount, def.ID, result)
+		}
+	case cards.EffectPayPercentage:
+		if poorest := poorestOpponent(game, player); poorest != nil {
+			cardPayment(game, player, poorest, player.Balance*p.Percent/100, def.ID, result)
+		}
+	case cards.EffectAllForfeitPercentage:
+		for i := range game.Players {
+			if other := &game.Players[i]; isInGame(other) {
+				cardPayment(game, other, nil, other.Balance*p.Percent/100, def.ID, result)
+			}
+		}
+	case cards.EffectLoseHalfKekels:
+		cardPayment(game, player, nil, player.Balance/2, def.ID, result)
+	case cards.EffectCollectPerProperty:
+		cardPayment(game, nil, player, p.PerUnit*len(player.Properties), def.ID, result)
+	case cards.EffectPayPerProperty:
+		cardPayment(game, player, nil, p.PerUnit*len(player.Properties), def.ID, result)
+	case cards.EffectCollectForBuildings:
+		engagements, checkmarks := countBuildings(game, player.ID)
+		cardPayment(game, nil, player, p.Amount+p.PerUnit*(engagements+checkmarks), def.ID, result)
+	case cards.EffectPayPerEngagement:
+		engagements, _ := countBuildings(game, player.ID)
+		cardPayment(game, player, nil, p.PerUnit*engagements, def.ID, result)
+	case cards.EffectRollCollect:
+		e.rollCollect(game, player, def, result)
+	case cards.EffectLowestRollPays:
+		e.lowestRollPays(game, player, def, result)
+	case cards.EffectVoteForfeit:
+		startVote(game, player, def.ID, p.Amount, result)
+
+	case cards.EffectAdvanceToStart:
+		e.moveByCard(game, player, e.board.StartPosition(), false, def.ID, result)
+		cardPayment(game, nil, player, p.Amount, def.ID, result)
+	case cards.EffectGoBack:
+		size := e.board.Size()
+		e.moveByCard(game, player, ((player.Position-p.Spaces)%size+size)%size, false, def.ID, result)
+		e.resolveLanding(game, player, result)
+	case cards.EffectAdvanceToAnyProperty:
+		property, err := cardProperty(game, payload, "propertyId")
+		if err != nil {
+			return false, err
+		}
+		e.moveByCard(game, player, property.Position, true, def.ID, result)
+		e.resolveLanding(game, player, result)
+	case cards.EffectTeleport, cards.EffectMoveToAnySpace:
+		position, ok := payloadInt(payload, "position")
+		if !ok || e.board.SpaceAt(position) == nil {
+			return false, fmt.Errorf("%w: a board position is required", ErrInvalidPayload)
+		}
+		e.moveByCard(game, player, position, false, def.ID, result)
+		e.resolveLanding(game, player, result)
+	case cards.EffectMoveRandom:
+		e.moveByCard(game, player, e.intn(game, e.board.Size()), false, def.ID, result)
+		e.resolveLanding(game, player, result)
+	case cards.EffectSwapPosition:
+		target, err := cardTarget(game, player, cards.TargetChosen, payload)
+		if err != nil {
+			return false, err
+		}
+		swapPositions(player, target, def.ID, result)
+	case cards.EffectForcePositionSwap:
+		target, err := cardTarget(game, player, cards.TargetChosen, payload)
+		if err != nil {
+			return false, err
+		}
+		second := findPlayer(game, payloadString(payload, "secondPlayerId"))
+		if second == nil || second.ID == target.ID || !isInGame(second) {
+			return false, ErrInvalidTarget
+		}
+		swapPositions(target, second, def.ID, result)
+
+	case cards.EffectGoToShadowban:
+		shadowban(player, DefaultShadowbanTurns, ShadowbanSourceCard, result)
+	case cards.EffectGetOutOfShadowban:
+		if isShadowbanned(player) {
+			liftShadowban(player, result)
+		}
+	case cards.EffectPropertyImmunity:
+		addStatusEffect(player, StatusPropertyImmunity, player.ID, p.Rounds, result)
+	case cards.EffectDoubleRentAgainst:
+		addStatusEffect(player, StatusDoubleRentAgainst, player.ID, p.Rounds, result)
+	case cards.EffectDoubleRent:
+		addStatusEffect(player, StatusDoubleRent, player.ID, p.Rounds, result)
+		doublePendingRent(game, player, def.ID, result)
+	case cards.EffectCollectAllRent:
+		addStatusEffect(player, StatusCollectAllRent, player.ID, p.Rounds, result)
+	case cards.EffectMustBuyNext:
+		addStatusEffect(player, StatusMustBuyNext, player.ID, 0, result)
+	case cards.EffectFreezePlayers:
+		for _, other := range opponents(game, player) {
+			addStatusEffect(other, StatusFrozen, player.ID, p.Rounds, result)
+		}
+
+	case cards.EffectForceMortgage:
+		return true, e.forceMortgage(game, player, def, payload, result)
+	case cards.EffectBuyAnyProperty:
+		return true, e.takeOverProperty(game, player, def, payload, result)
+	case cards.EffectBuyAnyPropertyDiscount:
+		return true, e.buyAtDiscount(game, player, def, payload, result)
+	case cards.EffectExchangeProperty:
+		return true, e.exchangeProperty(game, player, def, payload, result)
+	case cards.EffectFreeEngagement:
+		return true, e.freeEngagement(game, player, def, payload, result)
+	case cards.EffectRemoveAllEngagements:
+		removeAllEngagements(game, player, result)
+
+	case cards.EffectViewCards:
+		return true, e.viewCards(game, player, def, payload, result)
+	case cards.EffectStealCard:
+		return e.stealCard(game, player, def, payload, result)
+	case cards.EffectCopyLastCard:
+		return e.copyLastCard(game, player, payload, result)
+
+	default:
+		return false, fmt.Errorf("%w: card effect %s", ErrActionNotSupported, def.Effect)
+	}
+	return true, nil
+}
+
+// hasCardOptions reports whether the player has anything to choose for a card. A card
+// with nothing to choose can be passed even when it is mandatory.
+func (e *Engine) hasCardOptions(game *models.Game, player *models.Player, def cards.Definition) bool {
+	if def.Effect == cards.EffectForceMortgage && def.Params.Target == cards.TargetSelf {
+		return len(mortgageable(game, player.ID)) > 0
+	}
+	return true
+}
+
+// cardPayment moves kekels because of a card and returns the amount that changed
+// hands. A nil payer or payee is the bank. When the player whose turn it is cannot
+// afford the charge, it becomes their pending debt, to be raised in the liquidation
+// window or end in bankruptcy; anyone else pays what they have.
+func cardPayment(game *models.Game, from, to *models.Player, amount int, cardID string, result *Result) int {
+	if from != nil && amount > from.Balance {
+		if from.ID == game.CurrentTurn && game.TurnState.PendingRent == nil {
+			creditorID := ""
+			if to != nil {
+				creditorID = to.ID
+			}
+			game.TurnState.PendingRent = &models.Debt{CreditorID: creditorID, Amount: amount}
+			result.emit("rent_due", map[string]interface{}{
+				"playerId": from.ID,
+				"ownerId":  creditorID,
+				"cardId":   cardID,
+				"amount":   amount,
+			})
+			return 0
+		}
+		amount = from.Balance
+	}
+	if amount <= 0 {
+		return 0
+	}
+
+	data := map[string]interface{}{
+		"cardId": cardID,
+		"amount": amount,
+	}
+	fromID, toID := "", ""
+	if from != nil {
+		from.Balance -= amount
+		fromID = from.ID
+		data["fromPlayerId"] = from.ID
+		data["fromBalance"] = from.Balance
+	}
+	if to != nil {
+		to.Balance += amount
+		toID = to.ID
+		data["toPlayerId"] = to.ID
+		data["toBalance"] = to.Balance
+	}
+
+	result.recordCard(fromID, toID, amount, "", cardID)
+	result.emit("card_payment", data)
+	return amount
+}
+
+// cardTarget resolves the player a card is aimed at. A chosen target must be another
+// player still in the game. It returns nil when there is nobody to target.
+func cardTarget(game *models.Game, player *models.Player, target cards.Target, payload interface{}) (*models.Player, error) {
+	switch target {
+	case cards.TargetSelf:
+		return player, nil
+	case cards.TargetPrevious:
+		if previous := findPlayer(game, previousPlayerID(game, player.ID)); previous != nil && previous.ID != player.ID {
+			return previous, nil
+		}
+		return nil, nil
+	}
+
+	chosen := findPlayer(game, payloadString(payload, "targetPlayerId"))
+	if chosen == nil || chosen.ID == player.ID || !isInGame(chosen) {
+		return nil, ErrInvalidTarget
+	}
+	return chosen, nil
+}
+
+// cardProperty looks up the property named by a payload field
+func cardProperty(game *models.Game, payload interface{}, key string) (*models.Property, error) {
+	propertyID := payloadString(payload, key)
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: %s is required", ErrInvalidPayload, key)
+	}
+	property := findProperty(game, propertyID)
+	if property == nil {
+		return nil, ErrPropertyNotFound
+	}
+	return property, nil
+}
+
+// poorestOpponent returns the other player with the smallest balance
+func poorestOpponent(game *models.Game, player *models.Player) *models.Player {
+	var poorest *models.Player
+	for _, other := range opponents(game, player) {
+		if poorest == nil || other.Balance < poorest.Balance {
+			poorest = other
+		}
+	}
+	return poorest
+}
+
+// countBuildings counts the engagements and blue checkmarks on a player's properties
+func countBuildings(game *models.Game, playerID string) (engagements, checkmarks int) {
+	for _, property := range game.BoardState.Properties {
+		if property.OwnerID != playerID {
+			continue
+		}
+		engagements += property.Engagements
+		if property.BlueCheckmark {
+			checkmarks++
+		}
+	}
+	return engagements, checkmarks
+}
+
+// mortgageable returns the properties of a player that could be mortgaged now
+func mortgageable(game *models.Game, playerID string) []*models.Property {
+	var properties []*models.Property
+	for i := range game.BoardState.Properties {
+		property := &game.BoardState.Properties[i]
+		if property.OwnerID == playerID && CanMortgage(game, property) == nil {
+			properties = append(properties, property)
+		}
+	}
+	return properties
+}
+
+// moveByCard moves a player's token to a position. Cards that advance the player
+// forwards pay salary for passing START; teleports and moves backwards do not.
+func (e *Engine) moveByCard(game *models.Game, player *models.Player, to int, forward bool, cardID string, result *Result) {
+	from := player.Position
+	size := e.board.Size()
+	passedStart := forward && to != from && e.passesStart(from, (to-from+size)%size)
+	player.Position = to
+
+	if passedStart {
+		pay := salary(game)
+		player.Balance += pay
+		result.record(models.TransactionTypeSalary, "", player.ID, pay, "")
+	}
+
+	result.emit("player_moved", map[string]interface{}{
+		"playerId":    player.ID,
+		"from":        from,
+		"position":    to,
+		"passedStart": passedStart,
+		"balance":     player.Balance,
+		"cardId":      cardID,
+	})
+}
+
+// doublePendingRent doubles rent that is owed to the player but not yet paid, for a
+// double rent card played as a reaction to a player landing on their property
+func doublePendingRent(game *models.Game, player *models.Player, cardID string, result *Result) {
+	debt := game.TurnState.PendingRent
+	if debt == nil || debt.CreditorID != player.ID || debt.PropertyID == "" {
+		return
+	}
+	debt.Amount *= 2
+
+	result.emit("rent_due", map[string]interface{}{
+		"playerId":   game.CurrentTurn,
+		"ownerId":    player.ID,
+		"propertyId": debt.PropertyID,
+		"amount":     debt.Amount,
+		"cardId":     cardID,
+	})
+}
+
+// swapPositions swaps the tokens of two players without resolving either landing
+func swapPositions(a, b *models.Player, cardID string, result *Result) {
+	a.Position, b.Position = b.Position, a.Position
+
+	result.emit("positions_swapped", map[string]interface{}{
+		"cardId": cardID,
+		"players": map[string]int{
+			a.ID: a.Position,
+			b.ID: b.Position,
+		},
+	})
+}
+
+// rollCollect rolls the card's dice and pays the player for every pip
+func (e *Engine) rollCollect(game *models.Game, player *models.Player, def cards.Definition, result *Result) {
+	dice := make([]int, def.Params.Dice)
+	total := 0
+	for i := range dice {
+		dice[i] = e.rollDie(game)
+		total += dice[i]
+	}
+
+	result.emit("card_dice_rolled", map[string]interface{}{
+		"playerId": player.ID,
+		"cardId":   def.ID,
+		"dice":     dice,
+		"total":    total,
+	})
+	cardPayment(game, nil, player, total*def.Params.PerUnit, def.ID, result)
+}
+
+// lowestRollPays has every other player roll a die. Whoever rolls lowest pays the
+// player; everyone tied for lowest pays.
+func (e *Engine) lowestRollPays(game *models.Game, player *models.Player, def cards.Definition, result *Result) {
+	others := opponents(game, player)
+	if len(others) == 0 {
+		return
+	}
+
+	rolls := make(map[string]int, len(others))
+	lowest := 7
+	for _, other := range others {
+		rolls[other.ID] = e.rollDie(game)
+		if rolls[other.ID] < lowest {
+			lowest = rolls[other.ID]
+		}
+	}
+
+	var losers []string
+	for _, other := range others {
+		if rolls[other.ID] == lowest {
+			losers = append(losers, other.ID)
+		}
+	}
+	result.emit("card_dice_rolled", map[string]interface{}{
+		"playerId": player.ID,
+		"cardId":   def.ID,
+		"rolls":    rolls,
+		"losers":   losers,
+	})
+
+	for _, other := range others {
+		if rolls[other.ID] == lowest {
+			cardPayment(game, other, player, def.Params.Amount, def.ID, result)
+		}
+	}
+}
+
+// forceMortgage mortgages a property: one of the player's own for a card aimed at
+// themselves, or one chosen from another player's unprotected properties
+func (e *Engine) forceMortgage(game *models.Game, player *models.Player, def cards.Definition, payload interface{}, result *Result) error {
+	if def.Params.Target == cards.TargetSelf && len(mortgageable(game, player.ID)) == 0 {
+		return nil
+	}
+	if def.Params.Target != cards.TargetSelf && !anyOpponentMortgageable(game, player) {
+		return nil
+	}
+
+	property, err := cardProperty(game, payload, "propertyId")
+	if err != nil {
+		return err
+	}
+	owner := findPlayer(game, property.OwnerID)
+	if owner == nil || !isInGame(owner) {
+		return ErrInvalidTarget
+	}
+	if def.Params.Target == cards.TargetSelf {
+		if owner.ID != player.ID {
+			return ErrNotPropertyOwner
+		}
+	} else {
+		if owner.ID == player.ID {
+			return ErrInvalidTarget
+		}
+		if isPropertyImmune(game, property) {
+			return ErrPropertyImmune
+		}
+	}
+	if err := CanMortgage(game, property); err != nil {
+		return err
+	}
+
+	mortgage(property, owner, result)
+	return nil
+}
+
+// anyOpponentMortgageable reports whether another player has a property a card could force them to mortgage
+func anyOpponentMortgageable(game *models.Game, player *models.Player) bool {
+	for _, other := range opponents(game, player) {
+		for _, property := range mortgageable(game, other.ID) {
+			if !isPropertyImmune(game, property) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// takeOverProperty buys another player's property from them at a multiple of its price.
+// As with any trade, a property with buildings on it cannot change hands.
+func (e *Engine) takeOverProperty(game *models.Game, player *models.Player, def cards.Definition, payload interface{}, result *Result) error {
+	property, err := cardProperty(game, payload, "propertyId")
+	if err != nil {
+		return err
+	}
+	owner := findPlayer(game, property.OwnerID)
+	if owner == nil || owner.ID == player.ID || !isInGame(owner) {
+		return ErrInvalidTarget
+	}
+	if isPropertyImmune(game, property) {
+		return ErrPropertyImmune
+	}
+	if hasBuildings(property) {
+		return ErrHasBuildings
+	}
+	price := property.Price * def.Params.PricePercent / 100
+	if player.Balance < price+transferCost(property) {
+		return ErrInsufficientFunds
+	}
+
+	player.Balance -= price
+	owner.Balance += price
+	result.recordCard(player.ID, owner.ID, price, property.ID, def.ID)
+	transferProperty(property, owner, player, result)
+
+	result.emit("property_transferred", map[string]interface{}{
+		"propertyId":   property.ID,
+		"fromPlayerId": owner.ID,
+		"toPlayerId":   player.ID,
+		"price":        price,
+		"cardId":       def.ID,
+		"balance":      player.Balance,
+		"ownerBalance": owner.Balance,
+	})
+	return nil
+}
+
+// buyAtDiscount moves the player to an unowned property and buys it from the bank
+// at a share of its price
+func (e *Engine) buyAtDiscount(game *models.Game, player *models.Player, def cards.Definition, payload interface{}, result *Result) error {
+	property, err := cardProperty(game, payload, "propertyId")
+	if err != nil {
+		return err
+	}
+	if property.OwnerID != "" {
+		return ErrPropertyOwned
+	}
+	price := MarketPrice(game, property) * def.Params.PricePercent / 100
+	if player.Balance < price {
+		return ErrInsufficientFunds
+	}
+
+	e.moveByCard(game, player, property.Position, true, def.ID, result)
+	purchase(game, player, property, price, result)
+	return nil
+}
+
+// exchangeProperty swaps one of the player's properties for another player's.
+// Properties with buildings cannot be exchanged.
+func (e *Engine) exchangeProperty(game *models.Game, player *models.Player, def cards.Definition, payload interface{}, result *Result) error {
+	own, err := cardProperty(game, payload, "propertyId")
+	if err != nil {
+		return err
+	}
+	theirs, err := cardProperty(game, payload, "targetPropertyId")
+	if err != nil {
+		return err
+	}
+	if own.OwnerID != player.ID {
+		return ErrNotPropertyOwner
+	}
+	other := findPlayer(game, theirs.OwnerID)
+	if other == nil || other.ID == player.ID || !isInGame(other) {
+		return ErrInvalidTarget
+	}
+	if isPropertyImmune(game, theirs) {
+		return ErrPropertyImmune
+	}
+	if hasBuildings(own) || hasBuildings(theirs) {
+		return ErrHasBuildings
+	}
+	if player.Balance < transferCost(theirs) || other.Balance < transferCost(own) {
+		return ErrInsufficientFunds
+	}
+
+	transferProperty(own, player, other, result)
+	transferProperty(theirs, other, player, result)
+
+	result.emit("properties_exchanged", map[string]interface{}{
+		"cardId":           def.ID,
+		"playerId":         player.ID,
+		"propertyId":       own.ID,
+		"targetPlayerId":   other.ID,
+		"targetPropertyId": theirs.ID,
+	})
+	return nil
+}
+
+// freeEngagement builds an engagement on one of the player's properties at no cost,
+// following the usual building rules
+func (e *Engine) freeEngagement(game *models.Game, player *models.Player, def cards.Definition, payload interface{}, result *Result) error {
+	property, err := buildableProperty(game, player, payload)
+	if err != nil {
+		return err
+	}
+	supply, err := engagementAvailable(game, property)
+	if err != nil {
+		return err
+	}
+
+	addEngagement(property, supply, payload)
+	result.emit("engagement_built", map[string]interface{}{
+		"playerId":    player.ID,
+		"propertyId":  property.ID,
+		"engagements": property.Engagements,
+		"memeName":    property.MemeName,
+		"cost":        0,
+		"balance":     player.Balance,
+		"supply":      *supply,
+		"cardId":      def.ID,
+	})
+	return nil
+}
+
+// removeAllEngagements takes one engagement off every developed property back to the
+// bank, sparing the properties of immune players
+func removeAllEngagements(game *models.Game, player *models.Player, result *Result) {
+	supply := buildingSupply(game)
+	for i := range game.BoardState.Properties {
+		property := &game.BoardState.Properties[i]
+		if property.Engagements == 0 || isPropertyImmune(game, property) {
+			continue
+		}
+		property.Engagements--
+		supply.Engagements++
+
+		result.emit("engagement_removed", map[string]interface{}{
+			"playerId":    property.OwnerID,
+			"propertyId":  property.ID,
+			"engagements": property.Engagements,
+			"removedBy":   player.ID,
+			"supply":      *supply,
+		})
+	}
+}
+
+// viewCards shows the player, and only the player, the top cards of a deck
+func (e *Engine) viewCards(game *models.Game, player *models.Player, def cards.Definition, payload interface{}, result *Result) error {
+	cardType := models.CardType(payloadString(payload, "deck"))
+	deck := e.deck(game, cardType)
+	if deck == nil {
+		return fmt.Errorf("%w: a deck is required", ErrInvalidPayload)
+	}
+
+	top := cards.Peek(deck, def.Params.Count)
+	revealed := make([]models.Card, len(top))
+	for i, card := range top {
+		revealed[i] = card.Card()
+	}
+	result.emitTo(player.ID, "cards_revealed", map[string]interface{}{
+		"deck":  cardType,
+		"cards": revealed,
+	})
+	return nil
+}
+
+// stealCard takes a card from another player's hand. Naming only the target reveals
+// their hand to the player, who then names the card to take. A player whose hand is
+// full has nowhere to put a stolen card, so the card does nothing.
+func (e *Engine) stealCard(game *models.Game, player *models.Player, def cards.Definition, payload interface{}, result *Result) (bool, error) {
+	anyHeld := false
+	for _, other := range opponents(game, player) {
+		anyHeld = anyHeld || len(other.Cards) > 0
+	}
+	if !anyHeld {
+		return true, nil
+	}
+
+	if len(player.Cards) >= MaxHandSize {
+		return true, nil
+	}
+
+	target, err := cardTarget(game, player, cards.TargetChosen, payload)
+	if err != nil {
+		return false, err
+	}
+	if len(target.Cards) == 0 {
+		return false, ErrInvalidTarget
+	}
+
+	cardID := payloadString(payload, "targetCardId")
+	if cardID == "" {
+		result.emitTo(player.ID, "hand_revealed", map[string]interface{}{
+			"playerId": target.ID,
+			"cards":    target.Cards,
+		})
+		return false, nil
+	}
+
+	for i, card := range target.Cards {
+		if card.ID != cardID {
+			continue
+		}
+		target.Cards = append(target.Cards[:i], target.Cards[i+1:]...)
+		player.Cards = append(player.Cards, card)
+
+		result.emit("card_stolen", map[string]interface{}{
+			"playerId":     player.ID,
+			"fromPlayerId": target.ID,
+			"cardId":       def.ID,
+		})
+		result.emitTo(player.ID, "card_received", map[string]interface{}{
+			"fromPlayerId": target.ID,
+			"card":         card,
+		})
+		return true, nil
+	}
+	return false, ErrCardNotHeld
+}
+
+// copyLastCard applies the effect of a card played this round or last. The player
+// may name the card to copy; otherwise the most recent one is copied.
+func (e *Engine) copyLastCard(game *models.Game, player *models.Player, payload interface{}, result *Result) (bool, error) {
+	copyID := payloadString(payload, "copyCardId")
+
+	var copied *cards.Definition
+	for i := len(game.BoardState.PlayedCards) - 1; i >= 0; i-- {
+		played := game.BoardState.PlayedCards[i]
+		if played.Round < game.Round-1 || (copyID != "" && played.CardID != copyID) {
+			continue
+		}
+		def, ok := cards.Lookup(played.CardID)
+		if !ok || def.Effect == cards.EffectCopyLastCard {
+			continue
+		}
+		copied = &def
+		break
+	}
+
+	if copied == nil {
+		if copyID != "" {
+			return false, ErrInvalidTarget
+		}
+		return true, nil
+	}
+
+	result.emit("card_copied", map[string]interface{}{
+		"playerId": player.ID,
+		"card":     copied.Card(),
+	})
+	return e.applyCardEffect(game, player, *copied, payload, result)
+}
@@ -0,0 +1,350 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/kekopoly/backend/internal/game/cards"
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// MaxHandSize is the number of cards a player may keep in their hand
+const MaxHandSize = 3
+
+// SetupDecks deals a freshly shuffled MEME, REDPILL and EEGI deck to a game using the
+// game's randomizer, so the deck order can be reproduced from its seed
+func (e *Engine) SetupDecks(game *models.Game) {
+	game.BoardState.Decks = make(map[models.CardType]*models.CardDeck, len(cards.Types()))
+	for _, cardType := range cards.Types() {
+		game.BoardState.Decks[cardType] = cards.NewDeck(cardType, counter{e, game})
+	}
+	game.BoardState.PlayedCards = nil
+	updateCardsRemaining(game)
+}
+
+// deck returns one of the game's decks. Games started before decks existed get
+// their decks the first time a card is drawn.
+func (e *Engine) deck(game *models.Game, cardType models.CardType) *models.CardDeck {
+	if game.BoardState.Decks == nil {
+		e.SetupDecks(game)
+	}
+	return game.BoardState.Decks[cardType]
+}
+
+// updateCardsRemaining copies the size of each draw pile into BoardState.CardsRemaining
+func updateCardsRemaining(game *models.Game) {
+	remaining := func(cardType models.CardType) int {
+		if deck := game.BoardState.Decks[cardType]; deck != nil {
+			return len(deck.DrawPile)
+		}
+		return 0
+	}
+	game.BoardState.CardsRemaining = models.CardCount{
+		Meme:    remaining(models.CardTypeMeme),
+		Redpill: remaining(models.CardTypeRedpill),
+		Eegi:    remaining(models.CardTypeEegi),
+	}
+}
+
+// drawCard draws from the deck of the card space the current player landed on. Kept
+// cards go into the player's hand, cards that need the player's choices wait in
+// TurnState.PendingCard for USE_CARD, and every other card takes effect at once.
+func (e *Engine) drawCard(game *models.Game, player *models.Player, payload interface{}, result *Result) error {
+	if err := requireTurn(game, player); err != nil {
+		return err
+	}
+	if game.TurnState.PendingCard != nil {
+		return ErrCardUnresolved
+	}
+	cardType := game.TurnState.PendingDraw
+	if cardType == "" {
+		return ErrNoCardToDraw
+	}
+
+	deck := e.deck(game, cardType)
+	if deck == nil {
+		return ErrNoCardToDraw
+	}
+	def, reshuffled, ok := cards.Draw(deck, counter{e, game})
+	if !ok {
+		return ErrDeckEmpty
+	}
+	game.TurnState.PendingDraw = ""
+	updateCardsRemaining(game)
+
+	if reshuffled {
+		result.emit("deck_reshuffled", map[string]interface{}{
+			"deck": cardType,
+		})
+	}
+	result.emit("card_drawn", map[string]interface{}{
+		"playerId":       player.ID,
+		"deck":           cardType,
+		"card":           def.Card(),
+		"requiresChoice": def.RequiresChoice(),
+		"cardsRemaining": game.BoardState.CardsRemaining,
+	})
+
+	if def.Kept {
+		if len(player.Cards) < MaxHandSize {
+			keepCard(player, def, result)
+			return nil
+		}
+		// The player must discard a card, either this one or one from their hand
+		card := def.Card()
+		game.TurnState.PendingCard = &card
+		result.emit("hand_full", map[string]interface{}{
+			"playerId":    player.ID,
+			"card":        card,
+			"maxHandSize": MaxHandSize,
+		})
+		return nil
+	}
+	if def.RequiresChoice() {
+		card := def.Card()
+		game.TurnState.PendingCard = &card
+		return nil
+	}
+	// Cards that need no choices cannot be rejected, so the draw always completes
+	_, err := e.playCard(game, player, def, payload, result)
+	return err
+}
+
+// keepCard puts a drawn card into the player's hand
+func keepCard(player *models.Player, def cards.Definition, result *Result) {
+	player.Cards = append(player.Cards, def.Card())
+
+	result.emit("card_kept", map[string]interface{}{
+		"playerId":    player.ID,
+		"card":        def.Card(),
+		"handSize":    len(player.Cards),
+		"maxHandSize": MaxHandSize,
+	})
+}
+
+// useCard plays a card. Naming a card from the player's hand plays it, subject to the
+// card's timing; otherwise it settles the drawn card waiting in TurnState.PendingCard.
+func (e *Engine) useCard(game *models.Game, player *models.Player, payload interface{}, result *Result) error {
+	cardID := payloadString(payload, "cardId")
+	if cardID != "" && handIndex(player, cardID) >= 0 {
+		return e.playHeldCard(game, player, cardID, payload, result)
+	}
+	return e.resolvePendingCard(game, player, cardID, payload, result)
+}
+
+// resolvePendingCard applies the drawn card waiting for the player's choices, or
+// discards it when the player passes on an optional card. A kept card drawn into a
+// full hand waits for the player to name the card to discard.
+func (e *Engine) resolvePendingCard(game *models.Game, player *models.Player, cardID string, payload interface{}, result *Result) error {
+	if err := requireTurn(game, player); err != nil {
+		return err
+	}
+	pending := game.TurnState.PendingCard
+	if pending == nil {
+		if cardID != "" {
+			return ErrCardNotHeld
+		}
+		return ErrNoPendingCard
+	}
+	if cardID != "" && cardID != pending.ID {
+		return ErrCardNotHeld
+	}
+	def, ok := cards.Lookup(pending.ID)
+	if !ok {
+		return ErrNoPendingCard
+	}
+
+	if def.Kept {
+		return e.discardForHand(game, player, def, payload, result)
+	}
+
+	if payloadBool(payload, "pass") {
+		if !def.Optional() && e.hasCardOptions(game, player, def) {
+			return ErrCardNotOptional
+		}
+		game.TurnState.PendingCard = nil
+		e.discard(game, def)
+		result.emit("card_passed", map[string]interface{}{
+			"playerId": player.ID,
+			"card":     pending,
+		})
+		return nil
+	}
+
+	played, err := e.playCard(game, player, def, payload, result)
+	if err != nil {
+		return err
+	}
+	if played {
+		game.TurnState.PendingCard = nil
+	}
+	return nil
+}
+
+// discardForHand settles a kept card drawn into a full hand. The player names the card
+// to discard in discardCardId: the drawn card, or one from their hand to make room.
+// Passing discards the drawn card.
+func (e *Engine) discardForHand(game *models.Game, player *models.Player, drawn cards.Definition, payload interface{}, result *Result) error {
+	discardID := payloadString(payload, "discardCardId")
+	if discardID == "" && payloadBool(payload, "pass") {
+		discardID = drawn.ID
+	}
+	if discardID == "" {
+		return fmt.Errorf("%w: discardCardId is required", ErrInvalidPayload)
+	}
+
+	discarded := drawn
+	if discardID != drawn.ID {
+		i := handIndex(player, discardID)
+		if i < 0 {
+			return ErrCardNotHeld
+		}
+		held, ok := cards.Lookup(discardID)
+		if !ok {
+			return ErrCardNotHeld
+		}
+		discarded = held
+		player.Cards = append(player.Cards[:i], player.Cards[i+1:]...)
+	}
+
+	game.TurnState.PendingCard = nil
+	e.discard(game, discarded)
+	result.emit("card_discarded", map[string]interface{}{
+		"playerId": player.ID,
+		"card":     discarded.Card(),
+	})
+	if discarded.ID != drawn.ID {
+		keepCard(player, drawn, result)
+	}
+	return nil
+}
+
+// playHeldCard plays a card from the player's hand. Cards are checked against their
+// timing, so some can be played out of turn as a reaction.
+func (e *Engine) playHeldCard(game *models.Game, player *models.Player, cardID string, payload interface{}, result *Result) error {
+	def, ok := cards.Lookup(cardID)
+	if !ok {
+		return ErrCardNotHeld
+	}
+	if err := canPlayHeld(game, player, def); err != nil {
+		return err
+	}
+
+	played, err := e.playCard(game, player, def, payload, result)
+	if err != nil || !played {
+		return err
+	}
+	if i := handIndex(player, cardID); i >= 0 {
+		player.Cards = append(player.Cards[:i], player.Cards[i+1:]...)
+	}
+	return nil
+}
+
+// canPlayHeld checks that a card in the player's hand may be played now
+func canPlayHeld(game *models.Game, player *models.Player, def cards.Definition) error {
+	ownTurn := game.CurrentTurn == player.ID
+	switch def.Timing {
+	case cards.TimingAnyTime:
+	case cards.TimingRentDue:
+		debt := game.TurnState.PendingRent
+		if !ownTurn && (debt == nil || debt.CreditorID != player.ID) {
+			return ErrCardNotPlayable
+		}
+	default:
+		if !ownTurn {
+			return ErrNotYourTurn
+		}
+	}
+
+	if def.Effect == cards.EffectGetOutOfShadowban && !isShadowbanned(player) {
+		return ErrCardNotPlayable
+	}
+	return nil
+}
+
+// handIndex returns the index of a card in the player's hand, or -1
+func handIndex(player *models.Player, cardID string) int {
+	for i, card := range player.Cards {
+		if card.ID == cardID {
+			return i
+		}
+	}
+	return -1
+}
+
+// reclaimCards returns the cards held by players who have left the game, such as
+// bankrupt players, to their decks
+func (e *Engine) reclaimCards(game *models.Game, result *Result) {
+	for i := range game.Players {
+		player := &game.Players[i]
+		if isInGame(player) || len(player.Cards) == 0 {
+			continue
+		}
+
+		returned := make([]string, 0, len(player.Cards))
+		for _, card := range player.Cards {
+			if def, ok := cards.Lookup(card.ID); ok {
+				e.discard(game, def)
+				returned = append(returned, card.ID)
+			}
+		}
+		player.Cards = []models.Card{}
+
+		result.emit("cards_returned", map[string]interface{}{
+			"playerId": player.ID,
+			"cardIds":  returned,
+		})
+	}
+}
+
+// playCard applies a card's effect and, once it has taken effect, discards it and
+// announces it. It returns false when the card is still waiting for more choices.
+func (e *Engine) playCard(game *models.Game, player *models.Player, def cards.Definition, payload interface{}, result *Result) (bool, error) {
+	done, err := e.applyCardEffect(game, player, def, payload, result)
+	if err != nil || !done {
+		return false, err
+	}
+
+	e.discard(game, def)
+	game.BoardState.PlayedCards = append(game.BoardState.PlayedCards, models.PlayedCard{
+		CardID:   def.ID,
+		PlayerID: player.ID,
+		Round:    game.Round,
+	})
+
+	result.emit("card_played", map[string]interface{}{
+		"playerId": player.ID,
+		"card":     def.Card(),
+		"effect":   def.Effect,
+		"balance":  player.Balance,
+		"fromHand": handIndex(player, def.ID) >= 0,
+		"reaction": game.CurrentTurn != player.ID,
+	})
+	return true, nil
+}
+
+// discard puts a card on its deck's discard pile
+func (e *Engine) discard(game *models.Game, def cards.Definition) {
+	if deck := e.deck(game, def.Type); deck != nil {
+		cards.Discard(deck, def.ID)
+	}
+}
+
+// forgetOldPlayedCards runs at the start of each round and keeps only the cards
+// played in the previous round, which are the ones a copy card can copy
+func forgetOldPlayedCards(game *models.Game) {
+	kept := game.BoardState.PlayedCards[:0]
+	for _, played := range game.BoardState.PlayedCards {
+		if played.Round >= game.Round-1 {
+			kept = append(kept, played)
+		}
+	}
+	game.BoardState.PlayedCards = kept
+}
+
+// requireCardResolved rejects moving on while a card space or drawn card is unresolved
+func requireCardResolved(game *models.Game) error {
+	if game.TurnState.PendingDraw != "" || game.TurnState.PendingCard != nil {
+		return ErrCardUnresolved
+	}
+	return nil
+}
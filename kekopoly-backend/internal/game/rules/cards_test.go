@@ -0,0 +1,392 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rng"
+)
+
+// newCardGame returns a game where p1 has rolled onto a card space and the deck
+// holds the given cards in order
+func newCardGame(cardType models.CardType, cardIDs ...string) *models.Game {
+	game := newTestGame()
+	game.Round = 1
+	game.TurnState.HasRolled = true
+	game.TurnState.PendingDraw = cardType
+	game.BoardState.Decks = map[models.CardType]*models.CardDeck{
+		models.CardTypeMeme:    {DrawPile: []string{}, DiscardPile: []string{}},
+		models.CardTypeRedpill: {DrawPile: []string{}, DiscardPile: []string{}},
+		models.CardTypeEegi:    {DrawPile: []string{}, DiscardPile: []string{}},
+	}
+	game.BoardState.Decks[cardType].DrawPile = cardIDs
+	return game
+}
+
+func TestLandingOnCardSpaceOffersDraw(t *testing.T) {
+	game := newTestGame()
+	game.Players[0].Position = 20
+	engine := newTestEngine(1, 3)
+
+	result, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, models.CardTypeMeme, game.TurnState.PendingDraw)
+	assert.Equal(t, []string{"dice_rolled", "card_draw_available"}, eventTypes(result))
+
+	_, err = engine.Apply(game, action(models.ActionTypeEndTurn, "p1", nil))
+	assert.ErrorIs(t, err, ErrCardUnresolved)
+}
+
+func TestDrawCardAppliesEffectAndDiscards(t *testing.T) {
+	game := newCardGame(models.CardTypeMeme, "meme_01", "meme_02")
+	engine := newTestEngine()
+
+	result, err := engine.Apply(game, action(models.ActionTypeDrawCard, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"card_drawn", "card_payment", "card_played"}, eventTypes(result))
+	assert.Equal(t, 1550, game.Players[0].Balance)
+	assert.Equal(t, 1450, game.Players[1].Balance)
+	assert.Equal(t, models.CardType(""), game.TurnState.PendingDraw)
+	assert.Equal(t, []string{"meme_01"}, game.BoardState.Decks[models.CardTypeMeme].DiscardPile)
+	assert.Equal(t, 1, game.BoardState.CardsRemaining.Meme)
+	require.Len(t, result.Transactions, 1)
+	assert.Equal(t, models.TransactionTypeCardEffect, result.Transactions[0].Type)
+	assert.Equal(t, "meme_01", result.Transactions[0].CardID)
+
+	_, err = engine.Apply(game, action(models.ActionTypeDrawCard, "p1", nil))
+	assert.ErrorIs(t, err, ErrNoCardToDraw)
+}
+
+func TestCardPaymentsAreCappedAtBalance(t *testing.T) {
+	game := newCardGame(models.CardTypeRedpill, "redpill_01")
+	game.Players[1].Balance = 100
+	engine := newTestEngine()
+
+	_, err := engine.Apply(game, action(models.ActionTypeDrawCard, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 1600, game.Players[0].Balance)
+	assert.Equal(t, 0, game.Players[1].Balance)
+}
+
+func TestUnaffordableCardChargeBecomesDebt(t *testing.T) {
+	game := newCardGame(models.CardTypeMeme, "meme_03")
+	game.Players[0].Balance = 30
+	engine := newTestEngine()
+
+	result, err := engine.Apply(game, timedAction(models.ActionTypeDrawCard, "p1", nil, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"card_drawn", "rent_due", "card_played", "debt_unaffordable"}, eventTypes(result))
+	assert.Equal(t, 30, game.Players[0].Balance)
+	debt := game.TurnState.PendingRent
+	require.NotNil(t, debt)
+	assert.Equal(t, "p2", debt.CreditorID)
+	assert.Equal(t, 50, debt.Amount)
+	require.NotNil(t, debt.DueBy)
+
+	_, err = engine.Apply(game, timedAction(models.ActionTypeEndTurn, "p1", nil, time.Second))
+	assert.ErrorIs(t, err, ErrRentUnpaid)
+
+	result, err = engine.Tick(game, auctionStart.Add(LiquidationWindow))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"player_bankrupt", "game_over"}, eventTypes(result))
+	assert.Equal(t, models.PlayerStatusBankrupt, game.Players[0].Status)
+	assert.Equal(t, 1530, game.Players[1].Balance)
+}
+
+func TestChoiceCardWaitsForUseCard(t *testing.T) {
+	game := newCardGame(models.CardTypeMeme, "meme_12")
+	game.Players[0].Position = 24
+	game.Players[1].Position = 4
+	engine := newTestEngine()
+
+	result, err := engine.Apply(game, action(models.ActionTypeDrawCard, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"card_drawn"}, eventTypes(result))
+	require.NotNil(t, game.TurnState.PendingCard)
+	assert.Equal(t, "meme_12", game.TurnState.PendingCard.ID)
+
+	_, err = engine.Apply(game, action(models.ActionTypeUseCard, "p1", map[string]interface{}{"targetPlayerId": "p1"}))
+	assert.ErrorIs(t, err, ErrInvalidTarget)
+	assert.Equal(t, 24, game.Players[0].Position)
+
+	result, err = engine.Apply(game, action(models.ActionTypeUseCard, "p1", map[string]interface{}{"targetPlayerId": "p2"}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"positions_swapped", "card_played"}, eventTypes(result))
+	assert.Equal(t, 4, game.Players[0].Position)
+	assert.Equal(t, 24, game.Players[1].Position)
+	assert.Nil(t, game.TurnState.PendingCard)
+	assert.Equal(t, []models.PlayedCard{{CardID: "meme_12", PlayerID: "p1", Round: 1}}, game.BoardState.PlayedCards)
+}
+
+func TestMandatoryCardCannotBePassed(t *testing.T) {
+	game := newCardGame(models.CardTypeMeme, "meme_08")
+	game.BoardState.Properties[0].OwnerID = "p1"
+	game.Players[0].Properties = []string{"prop-4"}
+	engine := newTestEngine()
+
+	_, err := engine.Apply(game, action(models.ActionTypeDrawCard, "p1", nil))
+	require.NoError(t, err)
+
+	_, err = engine.Apply(game, action(models.ActionTypeUseCard, "p1", map[string]interface{}{"pass": true}))
+	assert.ErrorIs(t, err, ErrCardNotOptional)
+
+	_, err = engine.Apply(game, action(models.ActionTypeUseCard, "p1", map[string]interface{}{"propertyId": "prop-4"}))
+	require.NoError(t, err)
+	assert.True(t, game.BoardState.Properties[0].Mortgaged)
+	assert.Equal(t, 1550, game.Players[0].Balance)
+}
+
+func TestOptionalCardCanBePassed(t *testing.T) {
+	game := newCardGame(models.CardTypeRedpill, "redpill_05")
+	engine := newTestEngine()
+
+	_, err := engine.Apply(game, action(models.ActionTypeDrawCard, "p1", nil))
+	require.NoError(t, err)
+
+	result, err := engine.Apply(game, action(models.ActionTypeUseCard, "p1", map[string]interface{}{"pass": true}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"card_passed"}, eventTypes(result))
+	assert.Nil(t, game.TurnState.PendingCard)
+	assert.Equal(t, []string{"redpill_05"}, game.BoardState.Decks[models.CardTypeRedpill].DiscardPile)
+}
+
+func TestImmunityBlocksTakeOver(t *testing.T) {
+	game := newCardGame(models.CardTypeRedpill, "redpill_05")
+	game.BoardState.Properties[0].OwnerID = "p2"
+	game.Players[1].Properties = []string{"prop-4"}
+	game.Players[1].StatusEffects = []models.SpecialEffect{{Type: StatusPropertyImmunity, AppliedBy: "p2", ExpiresAfterTurns: 2}}
+	engine := newTestEngine()
+
+	_, err := engine.Apply(game, action(models.ActionTypeDrawCard, "p1", nil))
+	require.NoError(t, err)
+	_, err = engine.Apply(game, action(models.ActionTypeUseCard, "p1", map[string]interface{}{"propertyId": "prop-4"}))
+	assert.ErrorIs(t, err, ErrPropertyImmune)
+
+	game.Players[1].StatusEffects = nil
+	_, err = engine.Apply(game, action(models.ActionTypeUseCard, "p1", map[string]interface{}{"propertyId": "prop-4"}))
+	require.NoError(t, err)
+	assert.Equal(t, "p1", game.BoardState.Properties[0].OwnerID)
+	assert.Equal(t, 1300, game.Players[0].Balance)
+	assert.Equal(t, 1700, game.Players[1].Balance)
+}
+
+func TestTakeOverNeedsAnUnbuiltPropertyOfAPlayerInTheGame(t *testing.T) {
+	game := newCardGame(models.CardTypeRedpill, "redpill_05")
+	game.BoardState.Properties[0].OwnerID = "p2"
+	game.BoardState.Properties[0].Engagements = 1
+	game.Players[1].Properties = []string{"prop-4"}
+	engine := newTestEngine()
+
+	_, err := engine.Apply(game, action(models.ActionTypeDrawCard, "p1", nil))
+	require.NoError(t, err)
+	_, err = engine.Apply(game, action(models.ActionTypeUseCard, "p1", map[string]interface{}{"propertyId": "prop-4"}))
+	assert.ErrorIs(t, err, ErrHasBuildings)
+
+	game.BoardState.Properties[0].Engagements = 0
+	game.Players[1].Status = models.PlayerStatusBankrupt
+	_, err = engine.Apply(game, action(models.ActionTypeUseCard, "p1", map[string]interface{}{"propertyId": "prop-4"}))
+	assert.ErrorIs(t, err, ErrInvalidTarget)
+
+	assert.Equal(t, "p2", game.BoardState.Properties[0].OwnerID)
+	assert.Equal(t, 1500, game.Players[0].Balance)
+	require.NotNil(t, game.TurnState.PendingCard)
+}
+
+func TestVoteForfeitChargesMostVotedPlayer(t *testing.T) {
+	game := newCardGame(models.CardTypeMeme, "meme_13")
+	game.TurnOrder = append(game.TurnOrder, "p3")
+	game.Players = append(game.Players, models.Player{ID: "p3", Balance: 1500, Status: models.PlayerStatusActive})
+	engine := newTestEngine()
+
+	result, err := engine.Apply(game, action(models.ActionTypeDrawCard, "p1", nil))
+	require.NoError(t, err)
+	assert.Contains(t, eventTypes(result), "vote_started")
+	require.NotNil(t, game.TurnState.PendingVote)
+
+	vote := func(voterID, targetID string) (*Result, error) {
+		return engine.Apply(game, action(models.ActionTypeCastVote, voterID, map[string]interface{}{"targetPlayerId": targetID}))
+	}
+	_, err = vote("p2", "p2")
+	assert.ErrorIs(t, err, ErrInvalidTarget)
+	_, err = vote("p1", "p2")
+	require.NoError(t, err)
+	_, err = vote("p1", "p3")
+	assert.ErrorIs(t, err, ErrAlreadyVoted)
+	_, err = vote("p2", "p1")
+	require.NoError(t, err)
+
+	result, err = vote("p3", "p2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vote_cast", "card_payment", "vote_resolved"}, eventTypes(result))
+	assert.Equal(t, 1450, game.Players[1].Balance)
+	assert.Nil(t, game.TurnState.PendingVote)
+}
+
+func TestRentEffectsLastUntilTheirRoundsRunOut(t *testing.T) {
+	game := newCardGame(models.CardTypeRedpill, "redpill_08")
+	game.BoardState.Properties[0].OwnerID = "p1"
+	engine := newTestEngine()
+	engine.SetMarketSettings(MarketSettings{})
+
+	_, err := engine.Apply(game, action(models.ActionTypeDrawCard, "p1", nil))
+	require.NoError(t, err)
+	_, err = engine.Apply(game, action(models.ActionTypeUseCard, "p1", map[string]interface{}{"cardId": "redpill_08"}))
+	require.NoError(t, err)
+	assert.Equal(t, 20, game.BoardState.Properties[0].RentCurrent)
+
+	_, err = engine.Apply(game, action(models.ActionTypeEndTurn, "p1", nil))
+	require.NoError(t, err)
+	game.TurnState.HasRolled = true
+	result, err := engine.Apply(game, action(models.ActionTypeEndTurn, "p2", nil))
+	require.NoError(t, err)
+	assert.Contains(t, eventTypes(result), "status_effect_expired")
+	assert.Empty(t, game.Players[0].StatusEffects)
+	assert.Equal(t, 10, game.BoardState.Properties[0].RentCurrent)
+}
+
+func TestDoubleRentAgainstAndRentCollector(t *testing.T) {
+	game := newTestGame()
+	game.BoardState.Properties[0].OwnerID = "p2"
+	game.Players[0].StatusEffects = []models.SpecialEffect{{Type: StatusDoubleRentAgainst, ExpiresAfterTurns: 1}}
+	game.TurnOrder = append(game.TurnOrder, "p3")
+	game.Players = append(game.Players, models.Player{ID: "p3", Balance: 1500, Status: models.PlayerStatusActive,
+		StatusEffects: []models.SpecialEffect{{Type: StatusCollectAllRent, ExpiresAfterTurns: 1}}})
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	require.NotNil(t, game.TurnState.PendingRent)
+	assert.Equal(t, 20, game.TurnState.PendingRent.Amount)
+	assert.Equal(t, "p3", game.TurnState.PendingRent.CreditorID)
+}
+
+func TestFrozenPlayerDoesNotMove(t *testing.T) {
+	game := newTestGame()
+	game.Players[0].StatusEffects = []models.SpecialEffect{{Type: StatusFrozen, AppliedBy: "p2", ExpiresAfterTurns: 1}}
+	engine := newTestEngine(2, 2)
+
+	result, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dice_rolled", "movement_frozen"}, eventTypes(result))
+	assert.Equal(t, testBoard.StartPosition(), game.Players[0].Position)
+	assert.True(t, game.TurnState.HasRolled)
+}
+
+func TestSetupDecksIsReproducible(t *testing.T) {
+	first, second := newTestGame(), newTestGame()
+	NewEngine(testBoard, rng.NewSeeded(11)).SetupDecks(first)
+	NewEngine(testBoard, rng.NewSeeded(11)).SetupDecks(second)
+
+	assert.Equal(t, first.BoardState.Decks, second.BoardState.Decks)
+	assert.Equal(t, models.CardCount{Meme: 16, Redpill: 16, Eegi: 16}, first.BoardState.CardsRemaining)
+	assert.Positive(t, first.RNGDraws)
+}
+
+func TestKeptCardGoesToHand(t *testing.T) {
+	game := newCardGame(models.CardTypeMeme, "meme_07")
+	engine := newTestEngine()
+
+	result, err := engine.Apply(game, action(models.ActionTypeDrawCard, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"card_drawn", "card_kept"}, eventTypes(result))
+	require.Len(t, game.Players[0].Cards, 1)
+	assert.Equal(t, "meme_07", game.Players[0].Cards[0].ID)
+	assert.Nil(t, game.TurnState.PendingCard)
+	assert.Empty(t, game.Players[0].StatusEffects)
+
+	// Diamond Hands can be played at any time, even on another player's turn
+	game.CurrentTurn = "p2"
+	result, err = engine.Apply(game, action(models.ActionTypeUseCard, "p1", map[string]interface{}{"cardId": "meme_07"}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"status_effect_added", "card_played"}, eventTypes(result))
+	assert.Equal(t, true, result.Events[1].Data["reaction"])
+	assert.Equal(t, true, result.Events[1].Data["fromHand"])
+	assert.Empty(t, game.Players[0].Cards)
+	assert.True(t, hasStatus(&game.Players[0], StatusPropertyImmunity))
+	assert.Equal(t, []string{"meme_07"}, game.BoardState.Decks[models.CardTypeMeme].DiscardPile)
+}
+
+func TestFullHandMustDiscard(t *testing.T) {
+	game := newCardGame(models.CardTypeEegi, "eegi_11")
+	game.Players[0].Cards = []models.Card{{ID: "meme_07"}, {ID: "redpill_08"}, {ID: "redpill_11"}}
+	engine := newTestEngine()
+
+	result, err := engine.Apply(game, action(models.ActionTypeDrawCard, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"card_drawn", "hand_full"}, eventTypes(result))
+	require.NotNil(t, game.TurnState.PendingCard)
+
+	_, err = engine.Apply(game, action(models.ActionTypeEndTurn, "p1", nil))
+	assert.ErrorIs(t, err, ErrCardUnresolved)
+	_, err = engine.Apply(game, action(models.ActionTypeUseCard, "p1", nil))
+	assert.ErrorIs(t, err, ErrInvalidPayload)
+
+	result, err = engine.Apply(game, action(models.ActionTypeUseCard, "p1", map[string]interface{}{"discardCardId": "redpill_08"}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"card_discarded", "card_kept"}, eventTypes(result))
+	assert.Nil(t, game.TurnState.PendingCard)
+	assert.Equal(t, []string{"meme_07", "redpill_11", "eegi_11"}, handIDs(&game.Players[0]))
+	assert.Equal(t, []string{"redpill_08"}, game.BoardState.Decks[models.CardTypeRedpill].DiscardPile)
+}
+
+func TestRentReactionDoublesRentOwed(t *testing.T) {
+	game := newTestGame()
+	game.BoardState.Properties[0].OwnerID = "p2"
+	game.Players[1].Cards = []models.Card{{ID: "redpill_08"}}
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeUseCard, "p2", map[string]interface{}{"cardId": "redpill_08"}))
+	assert.ErrorIs(t, err, ErrCardNotPlayable)
+
+	_, err = engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	require.NotNil(t, game.TurnState.PendingRent)
+	assert.Equal(t, 10, game.TurnState.PendingRent.Amount)
+
+	_, err = engine.Apply(game, action(models.ActionTypeUseCard, "p2", map[string]interface{}{"cardId": "redpill_08"}))
+	require.NoError(t, err)
+	assert.Equal(t, 20, game.TurnState.PendingRent.Amount)
+	assert.Empty(t, game.Players[1].Cards)
+}
+
+func TestShadowbanCardNeedsShadowban(t *testing.T) {
+	game := newTestGame()
+	game.Players[1].Cards = []models.Card{{ID: "redpill_11"}}
+	engine := newTestEngine()
+
+	_, err := engine.Apply(game, action(models.ActionTypeUseCard, "p2", map[string]interface{}{"cardId": "redpill_11"}))
+	assert.ErrorIs(t, err, ErrCardNotPlayable)
+
+	game.Players[1].Shadowbanned = true
+	game.Players[1].ShadowbanRemainingTurns = 2
+	_, err = engine.Apply(game, action(models.ActionTypeUseCard, "p2", map[string]interface{}{"cardId": "redpill_11"}))
+	require.NoError(t, err)
+	assert.False(t, isShadowbanned(&game.Players[1]))
+}
+
+func TestBankruptPlayersCardsReturnToDeck(t *testing.T) {
+	game := newCardGame(models.CardTypeMeme, "meme_02")
+	game.TurnOrder = append(game.TurnOrder, "p3")
+	game.Players = append(game.Players, models.Player{ID: "p3", Status: models.PlayerStatusBankrupt,
+		Cards: []models.Card{{ID: "meme_07"}, {ID: "eegi_11"}}})
+	engine := newTestEngine()
+
+	result, err := engine.Apply(game, action(models.ActionTypeDrawCard, "p1", nil))
+	require.NoError(t, err)
+	assert.Contains(t, eventTypes(result), "cards_returned")
+	assert.Empty(t, game.Players[2].Cards)
+	assert.Equal(t, []string{"meme_02", "meme_07"}, game.BoardState.Decks[models.CardTypeMeme].DiscardPile)
+	assert.Equal(t, []string{"eegi_11"}, game.BoardState.Decks[models.CardTypeEegi].DiscardPile)
+}
+
+func handIDs(player *models.Player) []string {
+	ids := make([]string, len(player.Cards))
+	for i, card := range player.Cards {
+		ids[i] = card.ID
+	}
+	return ids
+}
@@ -0,0 +1,544 @@
+package rules
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kekopoly/backend/internal/game/board"
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rng"
+)
+
+// StartSalary is paid to a player each time they pass or land on START
+const StartSalary = 200
+
+// Engine is the authoritative rules engine. It validates game actions and applies
+// them to a game's state in place. It has no knowledge of storage or transport;
+// the game manager is responsible for persisting and broadcasting the result.
+type Engine struct {
+	board  *board.Board
+	rnd    rng.Randomizer
+	market MarketSettings
+}
+
+// NewEngine creates a rules engine for a game played on b that takes every random
+// decision from rnd. Each game should have its own engine so its random sequence
+// can be reproduced.
+func NewEngine(b *board.Board, rnd rng.Randomizer) *Engine {
+	return &Engine{board: b, rnd: rnd, market: DefaultMarketSettings()}
+}
+
+// SetMarketSettings replaces the odds and length of bull and crash markets
+func (e *Engine) SetMarketSettings(settings MarketSettings) {
+	e.market = settings
+}
+
+// intn draws a random number in [0, n) and counts the draw on the game, so a
+// reloaded game can resume its random sequence where it left off
+func (e *Engine) intn(game *models.Game, n int) int {
+	game.RNGDraws++
+	return e.rnd.Intn(n)
+}
+
+// rollDie rolls a single six-sided die
+func (e *Engine) rollDie(game *models.Game) int {
+	return e.intn(game, 6) + 1
+}
+
+// Shuffle randomly permutes n elements of the game's state, such as its turn order
+func (e *Engine) Shuffle(game *models.Game, n int, swap func(i, j int)) {
+	rng.Shuffle(counter{e, game}, n, swap)
+}
+
+// counter adapts an engine to rng.Randomizer while counting draws on a game
+type counter struct {
+	engine *Engine
+	game   *models.Game
+}
+
+// Intn implements rng.Randomizer
+func (c counter) Intn(n int) int {
+	return c.engine.intn(c.game, n)
+}
+
+// Apply validates an action against the game and, if it is legal, mutates the game
+// and returns a description of what changed. Illegal actions leave the game untouched
+// and return an error for which IsRuleViolation is true.
+func (e *Engine) Apply(game *models.Game, action models.GameAction) (*Result, error) {
+	if game.Status != models.GameStatusActive {
+		return nil, ErrGameNotActive
+	}
+
+	player := findPlayer(game, action.PlayerID)
+	if player == nil {
+		return nil, ErrPlayerNotFound
+	}
+	if !isInGame(player) {
+		return nil, ErrPlayerNotActive
+	}
+
+	result := newResult(game, action)
+
+	var err error
+	switch action.Type {
+	case models.ActionTypeRollDice:
+		err = e.rollDice(game, player, action.Payload, result.timestamp, result)
+	case models.ActionTypeBuyProperty:
+		err = e.buyProperty(game, player, action.Payload, result)
+	case models.ActionTypeDeclineProperty:
+		err = e.declineProperty(game, player, result)
+	case models.ActionTypePlaceBid:
+		err = e.placeBid(game, player, action.Payload, result)
+	case models.ActionTypeCloseAuction:
+		// Any player may close an auction whose countdown has run out
+		err = e.closeAuction(game, result)
+	case models.ActionTypePayRent:
+		err = e.payRent(game, player, result)
+	case models.ActionTypeDeclareBankruptcy:
+		err = e.declareBankruptcy(game, player, result)
+	case models.ActionTypeMortgageProperty:
+		err = e.mortgageProperty(game, player, action.Payload, result)
+	case models.ActionTypeUnmortgageProperty:
+		err = e.unmortgageProperty(game, player, action.Payload, result)
+	case models.ActionTypeBuildEngagement:
+		err = e.buildEngagement(game, player, action.Payload, result)
+	case models.ActionTypeBuildCheckmark:
+		err = e.buildCheckmark(game, player, action.Payload, result)
+	case models.ActionTypeSellEngagement:
+		err = e.sellEngagement(game, player, action.Payload, result)
+	case models.ActionTypeSellCheckmark:
+		err = e.sellCheckmark(game, player, action.Payload, result)
+	case models.ActionTypePayJailFine:
+		err = e.payJailFine(game, player, result)
+	case models.ActionTypeUseJailCard:
+		err = e.useJailCard(game, player, result)
+	case models.ActionTypeEndTurn:
+		err = e.endTurn(game, player, result)
+	case models.ActionTypeDrawCard:
+		err = e.drawCard(game, player, action.Payload, result)
+	case models.ActionTypeUseCard:
+		err = e.useCard(game, player, action.Payload, result)
+	case models.ActionTypeCastVote:
+		err = e.castVote(game, player, action.Payload, result)
+	case models.ActionTypeSpecial:
+		err = e.useSpecial(game, player, action.Payload, result)
+	case models.ActionTypeTrade:
+		err = e.trade(game, player, action.Payload, result)
+	default:
+		err = fmt.Errorf("%w: %s", ErrUnknownActionType, action.Type)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !action.ByBot {
+		markPresent(player, result)
+	}
+	e.settle(game, result)
+	return result, nil
+}
+
+// settle tidies up after an accepted action
+func (e *Engine) settle(game *models.Game, result *Result) {
+	checkDebts(game, result)
+	e.reclaimCards(game, result)
+	expireTrades(game, result)
+	// Ownership, buildings, mortgages and the last roll all affect rent
+	RefreshRents(game)
+	updateNetWorths(game)
+}
+
+// requireTurn rejects actions from anyone but the player whose turn it is
+func requireTurn(game *models.Game, player *models.Player) error {
+	if game.CurrentTurn != player.ID {
+		return ErrNotYourTurn
+	}
+	return nil
+}
+
+// rollDice rolls two dice for the current player and moves their token.
+// Rolling doubles lets the player roll again once the space they landed on is settled,
+// but a third consecutive double sends them to jail instead of moving. A frozen
+// player rolls but stays where they are.
+func (e *Engine) rollDice(game *models.Game, player *models.Player, payload interface{}, timestamp time.Time, result *Result) error {
+	if err := requireTurn(game, player); err != nil {
+		return err
+	}
+	if game.TurnState.HasRolled {
+		return ErrAlreadyRolled
+	}
+	if game.TurnState.Auction != nil {
+		return ErrAuctionRunning
+	}
+	if game.TurnState.PendingRent != nil {
+		return ErrRentUnpaid
+	}
+	// A property landed on before doubles goes to its buyer or up for auction first
+	if game.TurnState.PendingPurchase != "" {
+		return ErrPurchasePending
+	}
+	if err := requireCardResolved(game); err != nil {
+		return err
+	}
+
+	die1, die2 := e.rollDie(game), e.rollDie(game)
+	total := die1 + die2
+	doubles := die1 == die2
+
+	jailed := player.InJail
+	frozen := !jailed && hasStatus(player, StatusFrozen)
+	releaseReason := ""
+	moves := true
+	switch {
+	case jailed:
+		// A jailed player gets one attempt per turn and never rolls again, even on doubles
+		game.TurnState.HasRolled = true
+		releaseReason = jailRoll(player, doubles)
+		moves = jailRollMoves(player, releaseReason)
+	case frozen:
+		game.TurnState.HasRolled = true
+		moves = false
+	case doubles:
+		game.TurnState.DoublesCount++
+		moves = game.TurnState.DoublesCount < maxDoubles
+	default:
+		game.TurnState.HasRolled = true
+	}
+
+	from := player.Position
+	to := from
+	passedStart := false
+	if moves {
+		to = (from + total) % e.board.Size()
+		passedStart = e.passesStart(from, total)
+		player.Position = to
+	}
+
+	game.LastRoll = &models.DiceRoll{
+		PlayerID:     player.ID,
+		Dice1:        die1,
+		Dice2:        die2,
+		Total:        total,
+		Doubles:      doubles,
+		DoublesCount: game.TurnState.DoublesCount,
+		FromPosition: from,
+		ToPosition:   to,
+		PassedStart:  passedStart,
+		RequestID:    payloadString(payload, "requestId"),
+		Timestamp:    timestamp,
+	}
+
+	pay := salary(game)
+	if passedStart {
+		player.Balance += pay
+		result.record(models.TransactionTypeSalary, "", player.ID, pay, "")
+	}
+
+	result.emit("dice_rolled", map[string]interface{}{
+		"playerId":     player.ID,
+		"dice":         []int{die1, die2},
+		"dice1":        die1,
+		"dice2":        die2,
+		"doubles":      doubles,
+		"doublesCount": game.TurnState.DoublesCount,
+		"from":         from,
+		"position":     to,
+		"passedStart":  passedStart,
+		"balance":      player.Balance,
+		"requestId":    game.LastRoll.RequestID,
+	})
+	if passedStart {
+		result.emit("salary_collected", map[string]interface{}{
+			"playerId": player.ID,
+			"amount":   pay,
+			"balance":  player.Balance,
+		})
+	}
+
+	switch {
+	case jailed:
+		settleJailRoll(game, player, releaseReason, result)
+	case frozen:
+		result.emit("movement_frozen", map[string]interface{}{
+			"playerId": player.ID,
+			"position": player.Position,
+		})
+	case !moves:
+		e.sendToJail(game, player, JailReasonDoubles, result)
+	}
+	if moves {
+		e.resolveLanding(game, player, result)
+	}
+	return nil
+}
+
+// passesStart reports whether moving the given number of spaces forward from a
+// position passes or lands on START
+func (e *Engine) passesStart(from, steps int) bool {
+	size := e.board.Size()
+	distance := (e.board.StartPosition() - from + size) % size
+	if distance == 0 {
+		distance = size
+	}
+	return steps >= distance
+}
+
+// resolveLanding works out what the player owes or may do on the space they landed on
+func (e *Engine) resolveLanding(game *models.Game, player *models.Player, result *Result) {
+	if space := e.board.SpaceAt(player.Position); space != nil {
+		switch space.Type {
+		case board.SpaceTypeCollect:
+			player.Balance += space.Amount
+			result.record(models.TransactionTypeCollect, "", player.ID, space.Amount, "")
+			result.emit("space_collected", map[string]interface{}{
+				"playerId": player.ID,
+				"position": player.Position,
+				"amount":   space.Amount,
+			})
+			return
+		case board.SpaceTypeSpecial:
+			switch space.Effect {
+			case SpaceEffectShadowban:
+				shadowban(player, DefaultShadowbanTurns, ShadowbanSourceSpace, result)
+			case SpaceEffectSkipTurn:
+				addStatusEffect(player, StatusSkipNextTurn, "", 0, result)
+			}
+			return
+		case board.SpaceTypeCard:
+			game.TurnState.PendingDraw = space.Deck
+			result.emit("card_draw_available", map[string]interface{}{
+				"playerId": player.ID,
+				"deck":     space.Deck,
+			})
+			return
+		}
+	}
+
+	property := propertyAt(game, player.Position)
+	if property == nil {
+		return
+	}
+
+	switch {
+	case property.OwnerID == "":
+		if hasStatus(player, StatusMustBuyNext) {
+			// The card is used up on this property even if the player cannot afford it
+			removeStatusEffect(player, StatusMustBuyNext, result)
+			if price := MarketPrice(game, property); player.Balance >= price {
+				purchase(game, player, property, price, result)
+				return
+			}
+		}
+		game.TurnState.PendingPurchase = property.ID
+		result.emit("purchase_available", map[string]interface{}{
+			"playerId":   player.ID,
+			"propertyId": property.ID,
+			"price":      MarketPrice(game, property),
+		})
+	case property.OwnerID != player.ID && !property.Mortgaged:
+		if owner := findPlayer(game, property.OwnerID); owner != nil && isShadowbanned(owner) {
+			result.emit("rent_blocked", map[string]interface{}{
+				"playerId":   player.ID,
+				"ownerId":    property.OwnerID,
+				"propertyId": property.ID,
+				"reason":     "shadowban",
+			})
+			return
+		}
+		creditorID := property.OwnerID
+		if hostID := propertyHost(game, property); hostID != "" {
+			if hostID == player.ID {
+				return
+			}
+			creditorID = hostID
+		}
+		if collector := rentCollector(game); collector != nil && collector.ID != property.OwnerID {
+			if collector.ID == player.ID {
+				return
+			}
+			creditorID = collector.ID
+		}
+		rent := CalculateRent(game, property)
+		if hasStatus(player, StatusDoubleRentAgainst) {
+			rent *= 2
+		}
+		if rent <= 0 {
+			return
+		}
+		game.TurnState.PendingRent = &models.Debt{
+			CreditorID: creditorID,
+			Amount:     rent,
+			PropertyID: property.ID,
+		}
+		result.emit("rent_due", map[string]interface{}{
+			"playerId":   player.ID,
+			"ownerId":    creditorID,
+			"propertyId": property.ID,
+			"amount":     rent,
+		})
+	}
+}
+
+// buyProperty buys the unowned property the current player landed on
+func (e *Engine) buyProperty(game *models.Game, player *models.Player, payload interface{}, result *Result) error {
+	if err := requireTurn(game, player); err != nil {
+		return err
+	}
+
+	propertyID := payloadString(payload, "propertyId")
+	if propertyID == "" {
+		propertyID = game.TurnState.PendingPurchase
+	}
+	if propertyID == "" || propertyID != game.TurnState.PendingPurchase {
+		return ErrPropertyNotForSale
+	}
+
+	property := findProperty(game, propertyID)
+	if property == nil {
+		return ErrPropertyNotFound
+	}
+	if property.OwnerID != "" {
+		return ErrPropertyOwned
+	}
+	price := MarketPrice(game, property)
+	if player.Balance < price {
+		return ErrInsufficientFunds
+	}
+
+	purchase(game, player, property, price, result)
+	return nil
+}
+
+// purchase sells an unowned property to a player at the given price.
+// Callers must check that the player can afford it.
+func purchase(game *models.Game, player *models.Player, property *models.Property, price int, result *Result) {
+	player.Balance -= price
+	property.OwnerID = player.ID
+	addOwnedProperty(player, property.ID)
+	game.TurnState.PendingPurchase = ""
+
+	result.record(models.TransactionTypePurchase, player.ID, "", price, property.ID)
+	result.emit("property_purchased", map[string]interface{}{
+		"playerId":   player.ID,
+		"propertyId": property.ID,
+		"price":      price,
+		"balance":    player.Balance,
+	})
+}
+
+// payRent settles the rent the current player owes for the space they landed on
+func (e *Engine) payRent(game *models.Game, player *models.Player, result *Result) error {
+	if err := requireTurn(game, player); err != nil {
+		return err
+	}
+
+	debt := game.TurnState.PendingRent
+	if debt == nil {
+		return ErrNoRentDue
+	}
+	if player.Balance < debt.Amount {
+		return ErrInsufficientFunds
+	}
+
+	player.Balance -= debt.Amount
+	ownerBalance := 0
+	if owner := findPlayer(game, debt.CreditorID); owner != nil {
+		owner.Balance += debt.Amount
+		ownerBalance = owner.Balance
+	}
+	game.TurnState.PendingRent = nil
+
+	result.record(models.TransactionTypeRent, player.ID, debt.CreditorID, debt.Amount, debt.PropertyID)
+	result.emit("rent_paid", map[string]interface{}{
+		"playerId":     player.ID,
+		"ownerId":      debt.CreditorID,
+		"propertyId":   debt.PropertyID,
+		"amount":       debt.Amount,
+		"balance":      player.Balance,
+		"ownerBalance": ownerBalance,
+	})
+	return nil
+}
+
+// ownedProperty looks up the property named in the payload and checks the player owns it
+func ownedProperty(game *models.Game, player *models.Player, payload interface{}) (*models.Property, error) {
+	propertyID := payloadString(payload, "propertyId")
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: propertyId is required", ErrInvalidPayload)
+	}
+
+	property := findProperty(game, propertyID)
+	if property == nil {
+		return nil, ErrPropertyNotFound
+	}
+	if property.OwnerID != player.ID {
+		return nil, ErrNotPropertyOwner
+	}
+	return property, nil
+}
+
+// endTurn passes the turn to the next player still in the game
+func (e *Engine) endTurn(game *models.Game, player *models.Player, result *Result) error {
+	if err := requireTurn(game, player); err != nil {
+		return err
+	}
+	if !game.TurnState.HasRolled {
+		return ErrMustRollFirst
+	}
+	if game.TurnState.PendingRent != nil {
+		return ErrRentUnpaid
+	}
+	if game.TurnState.PendingPurchase != "" {
+		return ErrPurchasePending
+	}
+	if err := requireCardResolved(game); err != nil {
+		return err
+	}
+	if game.TurnState.Auction != nil {
+		return ErrAuctionRunning
+	}
+
+	e.passTurn(game, player, result)
+	return nil
+}
+
+// passTurn hands the turn from a player to the next player still in the game, starting
+// a new round when play wraps around the turn order
+func (e *Engine) passTurn(game *models.Game, player *models.Player, result *Result) {
+	// A vote still open when the turn ends is decided by the votes cast so far
+	e.closeVote(game, result)
+	game.CurrentTurn = nextPlayerID(game, player.ID)
+	game.TurnState = models.TurnState{}
+	startTurnClock(game, result.timestamp)
+
+	result.emit("turn_ended", map[string]interface{}{
+		"playerId": player.ID,
+	})
+	countDownShadowban(player, result)
+	if startsRound(game, player.ID, game.CurrentTurn) {
+		game.Round++
+		if checkRoundLimit(game, result) {
+			return
+		}
+		warnFinalRound(game, result)
+		e.advanceMarket(game, result)
+		countDownStatusEffects(game, result)
+		countDownPropertyEffects(game, result)
+		forgetOldPlayedCards(game)
+	}
+	result.emit("game_turn", map[string]interface{}{
+		"currentTurn":  game.CurrentTurn,
+		"turnOrder":    game.TurnOrder,
+		"turnDeadline": game.TurnState.Deadline,
+	})
+
+	// A player sent to sit out their next turn passes it straight on
+	if next := findPlayer(game, game.CurrentTurn); next != nil && hasStatus(next, StatusSkipNextTurn) {
+		removeStatusEffect(next, StatusSkipNextTurn, result)
+		result.emit("turn_skipped", map[string]interface{}{
+			"playerId": next.ID,
+		})
+		e.passTurn(game, next, result)
+	}
+}
@@ -0,0 +1,330 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/kekopoly/backend/internal/game/board"
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rng"
+)
+
+var testBoard = board.Default()
+
+// newTestEngine returns an engine whose dice always roll the given faces in order
+func newTestEngine(rolls ...int) *Engine {
+	values := make([]int, len(rolls))
+	for i, face := range rolls {
+		values[i] = face - 1
+	}
+	return NewEngine(testBoard, rng.NewScripted(values...))
+}
+
+// newTestGame returns an active two-player game with a few properties on the board
+func newTestGame() *models.Game {
+	return &models.Game{
+		ID:          primitive.NewObjectID(),
+		Status:      models.GameStatusActive,
+		CurrentTurn: "p1",
+		TurnOrder:   []string{"p1", "p2"},
+		Players: []models.Player{
+			{ID: "p1", Position: testBoard.StartPosition(), Balance: 1500, Status: models.PlayerStatusActive},
+			{ID: "p2", Position: testBoard.StartPosition(), Balance: 1500, Status: models.PlayerStatusActive},
+		},
+		BoardState: models.BoardState{
+			Properties: []models.Property{
+				{ID: "prop-4", Position: 4, Type: models.PropertyTypeRegular, Price: 100, RentBase: 10},
+				{ID: "prop-7", Position: 7, Type: models.PropertyTypeRegular, Price: 200, RentBase: 20},
+			},
+		},
+	}
+}
+
+func action(actionType models.ActionType, playerID string, payload interface{}) models.GameAction {
+	return models.GameAction{Type: actionType, PlayerID: playerID, Payload: payload}
+}
+
+func TestRollDiceMovesPlayerAndOffersPurchase(t *testing.T) {
+	game := newTestGame()
+	engine := newTestEngine(1, 2)
+
+	result, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, game.Players[0].Position)
+	assert.True(t, game.TurnState.HasRolled)
+	assert.Equal(t, "prop-4", game.TurnState.PendingPurchase)
+	require.NotNil(t, game.LastRoll)
+	assert.Equal(t, 1, game.LastRoll.Dice1)
+	assert.Equal(t, 2, game.LastRoll.Dice2)
+	assert.False(t, game.LastRoll.Doubles)
+	assert.Equal(t, 2, game.RNGDraws)
+	require.Len(t, result.Events, 2)
+	assert.Equal(t, "dice_rolled", result.Events[0].Type)
+	assert.Equal(t, 4, result.Events[0].Data["position"])
+	assert.Equal(t, "purchase_available", result.Events[1].Type)
+}
+
+func TestRollDiceRejectsOutOfTurnAndSecondRoll(t *testing.T) {
+	game := newTestGame()
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeRollDice, "p2", nil))
+	assert.ErrorIs(t, err, ErrNotYourTurn)
+	assert.True(t, IsRuleViolation(err))
+
+	_, err = engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+
+	_, err = engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	assert.ErrorIs(t, err, ErrAlreadyRolled)
+	assert.Equal(t, 4, game.Players[0].Position)
+}
+
+func TestRollDiceWrapsAroundBoardAndPaysSalary(t *testing.T) {
+	game := newTestGame()
+	game.Players[0].Position = 24
+	engine := newTestEngine(2, 3)
+
+	result, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 3, game.Players[0].Position)
+	assert.True(t, game.LastRoll.PassedStart)
+	assert.Equal(t, 1500+StartSalary, game.Players[0].Balance)
+	require.Len(t, result.Transactions, 1)
+	assert.Equal(t, models.TransactionTypeSalary, result.Transactions[0].Type)
+}
+
+func TestRollDiceLandingOnStartPaysSalary(t *testing.T) {
+	game := newTestGame()
+	game.Players[0].Position = 22
+	engine := newTestEngine(2, 3)
+
+	_, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, testBoard.StartPosition(), game.Players[0].Position)
+	assert.Equal(t, 1500+StartSalary, game.Players[0].Balance)
+}
+
+func TestRollDiceLeavingStartPaysNoSalary(t *testing.T) {
+	game := newTestGame()
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.False(t, game.LastRoll.PassedStart)
+	assert.Equal(t, 1500, game.Players[0].Balance)
+}
+
+func TestLandingOnCollectSpacePaysOut(t *testing.T) {
+	game := newTestGame()
+	engine := newTestEngine(2, 3)
+
+	result, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 6, game.Players[0].Position)
+	assert.Equal(t, []string{"dice_rolled", "space_collected"}, eventTypes(result))
+	assert.Equal(t, 1500+200, game.Players[0].Balance)
+	require.Len(t, result.Transactions, 1)
+	assert.Equal(t, models.TransactionTypeCollect, result.Transactions[0].Type)
+	assert.Equal(t, "p1", result.Transactions[0].ToPlayerID)
+	assert.Equal(t, 200, result.Transactions[0].Amount)
+}
+
+func TestLandingOnSkipTurnSpaceSitsOutNextTurn(t *testing.T) {
+	game := newTestGame()
+	game.Players[0].Position = 19
+	engine := newTestEngine(1, 2, 1, 3)
+
+	_, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 22, game.Players[0].Position)
+	assert.True(t, hasStatus(&game.Players[0], StatusSkipNextTurn))
+	_, err = engine.Apply(game, action(models.ActionTypeEndTurn, "p1", nil))
+	require.NoError(t, err)
+
+	_, err = engine.Apply(game, action(models.ActionTypeRollDice, "p2", nil))
+	require.NoError(t, err)
+	result, err := engine.Apply(game, action(models.ActionTypeEndTurn, "p2", nil))
+	require.NoError(t, err)
+	assert.Contains(t, eventTypes(result), "turn_skipped")
+	assert.Equal(t, "p2", game.CurrentTurn)
+	assert.False(t, hasStatus(&game.Players[0], StatusSkipNextTurn))
+}
+
+func TestRollDiceDoublesGrantsAnotherRoll(t *testing.T) {
+	game := newTestGame()
+	engine := newTestEngine(2, 2, 1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", map[string]interface{}{"requestId": "req-1"}))
+	require.NoError(t, err)
+	assert.Equal(t, 5, game.Players[0].Position)
+	assert.False(t, game.TurnState.HasRolled)
+	assert.Equal(t, 1, game.TurnState.DoublesCount)
+	assert.True(t, game.LastRoll.Doubles)
+	assert.Equal(t, "req-1", game.LastRoll.RequestID)
+
+	_, err = engine.Apply(game, action(models.ActionTypeEndTurn, "p1", nil))
+	assert.ErrorIs(t, err, ErrMustRollFirst)
+
+	_, err = engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 8, game.Players[0].Position)
+	assert.True(t, game.TurnState.HasRolled)
+	assert.Equal(t, 1, game.LastRoll.DoublesCount)
+}
+
+func TestRollAfterDoublesWaitsForPurchaseDecision(t *testing.T) {
+	game := newTestGame()
+	game.BoardState.Properties = append(game.BoardState.Properties,
+		models.Property{ID: "prop-5", Position: 5, Type: models.PropertyTypeRegular, Price: 150, RentBase: 15})
+	engine := newTestEngine(2, 2, 1, 2)
+
+	// Doubles land on prop-5, which must be bought or auctioned before rolling again
+	_, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "prop-5", game.TurnState.PendingPurchase)
+
+	_, err = engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	assert.ErrorIs(t, err, ErrPurchasePending)
+	assert.Equal(t, 5, game.Players[0].Position)
+
+	_, err = engine.Apply(game, action(models.ActionTypeDeclineProperty, "p1", nil))
+	require.NoError(t, err)
+	require.NotNil(t, game.TurnState.Auction)
+	assert.Equal(t, "prop-5", game.TurnState.Auction.PropertyID)
+}
+
+func TestEndTurnWaitsForPurchaseDecision(t *testing.T) {
+	game := newTestGame()
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "prop-4", game.TurnState.PendingPurchase)
+
+	_, err = engine.Apply(game, action(models.ActionTypeEndTurn, "p1", nil))
+	assert.ErrorIs(t, err, ErrPurchasePending)
+	assert.Equal(t, "p1", game.CurrentTurn)
+}
+
+func TestBuyProperty(t *testing.T) {
+	game := newTestGame()
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeBuyProperty, "p1", nil))
+	assert.ErrorIs(t, err, ErrPropertyNotForSale)
+
+	_, err = engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+
+	result, err := engine.Apply(game, action(models.ActionTypeBuyProperty, "p1", map[string]interface{}{"propertyId": "prop-4"}))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1400, game.Players[0].Balance)
+	assert.Equal(t, "p1", game.BoardState.Properties[0].OwnerID)
+	assert.Equal(t, []string{"prop-4"}, game.Players[0].Properties)
+	assert.Empty(t, game.TurnState.PendingPurchase)
+	require.Len(t, result.Transactions, 1)
+	assert.Equal(t, models.TransactionTypePurchase, result.Transactions[0].Type)
+	assert.Equal(t, 100, result.Transactions[0].Amount)
+}
+
+func TestBuyPropertyInsufficientFundsLeavesStateUntouched(t *testing.T) {
+	game := newTestGame()
+	game.Players[0].Balance = 50
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+
+	_, err = engine.Apply(game, action(models.ActionTypeBuyProperty, "p1", nil))
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	assert.Equal(t, 50, game.Players[0].Balance)
+	assert.Empty(t, game.BoardState.Properties[0].OwnerID)
+}
+
+func TestRentMustBePaidBeforeEndingTurn(t *testing.T) {
+	game := newTestGame()
+	game.BoardState.Properties[1].OwnerID = "p2"
+	game.Players[1].Properties = []string{"prop-7"}
+	engine := newTestEngine(2, 4)
+
+	_, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	require.NotNil(t, game.TurnState.PendingRent)
+
+	_, err = engine.Apply(game, action(models.ActionTypeEndTurn, "p1", nil))
+	assert.ErrorIs(t, err, ErrRentUnpaid)
+
+	result, err := engine.Apply(game, action(models.ActionTypePayRent, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 1480, game.Players[0].Balance)
+	assert.Equal(t, 1520, game.Players[1].Balance)
+	require.Len(t, result.Transactions, 1)
+	assert.Equal(t, models.TransactionTypeRent, result.Transactions[0].Type)
+
+	_, err = engine.Apply(game, action(models.ActionTypeEndTurn, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "p2", game.CurrentTurn)
+	assert.False(t, game.TurnState.HasRolled)
+}
+
+func TestEndTurnRequiresRoll(t *testing.T) {
+	game := newTestGame()
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeEndTurn, "p1", nil))
+	assert.ErrorIs(t, err, ErrMustRollFirst)
+	assert.Equal(t, "p1", game.CurrentTurn)
+}
+
+func TestEndTurnSkipsBankruptPlayers(t *testing.T) {
+	game := newTestGame()
+	game.Players = append(game.Players, models.Player{ID: "p3", Balance: 1500, Status: models.PlayerStatusActive})
+	game.TurnOrder = []string{"p1", "p2", "p3"}
+	game.Players[1].Status = models.PlayerStatusBankrupt
+	engine := newTestEngine(1, 3)
+
+	_, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	_, err = engine.Apply(game, action(models.ActionTypeEndTurn, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "p3", game.CurrentTurn)
+}
+
+func TestMortgageAndUnmortgage(t *testing.T) {
+	game := newTestGame()
+	game.BoardState.Properties[1].OwnerID = "p1"
+	game.Players[0].Properties = []string{"prop-7"}
+	engine := newTestEngine(1, 2)
+	payload := map[string]interface{}{"propertyId": "prop-7"}
+
+	_, err := engine.Apply(game, action(models.ActionTypeMortgageProperty, "p2", payload))
+	assert.ErrorIs(t, err, ErrNotPropertyOwner)
+
+	_, err = engine.Apply(game, action(models.ActionTypeMortgageProperty, "p1", payload))
+	require.NoError(t, err)
+	assert.True(t, game.BoardState.Properties[1].Mortgaged)
+	assert.Equal(t, 1600, game.Players[0].Balance)
+
+	_, err = engine.Apply(game, action(models.ActionTypeMortgageProperty, "p1", payload))
+	assert.ErrorIs(t, err, ErrAlreadyMortgaged)
+
+	_, err = engine.Apply(game, action(models.ActionTypeUnmortgageProperty, "p1", payload))
+	require.NoError(t, err)
+	assert.False(t, game.BoardState.Properties[1].Mortgaged)
+	assert.Equal(t, 1490, game.Players[0].Balance)
+}
+
+func TestApplyRejectsInactiveGame(t *testing.T) {
+	game := newTestGame()
+	game.Status = models.GameStatusLobby
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	assert.ErrorIs(t, err, ErrGameNotActive)
+}
@@ -0,0 +1,77 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrRuleViolation is the base error for every action rejected by the rules.
+// Callers can use IsRuleViolation to tell a rejected move apart from an internal failure.
+var ErrRuleViolation = errors.New("rule violation")
+
+var (
+	ErrGameNotActive       = fmt.Errorf("%w: game is not active", ErrRuleViolation)
+	ErrPlayerNotFound      = fmt.Errorf("%w: player not found in game", ErrRuleViolation)
+	ErrPlayerNotActive     = fmt.Errorf("%w: player is no longer in the game", ErrRuleViolation)
+	ErrNotYourTurn         = fmt.Errorf("%w: not your turn", ErrRuleViolation)
+	ErrAlreadyRolled       = fmt.Errorf("%w: dice already rolled this turn", ErrRuleViolation)
+	ErrMustRollFirst       = fmt.Errorf("%w: roll the dice first", ErrRuleViolation)
+	ErrPropertyNotFound    = fmt.Errorf("%w: property not found", ErrRuleViolation)
+	ErrPropertyNotForSale  = fmt.Errorf("%w: property is not available to buy", ErrRuleViolation)
+	ErrPropertyOwned       = fmt.Errorf("%w: property is already owned", ErrRuleViolation)
+	ErrNotPropertyOwner    = fmt.Errorf("%w: you do not own this property", ErrRuleViolation)
+	ErrInsufficientFunds   = fmt.Errorf("%w: insufficient funds", ErrRuleViolation)
+	ErrNoRentDue           = fmt.Errorf("%w: no rent is due", ErrRuleViolation)
+	ErrRentUnpaid          = fmt.Errorf("%w: rent must be paid before ending the turn", ErrRuleViolation)
+	ErrPurchasePending     = fmt.Errorf("%w: buy or decline the property first", ErrRuleViolation)
+	ErrCanPayDebt          = fmt.Errorf("%w: you can pay what you owe", ErrRuleViolation)
+	ErrAlreadyMortgaged    = fmt.Errorf("%w: property is already mortgaged", ErrRuleViolation)
+	ErrNotMortgaged        = fmt.Errorf("%w: property is not mortgaged", ErrRuleViolation)
+	ErrPropertyMortgaged   = fmt.Errorf("%w: property is mortgaged", ErrRuleViolation)
+	ErrHasBuildings        = fmt.Errorf("%w: property has buildings", ErrRuleViolation)
+	ErrNotBuildable        = fmt.Errorf("%w: buildings cannot be placed on this property", ErrRuleViolation)
+	ErrMaxEngagements      = fmt.Errorf("%w: maximum engagements reached", ErrRuleViolation)
+	ErrNeedFourEngagements = fmt.Errorf("%w: four engagements are required before a blue checkmark", ErrRuleViolation)
+	ErrHasCheckmark        = fmt.Errorf("%w: property already has a blue checkmark", ErrRuleViolation)
+	ErrNoCheckmark         = fmt.Errorf("%w: property has no blue checkmark", ErrRuleViolation)
+	ErrNoEngagements       = fmt.Errorf("%w: property has no engagements", ErrRuleViolation)
+	ErrIncompleteGroup     = fmt.Errorf("%w: you must own every property in the group to build", ErrRuleViolation)
+	ErrGroupMortgaged      = fmt.Errorf("%w: a property in the group is mortgaged", ErrRuleViolation)
+	ErrGroupHasBuildings   = fmt.Errorf("%w: a property in the group has buildings", ErrRuleViolation)
+	ErrUnevenBuilding      = fmt.Errorf("%w: buildings must be built and sold evenly across the group", ErrRuleViolation)
+	ErrEngagementShortage  = fmt.Errorf("%w: the bank has no engagements left", ErrRuleViolation)
+	ErrCheckmarkShortage   = fmt.Errorf("%w: the bank has no blue checkmarks left", ErrRuleViolation)
+	ErrNotInJail           = fmt.Errorf("%w: you are not in jail", ErrRuleViolation)
+	ErrNoJailCard          = fmt.Errorf("%w: you have no get out of jail card", ErrRuleViolation)
+	ErrShadowbanned        = fmt.Errorf("%w: shadowbanned players cannot do that", ErrRuleViolation)
+	ErrNoCardToDraw        = fmt.Errorf("%w: there is no card to draw", ErrRuleViolation)
+	ErrDeckEmpty           = fmt.Errorf("%w: the deck has no cards left", ErrRuleViolation)
+	ErrCardUnresolved      = fmt.Errorf("%w: resolve your card first", ErrRuleViolation)
+	ErrNoPendingCard       = fmt.Errorf("%w: you have no card to play", ErrRuleViolation)
+	ErrCardNotHeld         = fmt.Errorf("%w: you do not have that card", ErrRuleViolation)
+	ErrCardNotOptional     = fmt.Errorf("%w: this card must be played", ErrRuleViolation)
+	ErrCardNotPlayable     = fmt.Errorf("%w: this card cannot be played now", ErrRuleViolation)
+	ErrInvalidTarget       = fmt.Errorf("%w: invalid card target", ErrRuleViolation)
+	ErrPropertyImmune      = fmt.Errorf("%w: property is immune", ErrRuleViolation)
+	ErrEffectActive        = fmt.Errorf("%w: effect is already active on this property", ErrRuleViolation)
+	ErrHandFull            = fmt.Errorf("%w: too many cards in hand", ErrRuleViolation)
+	ErrNoVote              = fmt.Errorf("%w: no vote is open", ErrRuleViolation)
+	ErrAlreadyVoted        = fmt.Errorf("%w: you have already voted", ErrRuleViolation)
+	ErrTradeNotFound       = fmt.Errorf("%w: trade not found", ErrRuleViolation)
+	ErrTradeExpired        = fmt.Errorf("%w: trade has expired", ErrRuleViolation)
+	ErrTradePending        = fmt.Errorf("%w: you already have an open trade with this player", ErrRuleViolation)
+	ErrNotTradeParty       = fmt.Errorf("%w: you cannot answer this trade", ErrRuleViolation)
+	ErrEmptyTrade          = fmt.Errorf("%w: a trade must hand something over", ErrRuleViolation)
+	ErrNoAuction           = fmt.Errorf("%w: no auction is running", ErrRuleViolation)
+	ErrAuctionRunning      = fmt.Errorf("%w: wait for the auction to end", ErrRuleViolation)
+	ErrAuctionClosed       = fmt.Errorf("%w: the auction has ended", ErrRuleViolation)
+	ErrBidTooLow           = fmt.Errorf("%w: bid is too low", ErrRuleViolation)
+	ErrInvalidPayload      = fmt.Errorf("%w: invalid action payload", ErrRuleViolation)
+	ErrActionNotSupported  = fmt.Errorf("%w: action is not supported", ErrRuleViolation)
+	ErrUnknownActionType   = fmt.Errorf("%w: unknown game action type", ErrRuleViolation)
+)
+
+// IsRuleViolation reports whether err was caused by an illegal move rather than a server failure
+func IsRuleViolation(err error) bool {
+	return errors.Is(err, ErrRuleViolation)
+}
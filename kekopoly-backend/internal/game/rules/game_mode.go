@@ -0,0 +1,111 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+const (
+	// DefaultTimeLimitMinutes is the length of a timed game when none is chosen
+	DefaultTimeLimitMinutes = 30
+	// MinTimeLimitMinutes and MaxTimeLimitMinutes bound the length of a timed game
+	MinTimeLimitMinutes = 5
+	MaxTimeLimitMinutes = 180
+	// DefaultRoundLimit is the number of rounds in a round-capped game when none is chosen
+	DefaultRoundLimit = 20
+	// MaxRoundLimit is the most rounds a round-capped game may have
+	MaxRoundLimit = 100
+)
+
+// TimeWarnings are how long before the end of a timed game players are warned, longest first
+var TimeWarnings = []time.Duration{5 * time.Minute, time.Minute}
+
+// ErrInvalidGameMode is returned when a game is created with an unknown mode or a limit out of range
+var ErrInvalidGameMode = errors.New("invalid game mode")
+
+// ConfigureMode sets how a game in the lobby ends. A zero limit picks the default for the mode.
+func ConfigureMode(game *models.Game, mode models.GameMode, timeLimitMinutes, roundLimit int) error {
+	switch mode {
+	case "", models.GameModeClassic:
+		game.Mode = models.GameModeClassic
+		game.TimeLimitMinutes = 0
+		game.RoundLimit = 0
+	case models.GameModeTimed:
+		if timeLimitMinutes == 0 {
+			timeLimitMinutes = DefaultTimeLimitMinutes
+		}
+		if timeLimitMinutes < MinTimeLimitMinutes || timeLimitMinutes > MaxTimeLimitMinutes {
+			return fmt.Errorf("%w: time limit must be between %d and %d minutes", ErrInvalidGameMode, MinTimeLimitMinutes, MaxTimeLimitMinutes)
+		}
+		game.Mode = mode
+		game.TimeLimitMinutes = timeLimitMinutes
+		game.RoundLimit = 0
+	case models.GameModeRoundCapped:
+		if roundLimit == 0 {
+			roundLimit = DefaultRoundLimit
+		}
+		if roundLimit < 1 || roundLimit > MaxRoundLimit {
+			return fmt.Errorf("%w: round limit must be between 1 and %d", ErrInvalidGameMode, MaxRoundLimit)
+		}
+		game.Mode = mode
+		game.TimeLimitMinutes = 0
+		game.RoundLimit = roundLimit
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidGameMode, mode)
+	}
+	return nil
+}
+
+// StartClock starts the clock of the first turn as a game begins, along with the
+// countdown of a timed game. Warnings for more time than the game has are skipped.
+func StartClock(game *models.Game, now time.Time) {
+	startTurnClock(game, now)
+	if game.Mode != models.GameModeTimed || game.TimeLimitMinutes <= 0 {
+		return
+	}
+	limit := time.Duration(game.TimeLimitMinutes) * time.Minute
+	endsAt := now.Add(limit)
+	game.EndsAt = &endsAt
+	game.TimeWarningsSent = 0
+	for game.TimeWarningsSent < len(TimeWarnings) && TimeWarnings[game.TimeWarningsSent] >= limit {
+		game.TimeWarningsSent++
+	}
+}
+
+// nextTimeWarning returns when the next warning that a timed game is ending is due
+func nextTimeWarning(game *models.Game) (time.Time, bool) {
+	if game.EndsAt == nil || game.TimeWarningsSent >= len(TimeWarnings) {
+		return time.Time{}, false
+	}
+	return game.EndsAt.Add(-TimeWarnings[game.TimeWarningsSent]), true
+}
+
+// warnTimeRunningOut warns players once for each warning that has fallen due
+func warnTimeRunningOut(game *models.Game, now time.Time, result *Result) bool {
+	warned := false
+	for {
+		at, ok := nextTimeWarning(game)
+		if !ok || now.Before(at) {
+			return warned
+		}
+		game.TimeWarningsSent++
+		warned = true
+		result.emit("game_ending_soon", map[string]interface{}{
+			"endsAt":           *game.EndsAt,
+			"remainingSeconds": int(game.EndsAt.Sub(now).Seconds()),
+		})
+	}
+}
+
+// warnFinalRound tells players a round-capped game has reached its last round
+func warnFinalRound(game *models.Game, result *Result) {
+	if game.RoundLimit > 0 && game.Round == game.RoundLimit {
+		result.emit("final_round", map[string]interface{}{
+			"round":      game.Round,
+			"roundLimit": game.RoundLimit,
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+func TestConfigureModeAppliesDefaultsAndLimits(t *testing.T) {
+	game := &models.Game{}
+
+	require.NoError(t, ConfigureMode(game, "", 0, 0))
+	assert.Equal(t, models.GameModeClassic, game.Mode)
+
+	require.NoError(t, ConfigureMode(game, models.GameModeTimed, 0, 12))
+	assert.Equal(t, DefaultTimeLimitMinutes, game.TimeLimitMinutes)
+	assert.Zero(t, game.RoundLimit)
+
+	require.NoError(t, ConfigureMode(game, models.GameModeRoundCapped, 0, 12))
+	assert.Equal(t, 12, game.RoundLimit)
+	assert.Zero(t, game.TimeLimitMinutes)
+
+	assert.ErrorIs(t, ConfigureMode(game, models.GameModeTimed, 2, 0), ErrInvalidGameMode)
+	assert.ErrorIs(t, ConfigureMode(game, models.GameModeRoundCapped, 0, MaxRoundLimit+1), ErrInvalidGameMode)
+	assert.ErrorIs(t, ConfigureMode(game, "SPEEDRUN", 0, 0), ErrInvalidGameMode)
+}
+
+func TestTimedGameWarnsBeforeItEnds(t *testing.T) {
+	game := newTestGame()
+	require.NoError(t, ConfigureMode(game, models.GameModeTimed, 10, 0))
+	StartClock(game, auctionStart)
+	engine := newTestEngine()
+
+	endsAt := auctionStart.Add(10 * time.Minute)
+	require.NotNil(t, game.EndsAt)
+	assert.Equal(t, endsAt, *game.EndsAt)
+
+	deadline, ok := NextDeadline(game)
+	require.True(t, ok)
+	assert.Equal(t, endsAt.Add(-5*time.Minute), deadline)
+
+	result, err := engine.Tick(game, deadline)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"game_ending_soon"}, eventTypes(result))
+	assert.Equal(t, 300, result.Events[0].Data["remainingSeconds"])
+
+	deadline, _ = NextDeadline(game)
+	assert.Equal(t, endsAt.Add(-time.Minute), deadline)
+	_, err = engine.Tick(game, deadline)
+	require.NoError(t, err)
+
+	deadline, _ = NextDeadline(game)
+	assert.Equal(t, endsAt, deadline)
+	result, err = engine.Tick(game, deadline)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"game_over"}, eventTypes(result))
+	assert.Equal(t, models.GameEndReasonTimeLimit, game.EndReason)
+}
+
+func TestShortTimedGamesSkipEarlyWarnings(t *testing.T) {
+	game := newTestGame()
+	require.NoError(t, ConfigureMode(game, models.GameModeTimed, MinTimeLimitMinutes, 0))
+	StartClock(game, auctionStart)
+
+	deadline, ok := NextDeadline(game)
+	require.True(t, ok)
+	assert.Equal(t, auctionStart.Add(MinTimeLimitMinutes*time.Minute-time.Minute), deadline)
+}
+
+func TestRoundCappedGameAnnouncesTheFinalRound(t *testing.T) {
+	game := newTestGame()
+	require.NoError(t, ConfigureMode(game, models.GameModeRoundCapped, 0, 2))
+	game.Round = 1
+	engine := newTestEngine()
+	engine.SetMarketSettings(MarketSettings{})
+
+	game.TurnState.HasRolled = true
+	_, err := engine.Apply(game, action(models.ActionTypeEndTurn, "p1", nil))
+	require.NoError(t, err)
+	game.TurnState.HasRolled = true
+	result, err := engine.Apply(game, action(models.ActionTypeEndTurn, "p2", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"turn_ended", "final_round", "game_turn"}, eventTypes(result))
+	assert.Equal(t, models.GameStatusActive, game.Status)
+}
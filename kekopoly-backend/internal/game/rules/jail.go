@@ -0,0 +1,179 @@
+package rules
+
+import (
+	"github.com/kekopoly/backend/internal/game/cards"
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+const (
+	// JailFine is what a player pays the bank to leave PEPE
+	JailFine = 50
+	// MaxJailTurns is the number of failed rolls after which a jailed player must pay the fine
+	MaxJailTurns = 3
+	// maxDoubles is the number of consecutive doubles that sends a player to jail
+	maxDoubles = 3
+
+	// CardEffectGetOutOfJail is the effect of a card that releases its holder from jail
+	CardEffectGetOutOfJail = "GET_OUT_OF_JAIL"
+)
+
+// Reasons a player was sent to jail, reported in the player_jailed event
+const (
+	JailReasonDoubles = "three_doubles"
+	JailReasonCard    = "card"
+)
+
+// Ways a player left jail, reported in the player_released event
+const (
+	ReleaseReasonDoubles    = "doubles"
+	ReleaseReasonFine       = "fine"
+	ReleaseReasonCard       = "card"
+	ReleaseReasonForcedFine = "forced_fine"
+)
+
+// sendToJail moves a player straight to jail without passing START and ends
+// their movement for the turn. Jailed players keep collecting rent.
+func (e *Engine) sendToJail(game *models.Game, player *models.Player, reason string, result *Result) {
+	player.Position = e.board.JailPosition()
+	player.InJail = true
+	player.JailTurns = 0
+
+	if game.CurrentTurn == player.ID {
+		game.TurnState.HasRolled = true
+		game.TurnState.DoublesCount = 0
+		game.TurnState.PendingPurchase = ""
+	}
+
+	result.emit("player_jailed", map[string]interface{}{
+		"playerId": player.ID,
+		"position": player.Position,
+		"reason":   reason,
+	})
+}
+
+// release lets a jailed player out
+func release(player *models.Player, reason string, result *Result) {
+	player.InJail = false
+	player.JailTurns = 0
+
+	result.emit("player_released", map[string]interface{}{
+		"playerId": player.ID,
+		"reason":   reason,
+		"balance":  player.Balance,
+	})
+}
+
+// chargeJailFine takes the fine from a jailed player and records it
+func chargeJailFine(player *models.Player, result *Result) {
+	player.Balance -= JailFine
+	result.record(models.TransactionTypePenalty, player.ID, "", JailFine, "")
+}
+
+// jailCardIndex returns the index of a get-out-of-jail card in the player's hand, or -1
+func jailCardIndex(player *models.Player) int {
+	for i, card := range player.Cards {
+		if card.Effect == CardEffectGetOutOfJail {
+			return i
+		}
+	}
+	return -1
+}
+
+// requireJailed checks that a jailed player can still act on jail before rolling
+func requireJailed(game *models.Game, player *models.Player) error {
+	if err := requireTurn(game, player); err != nil {
+		return err
+	}
+	if !player.InJail {
+		return ErrNotInJail
+	}
+	if game.TurnState.HasRolled {
+		return ErrAlreadyRolled
+	}
+	return nil
+}
+
+// payJailFine pays the fine to leave jail before rolling
+func (e *Engine) payJailFine(game *models.Game, player *models.Player, result *Result) error {
+	if err := requireJailed(game, player); err != nil {
+		return err
+	}
+	if player.Balance < JailFine {
+		return ErrInsufficientFunds
+	}
+
+	chargeJailFine(player, result)
+	release(player, ReleaseReasonFine, result)
+	return nil
+}
+
+// useJailCard spends a get-out-of-jail card to leave jail before rolling
+func (e *Engine) useJailCard(game *models.Game, player *models.Player, result *Result) error {
+	if err := requireJailed(game, player); err != nil {
+		return err
+	}
+	i := jailCardIndex(player)
+	if i < 0 {
+		return ErrNoJailCard
+	}
+
+	if def, ok := cards.Lookup(player.Cards[i].ID); ok {
+		e.discard(game, def)
+	}
+	player.Cards = append(player.Cards[:i], player.Cards[i+1:]...)
+	release(player, ReleaseReasonCard, result)
+	return nil
+}
+
+// jailRoll decides the outcome of a jailed player's roll. Doubles release them;
+// otherwise the failed attempt is counted and, once they have used all their
+// attempts, they are released on condition of paying the fine. An empty reason
+// means the player stays in jail.
+func jailRoll(player *models.Player, doubles bool) (releaseReason string) {
+	if doubles {
+		return ReleaseReasonDoubles
+	}
+	player.JailTurns++
+	if player.JailTurns < MaxJailTurns {
+		return ""
+	}
+	return ReleaseReasonForcedFine
+}
+
+// jailRollMoves reports whether a jailed player moves by their roll. A player forced
+// to pay a fine they cannot afford is released but owes the bank and stays put.
+func jailRollMoves(player *models.Player, releaseReason string) bool {
+	switch releaseReason {
+	case "":
+		return false
+	case ReleaseReasonForcedFine:
+		return player.Balance >= JailFine
+	}
+	return true
+}
+
+// settleJailRoll applies the outcome of a jailed player's roll once the roll has been announced
+func settleJailRoll(game *models.Game, player *models.Player, releaseReason string, result *Result) {
+	switch releaseReason {
+	case "":
+		result.emit("jail_roll_failed", map[string]interface{}{
+			"playerId":       player.ID,
+			"jailTurns":      player.JailTurns,
+			"remainingTurns": MaxJailTurns - player.JailTurns,
+		})
+		return
+	case ReleaseReasonForcedFine:
+		if player.Balance < JailFine {
+			// The fine becomes a debt to the bank that must be settled before the turn ends
+			game.TurnState.PendingRent = &models.Debt{Amount: JailFine}
+			release(player, releaseReason, result)
+			result.emit("rent_due", map[string]interface{}{
+				"playerId": player.ID,
+				"amount":   JailFine,
+			})
+			return
+		}
+		chargeJailFine(player, result)
+	}
+	release(player, releaseReason, result)
+}
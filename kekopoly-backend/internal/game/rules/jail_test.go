@@ -0,0 +1,165 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+func eventTypes(result *Result) []string {
+	types := make([]string, len(result.Events))
+	for i, event := range result.Events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+// newJailedGame returns a game where p1 is in jail at the start of their turn
+func newJailedGame() *models.Game {
+	game := newTestGame()
+	game.Players[0].Position = testBoard.JailPosition()
+	game.Players[0].InJail = true
+	return game
+}
+
+func TestThreeDoublesSendsPlayerToJail(t *testing.T) {
+	game := newTestGame()
+	engine := newTestEngine(1, 1, 2, 2, 3, 3)
+
+	_, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	_, err = engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	game.TurnState.PendingPurchase = ""
+	position := game.Players[0].Position
+
+	result, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dice_rolled", "player_jailed"}, eventTypes(result))
+	assert.Equal(t, position, result.Events[0].Data["position"])
+	assert.Equal(t, 3, game.LastRoll.DoublesCount)
+	assert.True(t, game.Players[0].InJail)
+	assert.Equal(t, testBoard.JailPosition(), game.Players[0].Position)
+	assert.True(t, game.TurnState.HasRolled)
+	assert.Equal(t, 0, game.TurnState.DoublesCount)
+
+	_, err = engine.Apply(game, action(models.ActionTypeEndTurn, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "p2", game.CurrentTurn)
+}
+
+func TestJailedPlayerRollsDoublesToLeave(t *testing.T) {
+	game := newJailedGame()
+	engine := newTestEngine(2, 2)
+
+	result, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "dice_rolled", result.Events[0].Type)
+	assert.Contains(t, eventTypes(result), "player_released")
+	assert.False(t, game.Players[0].InJail)
+	assert.Equal(t, testBoard.JailPosition()+4, game.Players[0].Position)
+	// Doubles out of jail do not earn another roll
+	assert.True(t, game.TurnState.HasRolled)
+}
+
+func TestJailedPlayerStaysAfterFailedRoll(t *testing.T) {
+	game := newJailedGame()
+	engine := newTestEngine(1, 2)
+
+	result, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dice_rolled", "jail_roll_failed"}, eventTypes(result))
+	assert.True(t, game.Players[0].InJail)
+	assert.Equal(t, 1, game.Players[0].JailTurns)
+	assert.Equal(t, testBoard.JailPosition(), game.Players[0].Position)
+	assert.True(t, game.TurnState.HasRolled)
+}
+
+func TestJailFineForcedAfterThirdFailedRoll(t *testing.T) {
+	game := newJailedGame()
+	game.Players[0].JailTurns = MaxJailTurns - 1
+	engine := newTestEngine(1, 2)
+
+	result, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.False(t, game.Players[0].InJail)
+	// Leaving PEPE on the default board passes START
+	assert.Equal(t, 1500-JailFine+StartSalary, game.Players[0].Balance)
+	assert.Equal(t, testBoard.JailPosition()+3, game.Players[0].Position)
+	require.Len(t, result.Transactions, 2)
+	assert.Equal(t, models.TransactionTypePenalty, result.Transactions[1].Type)
+	assert.Equal(t, JailFine, result.Transactions[1].Amount)
+}
+
+func TestForcedJailFineBecomesDebtWhenUnaffordable(t *testing.T) {
+	game := newJailedGame()
+	game.Players[0].JailTurns = MaxJailTurns - 1
+	game.Players[0].Balance = 20
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.False(t, game.Players[0].InJail)
+	assert.Equal(t, testBoard.JailPosition(), game.Players[0].Position)
+	require.NotNil(t, game.TurnState.PendingRent)
+	assert.Equal(t, JailFine, game.TurnState.PendingRent.Amount)
+
+	_, err = engine.Apply(game, action(models.ActionTypeEndTurn, "p1", nil))
+	assert.ErrorIs(t, err, ErrRentUnpaid)
+}
+
+func TestPayJailFine(t *testing.T) {
+	game := newJailedGame()
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypePayJailFine, "p2", nil))
+	assert.ErrorIs(t, err, ErrNotYourTurn)
+
+	result, err := engine.Apply(game, action(models.ActionTypePayJailFine, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"player_released"}, eventTypes(result))
+	assert.False(t, game.Players[0].InJail)
+	assert.Equal(t, 1500-JailFine, game.Players[0].Balance)
+
+	// Having paid, the player rolls and moves as normal
+	_, err = engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, testBoard.JailPosition()+3, game.Players[0].Position)
+
+	_, err = engine.Apply(game, action(models.ActionTypePayJailFine, "p1", nil))
+	assert.ErrorIs(t, err, ErrNotInJail)
+}
+
+func TestUseJailCard(t *testing.T) {
+	game := newJailedGame()
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeUseJailCard, "p1", nil))
+	assert.ErrorIs(t, err, ErrNoJailCard)
+
+	game.Players[0].Cards = []models.Card{
+		{ID: "meme-1", Effect: "COLLECT_FROM_ALL"},
+		{ID: "jail-1", Effect: CardEffectGetOutOfJail},
+	}
+	_, err = engine.Apply(game, action(models.ActionTypeUseJailCard, "p1", nil))
+	require.NoError(t, err)
+	assert.False(t, game.Players[0].InJail)
+	assert.Equal(t, 1500, game.Players[0].Balance)
+	require.Len(t, game.Players[0].Cards, 1)
+	assert.Equal(t, "meme-1", game.Players[0].Cards[0].ID)
+}
+
+func TestJailedOwnerStillCollectsRent(t *testing.T) {
+	game := newTestGame()
+	game.Players[1].InJail = true
+	game.BoardState.Properties[0].OwnerID = "p2"
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	require.NotNil(t, game.TurnState.PendingRent)
+	assert.Equal(t, "p2", game.TurnState.PendingRent.CreditorID)
+}
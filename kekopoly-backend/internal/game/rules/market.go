@@ -0,0 +1,113 @@
+package rules
+
+import (
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// Sources of a market change, reported in the market_changed event
+const (
+	MarketSourceRound   = "round"
+	MarketSourceCard    = "card"
+	MarketSourceExpired = "expired"
+)
+
+// MarketSettings controls how often bull and crash markets occur and how long they last
+type MarketSettings struct {
+	BullChance  int // Percent chance of a bull market starting each round
+	CrashChance int // Percent chance of a crash starting each round
+	BullRounds  int // Rounds a bull market lasts
+	CrashRounds int // Rounds a crash lasts
+}
+
+// DefaultMarketSettings matches the frontend's odds of rolling a 1 or a 6 on a die,
+// with each condition lasting a single round
+func DefaultMarketSettings() MarketSettings {
+	return MarketSettings{
+		BullChance:  16,
+		CrashChance: 16,
+		BullRounds:  1,
+		CrashRounds: 1,
+	}
+}
+
+// rounds returns how long a market condition lasts
+func (s MarketSettings) rounds(condition models.MarketCondition) int {
+	rounds := s.CrashRounds
+	if condition == models.MarketConditionBull {
+		rounds = s.BullRounds
+	}
+	if rounds < 1 {
+		rounds = 1
+	}
+	return rounds
+}
+
+// applyMarketCondition raises an amount by 10% in a bull market and lowers it by
+// 10% in a crash. It applies to rent, property prices and salary.
+func applyMarketCondition(condition models.MarketCondition, amount int) int {
+	switch condition {
+	case models.MarketConditionBull:
+		return amount * 110 / 100
+	case models.MarketConditionCrash:
+		return amount * 90 / 100
+	}
+	return amount
+}
+
+// MarketPrice is what the bank charges for a property under the current market condition
+func MarketPrice(game *models.Game, property *models.Property) int {
+	return applyMarketCondition(game.MarketCondition, property.Price)
+}
+
+// salary is what a player collects for passing START under the current market condition
+func salary(game *models.Game) int {
+	return applyMarketCondition(game.MarketCondition, StartSalary)
+}
+
+// setMarket changes the market condition for a number of rounds
+func setMarket(game *models.Game, condition models.MarketCondition, rounds int, source string, result *Result) {
+	previous := game.MarketCondition
+	if previous == "" {
+		previous = models.MarketConditionNormal
+	}
+	game.MarketCondition = condition
+	game.MarketConditionRemainingTurns = rounds
+	if condition == models.MarketConditionNormal {
+		game.MarketConditionRemainingTurns = 0
+	}
+
+	result.emit("market_changed", map[string]interface{}{
+		"condition":       condition,
+		"previous":        previous,
+		"remainingRounds": game.MarketConditionRemainingTurns,
+		"round":           game.Round,
+		"source":          source,
+	})
+}
+
+// advanceMarket runs at the start of each round. An active condition counts down
+// and ends when its rounds are used up; a normal market may turn bull or crash.
+func (e *Engine) advanceMarket(game *models.Game, result *Result) {
+	if game.MarketCondition == models.MarketConditionBull || game.MarketCondition == models.MarketConditionCrash {
+		game.MarketConditionRemainingTurns--
+		if game.MarketConditionRemainingTurns <= 0 {
+			setMarket(game, models.MarketConditionNormal, 0, MarketSourceExpired, result)
+		}
+		return
+	}
+
+	if e.market.BullChance <= 0 && e.market.CrashChance <= 0 {
+		return
+	}
+	switch roll := e.intn(game, 100); {
+	case roll < e.market.BullChance:
+		setMarket(game, models.MarketConditionBull, e.market.rounds(models.MarketConditionBull), MarketSourceRound, result)
+	case roll < e.market.BullChance+e.market.CrashChance:
+		setMarket(game, models.MarketConditionCrash, e.market.rounds(models.MarketConditionCrash), MarketSourceRound, result)
+	}
+}
+
+// triggerMarket starts a market condition from a card, using the configured length
+func (e *Engine) triggerMarket(game *models.Game, condition models.MarketCondition, result *Result) {
+	setMarket(game, condition, e.market.rounds(condition), MarketSourceCard, result)
+}
@@ -0,0 +1,116 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rng"
+)
+
+// endTurns ends the turn of each player in turn order, starting with the current one
+func endTurns(t *testing.T, engine *Engine, game *models.Game, n int) *Result {
+	t.Helper()
+	var result *Result
+	for i := 0; i < n; i++ {
+		game.TurnState.HasRolled = true
+		var err error
+		result, err = engine.Apply(game, action(models.ActionTypeEndTurn, game.CurrentTurn, nil))
+		require.NoError(t, err)
+	}
+	return result
+}
+
+func TestRoundStartsWhenTurnOrderWraps(t *testing.T) {
+	game := newTestGame()
+	game.Round = 1
+	engine := NewEngine(testBoard, rng.NewScripted(99))
+
+	endTurns(t, engine, game, 1)
+	assert.Equal(t, 1, game.Round)
+	assert.Equal(t, 0, game.RNGDraws)
+
+	// The market is rolled once per round
+	endTurns(t, engine, game, 1)
+	assert.Equal(t, 2, game.Round)
+	assert.Equal(t, 1, game.RNGDraws)
+	assert.Empty(t, game.MarketCondition)
+}
+
+// findEvent returns the first event of a type in the result, or nil
+func findEvent(result *Result, eventType string) *Event {
+	for i := range result.Events {
+		if result.Events[i].Type == eventType {
+			return &result.Events[i]
+		}
+	}
+	return nil
+}
+
+func TestMarketCycle(t *testing.T) {
+	game := newTestGame()
+	game.MarketCondition = models.MarketConditionNormal
+	// The first round roll lands in the crash band, the rest in neither band
+	engine := NewEngine(testBoard, rng.NewScripted(20, 99))
+	engine.SetMarketSettings(MarketSettings{BullChance: 10, CrashChance: 20, BullRounds: 1, CrashRounds: 2})
+
+	result := endTurns(t, engine, game, 2)
+	assert.Equal(t, models.MarketConditionCrash, game.MarketCondition)
+	assert.Equal(t, 2, game.MarketConditionRemainingTurns)
+	event := findEvent(result, "market_changed")
+	require.NotNil(t, event)
+	assert.Equal(t, models.MarketConditionCrash, event.Data["condition"])
+	assert.Equal(t, MarketSourceRound, event.Data["source"])
+
+	endTurns(t, engine, game, 2)
+	assert.Equal(t, models.MarketConditionCrash, game.MarketCondition)
+	assert.Equal(t, 1, game.MarketConditionRemainingTurns)
+
+	result = endTurns(t, engine, game, 2)
+	assert.Equal(t, models.MarketConditionNormal, game.MarketCondition)
+	require.NotNil(t, findEvent(result, "market_changed"))
+	assert.Equal(t, MarketSourceExpired, findEvent(result, "market_changed").Data["source"])
+}
+
+func TestMarketWithoutOddsNeverChanges(t *testing.T) {
+	game := newTestGame()
+	engine := newTestEngine(1, 2)
+	engine.SetMarketSettings(MarketSettings{})
+
+	endTurns(t, engine, game, 6)
+	assert.Equal(t, 0, game.RNGDraws)
+	assert.Empty(t, game.MarketCondition)
+}
+
+func TestMarketAffectsPricesAndSalary(t *testing.T) {
+	game := newTestGame()
+	game.MarketCondition = models.MarketConditionBull
+	game.Players[0].Position = testBoard.StartPosition() - 1
+	engine := newTestEngine(1, 3)
+
+	result, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 1500+220, game.Players[0].Balance)
+	assert.Equal(t, "prop-4", game.TurnState.PendingPurchase)
+	assert.Equal(t, 110, result.Events[len(result.Events)-1].Data["price"])
+
+	game.MarketCondition = models.MarketConditionCrash
+	result, err = engine.Apply(game, action(models.ActionTypeBuyProperty, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 1720-90, game.Players[0].Balance)
+	assert.Equal(t, 90, result.Transactions[0].Amount)
+}
+
+func TestTriggerMarketFromCard(t *testing.T) {
+	game := newTestGame()
+	engine := newTestEngine(1, 2)
+	engine.SetMarketSettings(MarketSettings{BullRounds: 3})
+	result := newResult(game, action(models.ActionTypeUseCard, "p1", nil))
+
+	engine.triggerMarket(game, models.MarketConditionBull, result)
+	assert.Equal(t, models.MarketConditionBull, game.MarketCondition)
+	assert.Equal(t, 3, game.MarketConditionRemainingTurns)
+	assert.Equal(t, MarketSourceCard, result.Events[0].Data["source"])
+}
@@ -0,0 +1,123 @@
+package rules
+
+import (
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// MortgageInterestPercent is the interest the bank charges on a mortgage, as a
+// percentage of the mortgage value
+const MortgageInterestPercent = 10
+
+// mortgageInterest is the interest owed on a mortgaged property, rounded up to the
+// nearest kekel
+func mortgageInterest(property *models.Property) int {
+	return (mortgageValue(property)*MortgageInterestPercent + 99) / 100
+}
+
+// unmortgageCost is the principal plus interest needed to lift a mortgage
+func unmortgageCost(property *models.Property) int {
+	return mortgageValue(property) + mortgageInterest(property)
+}
+
+// transferCost is what the receiving player must pay the bank when a property
+// changes hands: a mortgaged property carries its interest to the new owner
+func transferCost(property *models.Property) int {
+	if !property.Mortgaged {
+		return 0
+	}
+	return mortgageInterest(property)
+}
+
+// transferProperty moves a property between players, charging the receiver the
+// interest on any mortgage. The mortgage itself stays on the property, so the new
+// owner still pays principal plus interest to lift it. Callers must check that the
+// receiver can afford transferCost first.
+func transferProperty(property *models.Property, from, to *models.Player, result *Result) {
+	removeOwnedProperty(from, property.ID)
+	addOwnedProperty(to, property.ID)
+	property.OwnerID = to.ID
+
+	if interest := transferCost(property); interest > 0 {
+		to.Balance -= interest
+		result.record(models.TransactionTypeMortgageInterest, to.ID, "", interest, property.ID)
+	}
+}
+
+// mortgageProperty mortgages one of the player's properties for its mortgage value
+func (e *Engine) mortgageProperty(game *models.Game, player *models.Player, payload interface{}, result *Result) error {
+	property, err := ownedProperty(game, player, payload)
+	if err != nil {
+		return err
+	}
+	if err := CanMortgage(game, property); err != nil {
+		return err
+	}
+
+	mortgage(property, player, result)
+	return nil
+}
+
+// CanMortgage checks that a property is unmortgaged and that neither it nor the
+// rest of its group has buildings
+func CanMortgage(game *models.Game, property *models.Property) error {
+	if property.Mortgaged {
+		return ErrAlreadyMortgaged
+	}
+	if hasBuildings(property) {
+		return ErrHasBuildings
+	}
+	if property.Type == models.PropertyTypeRegular && groupHasBuildings(game, property.Group) {
+		return ErrGroupHasBuildings
+	}
+	return nil
+}
+
+// mortgage mortgages a property and pays its owner the mortgage value.
+// Callers must check CanMortgage first.
+func mortgage(property *models.Property, owner *models.Player, result *Result) {
+	value := mortgageValue(property)
+	property.Mortgaged = true
+	owner.Balance += value
+
+	result.record(models.TransactionTypeMortgage, "", owner.ID, value, property.ID)
+	result.emit("property_mortgaged", map[string]interface{}{
+		"playerId":       owner.ID,
+		"propertyId":     property.ID,
+		"amount":         value,
+		"unmortgageCost": unmortgageCost(property),
+		"balance":        owner.Balance,
+	})
+}
+
+// unmortgageProperty pays off the mortgage on one of the player's properties,
+// charging the principal plus interest
+func (e *Engine) unmortgageProperty(game *models.Game, player *models.Player, payload interface{}, result *Result) error {
+	property, err := ownedProperty(game, player, payload)
+	if err != nil {
+		return err
+	}
+	if !property.Mortgaged {
+		return ErrNotMortgaged
+	}
+
+	principal := mortgageValue(property)
+	interest := mortgageInterest(property)
+	if player.Balance < principal+interest {
+		return ErrInsufficientFunds
+	}
+
+	player.Balance -= principal + interest
+	property.Mortgaged = false
+
+	result.record(models.TransactionTypeUnmortgage, player.ID, "", principal, property.ID)
+	result.record(models.TransactionTypeMortgageInterest, player.ID, "", interest, property.ID)
+	result.emit("property_unmortgaged", map[string]interface{}{
+		"playerId":   player.ID,
+		"propertyId": property.ID,
+		"amount":     principal + interest,
+		"principal":  principal,
+		"interest":   interest,
+		"balance":    player.Balance,
+	})
+	return nil
+}
@@ -0,0 +1,76 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+func TestMortgageRecordsTransactions(t *testing.T) {
+	game := newRentGame("prop_colmer_corner")
+	game.Players[0].Properties = []string{"prop_colmer_corner"}
+	engine := newTestEngine(1, 2)
+	payload := propertyPayload("prop_colmer_corner")
+
+	result, err := engine.Apply(game, action(models.ActionTypeMortgageProperty, "p1", payload))
+	require.NoError(t, err)
+	require.Len(t, result.Transactions, 1)
+	assert.Equal(t, models.TransactionTypeMortgage, result.Transactions[0].Type)
+	assert.Equal(t, "p1", result.Transactions[0].ToPlayerID)
+	assert.Equal(t, 30, result.Transactions[0].Amount)
+	assert.Equal(t, 1530, game.Players[0].Balance)
+
+	result, err = engine.Apply(game, action(models.ActionTypeUnmortgageProperty, "p1", payload))
+	require.NoError(t, err)
+	require.Len(t, result.Transactions, 2)
+	assert.Equal(t, models.TransactionTypeUnmortgage, result.Transactions[0].Type)
+	assert.Equal(t, 30, result.Transactions[0].Amount)
+	assert.Equal(t, models.TransactionTypeMortgageInterest, result.Transactions[1].Type)
+	assert.Equal(t, 3, result.Transactions[1].Amount)
+	assert.Equal(t, "p1", result.Transactions[1].FromPlayerID)
+	assert.Equal(t, 1497, game.Players[0].Balance)
+}
+
+func TestUnmortgageRequiresPrincipalAndInterest(t *testing.T) {
+	game := newRentGame("prop_colmer_corner")
+	game.Players[0].Properties = []string{"prop_colmer_corner"}
+	findProperty(game, "prop_colmer_corner").Mortgaged = true
+	game.Players[0].Balance = 32
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeUnmortgageProperty, "p1", propertyPayload("prop_colmer_corner")))
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	assert.True(t, findProperty(game, "prop_colmer_corner").Mortgaged)
+	assert.Equal(t, 32, game.Players[0].Balance)
+}
+
+func TestMortgageInterestRoundsUp(t *testing.T) {
+	assert.Equal(t, 3, mortgageInterest(&models.Property{MortgageValue: 30}))
+	assert.Equal(t, 4, mortgageInterest(&models.Property{MortgageValue: 35}))
+	assert.Equal(t, 10, mortgageInterest(&models.Property{Price: 200}))
+}
+
+func TestTransferMortgagedPropertyChargesInterest(t *testing.T) {
+	game := newRentGame("prop_colmer_corner", "prop_wojak_street")
+	from, to := &game.Players[0], &game.Players[1]
+	from.Properties = []string{"prop_colmer_corner", "prop_wojak_street"}
+	colmer := findProperty(game, "prop_colmer_corner")
+	colmer.Mortgaged = true
+	result := newResult(game, action(models.ActionTypeTrade, "p1", nil))
+
+	assert.Equal(t, 3, transferCost(colmer))
+	assert.Equal(t, 0, transferCost(findProperty(game, "prop_wojak_street")))
+
+	transferProperty(colmer, from, to, result)
+	assert.Equal(t, "p2", colmer.OwnerID)
+	assert.True(t, colmer.Mortgaged)
+	assert.Equal(t, []string{"prop_wojak_street"}, from.Properties)
+	assert.Equal(t, []string{"prop_colmer_corner"}, to.Properties)
+	assert.Equal(t, 1497, to.Balance)
+	require.Len(t, result.Transactions, 1)
+	assert.Equal(t, models.TransactionTypeMortgageInterest, result.Transactions[0].Type)
+	assert.Equal(t, "p2", result.Transactions[0].FromPlayerID)
+}
@@ -0,0 +1,55 @@
+package rules
+
+// payloadMap returns the action payload as a map, or an empty map if it has another shape
+func payloadMap(payload interface{}) map[string]interface{} {
+	if m, ok := payload.(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{}
+}
+
+// payloadString reads a string field from an action payload
+func payloadString(payload interface{}, key string) string {
+	if s, ok := payloadMap(payload)[key].(string); ok {
+		return s
+	}
+	return ""
+}
+
+// payloadInt reads a numeric field from an action payload.
+// JSON numbers decode as float64, so both float64 and int are accepted.
+func payloadInt(payload interface{}, key string) (int, bool) {
+	switch v := payloadMap(payload)[key].(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+// payloadBool reads a boolean field from an action payload
+func payloadBool(payload interface{}, key string) bool {
+	b, _ := payloadMap(payload)[key].(bool)
+	return b
+}
+
+// payloadStrings reads a list of strings from an action payload. JSON arrays decode
+// as []interface{}, so both that and []string are accepted; other values are skipped.
+func payloadStrings(payload interface{}, key string) []string {
+	switch v := payloadMap(payload)[key].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// Effects held in Property.SpecialEffects. They match the effect types known to the frontend.
+const (
+	PropertyEffectStream     = "STREAM"      // Rent is raised by half while the owner streams from the property
+	PropertyEffectHost       = "HOST"        // The player who applied the effect collects the property's rent
+	PropertyEffectViralTrend = "VIRAL_TREND" // Rent is raised by viralTrendRent
+	PropertyEffectImmunity   = "IMMUNITY"    // Other players' cards cannot target the property
+	PropertyEffectDoubleRent = "DOUBLE_RENT" // Rent is doubled
+	PropertyEffectHalfRent   = "HALF_RENT"   // Rent is halved
+)
+
+// viralTrendRent is added to the rent of a trending property
+const viralTrendRent = 10
+
+// specialAction is a property effect a player can start with a SPECIAL action
+type specialAction struct {
+	pricePercent int  // Cost as a share of the property's price
+	rounds       int  // How many rounds the effect lasts
+	ownProperty  bool // Started on the player's own property; otherwise on another player's
+	payOwner     bool // Paid to the property's owner; otherwise to the bank
+}
+
+// specialActions are the property effects that can be bought with a SPECIAL action
+var specialActions = map[string]specialAction{
+	PropertyEffectStream:     {pricePercent: 25, rounds: 2, ownProperty: true},
+	PropertyEffectViralTrend: {pricePercent: 10, rounds: 2, ownProperty: true},
+	PropertyEffectDoubleRent: {pricePercent: 75, rounds: 1, ownProperty: true},
+	PropertyEffectImmunity:   {pricePercent: 50, rounds: 3, ownProperty: true},
+	PropertyEffectHost:       {pricePercent: 50, rounds: 2, payOwner: true},
+	PropertyEffectHalfRent:   {pricePercent: 40, rounds: 2},
+}
+
+// hasPropertyEffect reports whether an effect is active on a property
+func hasPropertyEffect(property *models.Property, effect string) bool {
+	return propertyEffectIndex(property, effect) >= 0
+}
+
+// propertyEffectIndex returns the index of an effect in the property's special effects, or -1
+func propertyEffectIndex(property *models.Property, effect string) int {
+	for i, special := range property.SpecialEffects {
+		if special.Type == effect {
+			return i
+		}
+	}
+	return -1
+}
+
+// addPropertyEffect puts an effect on a property for a number of rounds. Zero rounds
+// keeps the effect until it is removed. Reapplying an active effect keeps whichever
+// lasts longer and credits the player who applied it last.
+func addPropertyEffect(property *models.Property, effect, appliedBy string, rounds int, result *Result) {
+	if i := propertyEffectIndex(property, effect); i >= 0 {
+		special := &property.SpecialEffects[i]
+		if rounds == 0 || (special.ExpiresAfterTurns != 0 && rounds > special.ExpiresAfterTurns) {
+			special.ExpiresAfterTurns = rounds
+		}
+		special.AppliedBy = appliedBy
+	} else {
+		property.SpecialEffects = append(property.SpecialEffects, models.SpecialEffect{
+			Type:              effect,
+			AppliedBy:         appliedBy,
+			ExpiresAfterTurns: rounds,
+		})
+	}
+
+	result.emit("property_effect_added", map[string]interface{}{
+		"propertyId":      property.ID,
+		"effect":          effect,
+		"appliedBy":       appliedBy,
+		"remainingRounds": rounds,
+	})
+}
+
+// removePropertyEffect clears an effect from a property
+func removePropertyEffect(property *models.Property, effect string, result *Result) {
+	i := propertyEffectIndex(property, effect)
+	if i < 0 {
+		return
+	}
+	property.SpecialEffects = append(property.SpecialEffects[:i], property.SpecialEffects[i+1:]...)
+
+	result.emit("property_effect_expired", map[string]interface{}{
+		"propertyId": property.ID,
+		"effect":     effect,
+	})
+}
+
+// countDownPropertyEffects runs at the start of each round, using up a round of every
+// timed property effect and removing those that have run out
+func countDownPropertyEffects(game *models.Game, result *Result) {
+	for i := range game.BoardState.Properties {
+		property := &game.BoardState.Properties[i]
+		var expired []string
+		for j := range property.SpecialEffects {
+			special := &property.SpecialEffects[j]
+			if special.ExpiresAfterTurns == 0 {
+				continue
+			}
+			special.ExpiresAfterTurns--
+			if special.ExpiresAfterTurns == 0 {
+				expired = append(expired, special.Type)
+			}
+		}
+		for _, effect := range expired {
+			removePropertyEffect(property, effect, result)
+		}
+	}
+}
+
+// applyPropertyEffects adjusts a property's rent for the effects active on it
+func applyPropertyEffects(property *models.Property, rent int) int {
+	if hasPropertyEffect(property, PropertyEffectStream) {
+		rent += rent / 2
+	}
+	if hasPropertyEffect(property, PropertyEffectViralTrend) {
+		rent += viralTrendRent
+	}
+	if hasPropertyEffect(property, PropertyEffectDoubleRent) {
+		rent *= 2
+	}
+	if hasPropertyEffect(property, PropertyEffectHalfRent) {
+		rent /= 2
+	}
+	return rent
+}
+
+// propertyHost returns the ID of the player hosting on a property, or "" when nobody is
+func propertyHost(game *models.Game, property *models.Property) string {
+	i := propertyEffectIndex(property, PropertyEffectHost)
+	if i < 0 {
+		return ""
+	}
+	host := findPlayer(game, property.SpecialEffects[i].AppliedBy)
+	if host == nil || !isInGame(host) {
+		return ""
+	}
+	return host.ID
+}
+
+// isPropertyImmune reports whether a property is protected from other players' cards,
+// either by its own immunity or by its owner's
+func isPropertyImmune(game *models.Game, property *models.Property) bool {
+	return hasPropertyEffect(property, PropertyEffectImmunity) || isImmune(game, property.OwnerID)
+}
+
+// useSpecial buys a property effect for the current player. Streams, viral trends,
+// double rent and immunity are bought from the bank for the player's own property.
+// Hosting on another player's property is paid to its owner; halving another player's
+// rent is paid to the bank.
+func (e *Engine) useSpecial(game *models.Game, player *models.Player, payload interface{}, result *Result) error {
+	if err := requireTurn(game, player); err != nil {
+		return err
+	}
+	effect := payloadString(payload, "effect")
+	action, ok := specialActions[effect]
+	if !ok {
+		return fmt.Errorf("%w: unknown special effect %q", ErrInvalidPayload, effect)
+	}
+
+	propertyID := payloadString(payload, "propertyId")
+	if propertyID == "" {
+		return fmt.Errorf("%w: propertyId is required", ErrInvalidPayload)
+	}
+	property := findProperty(game, propertyID)
+	if property == nil {
+		return ErrPropertyNotFound
+	}
+
+	var owner *models.Player
+	if action.ownProperty {
+		if property.OwnerID != player.ID {
+			return ErrNotPropertyOwner
+		}
+	} else {
+		owner = findPlayer(game, property.OwnerID)
+		if owner == nil || owner.ID == player.ID || !isInGame(owner) {
+			return ErrInvalidTarget
+		}
+		if isPropertyImmune(game, property) {
+			return ErrPropertyImmune
+		}
+	}
+	if property.Mortgaged {
+		return ErrPropertyMortgaged
+	}
+	if hasPropertyEffect(property, effect) {
+		return ErrEffectActive
+	}
+
+	cost := property.Price * action.pricePercent / 100
+	if player.Balance < cost {
+		return ErrInsufficientFunds
+	}
+
+	player.Balance -= cost
+	toPlayerID := ""
+	if action.payOwner {
+		owner.Balance += cost
+		toPlayerID = owner.ID
+	}
+	result.record(models.TransactionTypeSpecialEffect, player.ID, toPlayerID, cost, property.ID)
+	addPropertyEffect(property, effect, player.ID, action.rounds, result)
+
+	result.emit("special_used", map[string]interface{}{
+		"playerId":   player.ID,
+		"propertyId": property.ID,
+		"ownerId":    property.OwnerID,
+		"effect":     effect,
+		"cost":       cost,
+		"balance":    player.Balance,
+	})
+	return nil
+}
@@ -0,0 +1,193 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+func specialPayload(effect, propertyID string) map[string]interface{} {
+	return map[string]interface{}{"effect": effect, "propertyId": propertyID}
+}
+
+func TestPropertyEffectsChangeRent(t *testing.T) {
+	tests := []struct {
+		effect string
+		want   int
+	}{
+		{PropertyEffectStream, 15},
+		{PropertyEffectViralTrend, 20},
+		{PropertyEffectDoubleRent, 20},
+		{PropertyEffectHalfRent, 5},
+		{PropertyEffectImmunity, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.effect, func(t *testing.T) {
+			game := newTestGame()
+			property := &game.BoardState.Properties[0]
+			property.OwnerID = "p2"
+			addPropertyEffect(property, tt.effect, "p2", 1, newResult(game, action(models.ActionTypeSpecial, "p2", nil)))
+
+			assert.Equal(t, tt.want, CalculateRent(game, property))
+		})
+	}
+}
+
+func TestPropertyEffectsExpireAtRoundStart(t *testing.T) {
+	game := newTestGame()
+	game.TurnState.HasRolled = true
+	property := &game.BoardState.Properties[0]
+	property.OwnerID = "p1"
+	property.SpecialEffects = []models.SpecialEffect{
+		{Type: PropertyEffectDoubleRent, AppliedBy: "p1", ExpiresAfterTurns: 1},
+		{Type: PropertyEffectHalfRent, AppliedBy: "p2", ExpiresAfterTurns: 2},
+		{Type: PropertyEffectImmunity, AppliedBy: "p1"},
+	}
+	engine := newTestEngine()
+	engine.SetMarketSettings(MarketSettings{})
+
+	// p1 -> p2 stays in the round
+	_, err := engine.Apply(game, action(models.ActionTypeEndTurn, "p1", nil))
+	require.NoError(t, err)
+	assert.Len(t, property.SpecialEffects, 3)
+
+	// p2 -> p1 starts a new round
+	game.TurnState.HasRolled = true
+	result, err := engine.Apply(game, action(models.ActionTypeEndTurn, "p2", nil))
+	require.NoError(t, err)
+	assert.Contains(t, eventTypes(result), "property_effect_expired")
+	require.Len(t, property.SpecialEffects, 2)
+	assert.Equal(t, PropertyEffectHalfRent, property.SpecialEffects[0].Type)
+	assert.Equal(t, 1, property.SpecialEffects[0].ExpiresAfterTurns)
+	assert.Equal(t, PropertyEffectImmunity, property.SpecialEffects[1].Type)
+	assert.Equal(t, 5, property.RentCurrent)
+}
+
+func TestSpecialStreamIsBoughtFromTheBank(t *testing.T) {
+	game := newTestGame()
+	game.BoardState.Properties[0].OwnerID = "p1"
+	engine := newTestEngine()
+
+	_, err := engine.Apply(game, action(models.ActionTypeSpecial, "p2", specialPayload(PropertyEffectStream, "prop-4")))
+	assert.ErrorIs(t, err, ErrNotYourTurn)
+	_, err = engine.Apply(game, action(models.ActionTypeSpecial, "p1", specialPayload(PropertyEffectStream, "prop-7")))
+	assert.ErrorIs(t, err, ErrNotPropertyOwner)
+	_, err = engine.Apply(game, action(models.ActionTypeSpecial, "p1", specialPayload("TELEPORT", "prop-4")))
+	assert.ErrorIs(t, err, ErrInvalidPayload)
+
+	result, err := engine.Apply(game, action(models.ActionTypeSpecial, "p1", specialPayload(PropertyEffectStream, "prop-4")))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"property_effect_added", "special_used"}, eventTypes(result))
+	assert.Equal(t, 1475, game.Players[0].Balance)
+	assert.Equal(t, 15, game.BoardState.Properties[0].RentCurrent)
+	require.Len(t, result.Transactions, 1)
+	assert.Equal(t, models.TransactionTypeSpecialEffect, result.Transactions[0].Type)
+	assert.Equal(t, 25, result.Transactions[0].Amount)
+
+	_, err = engine.Apply(game, action(models.ActionTypeSpecial, "p1", specialPayload(PropertyEffectStream, "prop-4")))
+	assert.ErrorIs(t, err, ErrEffectActive)
+}
+
+func TestSpecialRentEffectsLastTheirRounds(t *testing.T) {
+	tests := []struct {
+		effect string
+		owner  string
+		cost   int
+		rent   int
+		rounds int
+	}{
+		{PropertyEffectViralTrend, "p1", 10, 20, 2},
+		{PropertyEffectDoubleRent, "p1", 75, 20, 1},
+		{PropertyEffectHalfRent, "p2", 40, 5, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.effect, func(t *testing.T) {
+			game := newTestGame()
+			property := &game.BoardState.Properties[0]
+			property.OwnerID = tt.owner
+			engine := newTestEngine()
+			engine.SetMarketSettings(MarketSettings{})
+
+			result, err := engine.Apply(game, action(models.ActionTypeSpecial, "p1", specialPayload(tt.effect, "prop-4")))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"property_effect_added", "special_used"}, eventTypes(result))
+			assert.Equal(t, 1500-tt.cost, game.Players[0].Balance)
+			assert.Equal(t, 1500, game.Players[1].Balance, "paid to the bank")
+			require.Len(t, result.Transactions, 1)
+			assert.Empty(t, result.Transactions[0].ToPlayerID)
+			assert.Equal(t, tt.rent, property.RentCurrent)
+
+			for round := 1; round <= tt.rounds; round++ {
+				require.Len(t, property.SpecialEffects, 1, "round %d", round)
+				game.TurnState.HasRolled = true
+				_, err = engine.Apply(game, action(models.ActionTypeEndTurn, "p1", nil))
+				require.NoError(t, err)
+				game.TurnState.HasRolled = true
+				result, err = engine.Apply(game, action(models.ActionTypeEndTurn, "p2", nil))
+				require.NoError(t, err)
+			}
+			assert.Contains(t, eventTypes(result), "property_effect_expired")
+			assert.Empty(t, property.SpecialEffects)
+			assert.Equal(t, 10, property.RentCurrent)
+		})
+	}
+}
+
+func TestHalfRentNeedsAnotherPlayersProperty(t *testing.T) {
+	game := newTestGame()
+	game.BoardState.Properties[0].OwnerID = "p1"
+	engine := newTestEngine()
+
+	_, err := engine.Apply(game, action(models.ActionTypeSpecial, "p1", specialPayload(PropertyEffectHalfRent, "prop-4")))
+	assert.ErrorIs(t, err, ErrInvalidTarget)
+	_, err = engine.Apply(game, action(models.ActionTypeSpecial, "p1", specialPayload(PropertyEffectDoubleRent, "prop-7")))
+	assert.ErrorIs(t, err, ErrNotPropertyOwner)
+}
+
+func TestHostCollectsRent(t *testing.T) {
+	game := newTestGame()
+	game.BoardState.Properties[0].OwnerID = "p2"
+	game.TurnOrder = []string{"p1", "p2", "p3"}
+	game.Players = append(game.Players, models.Player{ID: "p3", Position: testBoard.StartPosition(), Balance: 1500, Status: models.PlayerStatusActive})
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeSpecial, "p1", specialPayload(PropertyEffectHost, "prop-4")))
+	require.NoError(t, err)
+	assert.Equal(t, 1450, game.Players[0].Balance)
+	assert.Equal(t, 1550, game.Players[1].Balance)
+
+	// The host lands on the property they host and owes nothing
+	_, err = engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Nil(t, game.TurnState.PendingRent)
+
+	// Anyone else pays the host
+	game.CurrentTurn = "p3"
+	game.TurnState = models.TurnState{}
+	engine = newTestEngine(1, 2)
+	_, err = engine.Apply(game, action(models.ActionTypeRollDice, "p3", nil))
+	require.NoError(t, err)
+	require.NotNil(t, game.TurnState.PendingRent)
+	assert.Equal(t, "p1", game.TurnState.PendingRent.CreditorID)
+}
+
+func TestImmunePropertyCannotBeHostedOrTaken(t *testing.T) {
+	game := newCardGame(models.CardTypeRedpill, "redpill_05")
+	property := &game.BoardState.Properties[0]
+	property.OwnerID = "p2"
+	game.Players[1].Properties = []string{"prop-4"}
+	property.SpecialEffects = []models.SpecialEffect{{Type: PropertyEffectImmunity, AppliedBy: "p2", ExpiresAfterTurns: 3}}
+	engine := newTestEngine()
+
+	_, err := engine.Apply(game, action(models.ActionTypeSpecial, "p1", specialPayload(PropertyEffectHost, "prop-4")))
+	assert.ErrorIs(t, err, ErrPropertyImmune)
+
+	_, err = engine.Apply(game, action(models.ActionTypeDrawCard, "p1", nil))
+	require.NoError(t, err)
+	_, err = engine.Apply(game, action(models.ActionTypeUseCard, "p1", propertyPayload("prop-4")))
+	assert.ErrorIs(t, err, ErrPropertyImmune)
+	assert.Equal(t, "p2", property.OwnerID)
+}
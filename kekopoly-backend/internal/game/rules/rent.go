@@ -0,0 +1,147 @@
+package rules
+
+import (
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+const (
+	// maxEngagements is the number of engagements a property can hold before it can
+	// be upgraded to a blue checkmark
+	maxEngagements = 4
+	// defaultUtilityDice is used for utility rent before anyone has rolled
+	defaultUtilityDice = 7
+)
+
+// Rent multipliers of the base rent for properties whose board pack has no rent table.
+// They match the multipliers used by the frontend.
+var (
+	engagementRentMultipliers = [maxEngagements + 1]int{1, 5, 15, 30, 45}
+	checkmarkRentMultiplier   = 70
+	transitRents              = []int{25, 50, 100, 200}
+)
+
+// CalculateRent returns the rent a player owes for landing on a property in the
+// game's current state. Mortgaged properties earn nothing.
+func CalculateRent(game *models.Game, property *models.Property) int {
+	if property.Mortgaged {
+		return 0
+	}
+	if property.OwnerID == "" {
+		return property.RentBase
+	}
+
+	var rent int
+	switch property.Type {
+	case models.PropertyTypeRegular:
+		rent = regularRent(game, property)
+	case models.PropertyTypeTransit:
+		rent = transitRent(game, property)
+	case models.PropertyTypeUtility:
+		rent = utilityRent(game, property)
+	default:
+		rent = property.RentBase
+	}
+
+	if owner := findPlayer(game, property.OwnerID); owner != nil && hasStatus(owner, StatusDoubleRent) {
+		rent *= 2
+	}
+	rent = applyPropertyEffects(property, rent)
+	return applyMarketCondition(game.MarketCondition, rent)
+}
+
+// RefreshRents recalculates RentCurrent for every property so clients can display it
+func RefreshRents(game *models.Game) {
+	for i := range game.BoardState.Properties {
+		property := &game.BoardState.Properties[i]
+		property.RentCurrent = CalculateRent(game, property)
+	}
+}
+
+// regularRent uses the rent tier for the property's development. Owning the whole
+// group doubles the rent of undeveloped properties.
+func regularRent(game *models.Game, property *models.Property) int {
+	table := property.RentTable
+
+	if property.BlueCheckmark {
+		if len(table) > maxEngagements+1 {
+			return table[maxEngagements+1]
+		}
+		return property.RentBase * checkmarkRentMultiplier
+	}
+
+	if property.Engagements > 0 {
+		level := property.Engagements
+		if level > maxEngagements {
+			level = maxEngagements
+		}
+		if level < len(table) {
+			return table[level]
+		}
+		return property.RentBase * engagementRentMultipliers[level]
+	}
+
+	rent := property.RentBase
+	if ownsGroup(game, property.OwnerID, property.Group) {
+		rent *= 2
+	}
+	return rent
+}
+
+// transitRent scales with the number of transit properties the owner holds
+func transitRent(game *models.Game, property *models.Property) int {
+	owned := countOwnedOfType(game, property.OwnerID, models.PropertyTypeTransit)
+
+	table := property.RentTable
+	if len(table) == 0 {
+		table = transitRents
+	}
+	if owned > len(table) {
+		owned = len(table)
+	}
+	return table[owned-1]
+}
+
+// utilityRent is the dice total of the last roll times 4, or times 10 when the
+// owner holds two or more utilities
+func utilityRent(game *models.Game, property *models.Property) int {
+	dice := defaultUtilityDice
+	if game.LastRoll != nil {
+		dice = game.LastRoll.Total
+	}
+
+	if countOwnedOfType(game, property.OwnerID, models.PropertyTypeUtility) >= 2 {
+		return dice * 10
+	}
+	return dice * 4
+}
+
+// ownsGroup reports whether a player owns every property in a group
+func ownsGroup(game *models.Game, playerID, group string) bool {
+	if group == "" {
+		return false
+	}
+	found := false
+	for i := range game.BoardState.Properties {
+		property := &game.BoardState.Properties[i]
+		if property.Group != group {
+			continue
+		}
+		if property.OwnerID != playerID {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// countOwnedOfType counts the properties of a type held by a player
+func countOwnedOfType(game *models.Game, playerID string, propertyType models.PropertyType) int {
+	count := 0
+	for i := range game.BoardState.Properties {
+		property := &game.BoardState.Properties[i]
+		if property.Type == propertyType && property.OwnerID == playerID {
+			count++
+		}
+	}
+	return count
+}
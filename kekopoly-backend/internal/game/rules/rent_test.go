@@ -0,0 +1,102 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// newRentGame returns a game on the default board where p1 owns the properties given
+func newRentGame(owned ...string) *models.Game {
+	game := newTestGame()
+	game.BoardState.Properties = testBoard.NewProperties()
+	for _, id := range owned {
+		findProperty(game, id).OwnerID = "p1"
+	}
+	return game
+}
+
+func TestCalculateRentRegular(t *testing.T) {
+	game := newRentGame("prop_colmer_corner")
+	colmer := findProperty(game, "prop_colmer_corner")
+
+	assert.Equal(t, 2, CalculateRent(game, colmer))
+
+	// Owning the whole group doubles undeveloped rent
+	findProperty(game, "prop_wojak_street").OwnerID = "p1"
+	assert.Equal(t, 4, CalculateRent(game, colmer))
+
+	colmer.Engagements = 3
+	assert.Equal(t, 90, CalculateRent(game, colmer))
+
+	colmer.Engagements = 0
+	colmer.BlueCheckmark = true
+	assert.Equal(t, 250, CalculateRent(game, colmer))
+}
+
+func TestCalculateRentWithoutRentTable(t *testing.T) {
+	game := newTestGame()
+	property := &game.BoardState.Properties[0]
+	property.OwnerID = "p1"
+
+	property.Engagements = 2
+	assert.Equal(t, 150, CalculateRent(game, property))
+
+	property.Engagements = 0
+	property.BlueCheckmark = true
+	assert.Equal(t, 700, CalculateRent(game, property))
+}
+
+func TestCalculateRentTransit(t *testing.T) {
+	game := newRentGame("prop_rage_train")
+	rage := findProperty(game, "prop_rage_train")
+
+	assert.Equal(t, 25, CalculateRent(game, rage))
+
+	findProperty(game, "prop_pepe_train").OwnerID = "p1"
+	assert.Equal(t, 50, CalculateRent(game, rage))
+}
+
+func TestCalculateRentUtility(t *testing.T) {
+	game := newTestGame()
+	game.BoardState.Properties = []models.Property{
+		{ID: "u1", Type: models.PropertyTypeUtility, OwnerID: "p1", Price: 150},
+		{ID: "u2", Type: models.PropertyTypeUtility, Price: 150},
+	}
+	u1 := findProperty(game, "u1")
+
+	assert.Equal(t, 28, CalculateRent(game, u1))
+
+	game.LastRoll = &models.DiceRoll{Dice1: 3, Dice2: 2, Total: 5}
+	assert.Equal(t, 20, CalculateRent(game, u1))
+
+	findProperty(game, "u2").OwnerID = "p1"
+	assert.Equal(t, 50, CalculateRent(game, u1))
+}
+
+func TestCalculateRentMortgagedAndMarket(t *testing.T) {
+	game := newRentGame("prop_galaxy_brain")
+	galaxy := findProperty(game, "prop_galaxy_brain")
+
+	game.MarketCondition = models.MarketConditionBull
+	assert.Equal(t, 28, CalculateRent(game, galaxy))
+
+	game.MarketCondition = models.MarketConditionCrash
+	assert.Equal(t, 23, CalculateRent(game, galaxy))
+
+	galaxy.Mortgaged = true
+	assert.Equal(t, 0, CalculateRent(game, galaxy))
+}
+
+func TestApplyRefreshesRentCurrent(t *testing.T) {
+	game := newRentGame("prop_colmer_corner")
+	game.Players[0].Properties = []string{"prop_colmer_corner"}
+	engine := newTestEngine(1, 2)
+
+	_, err := engine.Apply(game, action(models.ActionTypeMortgageProperty, "p1", map[string]interface{}{"propertyId": "prop_colmer_corner"}))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, findProperty(game, "prop_colmer_corner").RentCurrent)
+	assert.Equal(t, 4, findProperty(game, "prop_wojak_street").RentCurrent)
+}
@@ -0,0 +1,94 @@
+package rules
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// Result describes everything an accepted action changed
+type Result struct {
+	Action       models.ActionType    `json:"action"`
+	PlayerID     string               `json:"playerId"`
+	ByBot        bool                 `json:"byBot,omitempty"` // The action was made by a bot on the player's behalf
+	Events       []Event              `json:"events"`
+	Transactions []models.Transaction `json:"transactions,omitempty"`
+	Trades       []models.Trade       `json:"trades,omitempty"` // Trades created or resolved by the action, to be saved
+
+	gameID    string
+	timestamp time.Time
+}
+
+// Event is a state change that should be broadcast to every client in the game,
+// or only to its recipient when one is set
+type Event struct {
+	Type      string                 `json:"type"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Recipient string                 `json:"recipient,omitempty"` // Player ID of a private event
+}
+
+// Message flattens the event into the WebSocket message format used by the hub
+func (e Event) Message(gameID string, timestamp time.Time) map[string]interface{} {
+	msg := make(map[string]interface{}, len(e.Data)+3)
+	for k, v := range e.Data {
+		msg[k] = v
+	}
+	msg["type"] = e.Type
+	msg["gameId"] = gameID
+	msg["timestamp"] = timestamp.Format(time.RFC3339)
+	return msg
+}
+
+// newResult creates an empty result for an action
+func newResult(game *models.Game, action models.GameAction) *Result {
+	timestamp := action.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	return &Result{
+		Action:    action.Type,
+		PlayerID:  action.PlayerID,
+		ByBot:     action.ByBot,
+		Events:    []Event{},
+		gameID:    game.ID.Hex(),
+		timestamp: timestamp,
+	}
+}
+
+// emit appends an event to the result
+func (r *Result) emit(eventType string, data map[string]interface{}) {
+	r.Events = append(r.Events, Event{Type: eventType, Data: data})
+}
+
+// emitTo appends an event that only one player may see
+func (r *Result) emitTo(playerID, eventType string, data map[string]interface{}) {
+	r.Events = append(r.Events, Event{Type: eventType, Data: data, Recipient: playerID})
+}
+
+// record appends a transaction to the result, filling in the bookkeeping fields
+func (r *Result) record(txType models.TransactionType, fromPlayerID, toPlayerID string, amount int, propertyID string) {
+	r.Transactions = append(r.Transactions, models.Transaction{
+		ID:            uuid.New().String(),
+		GameID:        r.gameID,
+		Type:          txType,
+		FromPlayerID:  fromPlayerID,
+		ToPlayerID:    toPlayerID,
+		Amount:        amount,
+		PropertyID:    propertyID,
+		Timestamp:     r.timestamp,
+		OnChainStatus: models.OnChainStatusPending,
+	})
+}
+
+// recordCard appends a transaction caused by a card
+func (r *Result) recordCard(fromPlayerID, toPlayerID string, amount int, propertyID, cardID string) {
+	r.record(models.TransactionTypeCardEffect, fromPlayerID, toPlayerID, amount, propertyID)
+	r.Transactions[len(r.Transactions)-1].CardID = cardID
+}
+
+// saveTrade adds a copy of a trade to the result so its latest state gets saved
+func (r *Result) saveTrade(trade models.Trade) {
+	r.Trades = append(r.Trades, trade)
+}
@@ -0,0 +1,89 @@
+package rules
+
+import (
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+const (
+	// DefaultShadowbanTurns is how many of their own turns a shadowbanned player sits out of the economy
+	DefaultShadowbanTurns = 3
+
+	// SpaceEffectShadowban is the effect of a SPECIAL space that shadowbans whoever lands on it
+	SpaceEffectShadowban = "shadowban"
+	// SpaceEffectSkipTurn is the effect of a SPECIAL space whose visitor sits out their next turn
+	SpaceEffectSkipTurn = "skip_turn"
+)
+
+// Sources of a shadowban, reported in the player_shadowbanned event
+const (
+	ShadowbanSourceSpace = "space"
+	ShadowbanSourceCard  = "card"
+)
+
+// Shadowban describes a player's active shadowban
+type Shadowban struct {
+	PlayerID       string `json:"playerId"`
+	RemainingTurns int    `json:"remainingTurns"`
+}
+
+// ActiveShadowbans lists the players who are currently shadowbanned
+func ActiveShadowbans(game *models.Game) []Shadowban {
+	bans := []Shadowban{}
+	for _, player := range game.Players {
+		if isShadowbanned(&player) {
+			bans = append(bans, Shadowban{PlayerID: player.ID, RemainingTurns: player.ShadowbanRemainingTurns})
+		}
+	}
+	return bans
+}
+
+// isShadowbanned reports whether a player is shadowbanned. A shadowbanned player
+// collects no rent and cannot trade.
+func isShadowbanned(player *models.Player) bool {
+	return player.ShadowbanRemainingTurns > 0
+}
+
+// requireNotShadowbanned rejects economic actions from shadowbanned players
+func requireNotShadowbanned(player *models.Player) error {
+	if isShadowbanned(player) {
+		return ErrShadowbanned
+	}
+	return nil
+}
+
+// shadowban bans a player for the given number of their turns. A player who is
+// already shadowbanned keeps whichever ban runs longer.
+func shadowban(player *models.Player, turns int, source string, result *Result) {
+	if turns > player.ShadowbanRemainingTurns {
+		player.ShadowbanRemainingTurns = turns
+	}
+	player.Shadowbanned = true
+
+	result.emit("player_shadowbanned", map[string]interface{}{
+		"playerId":       player.ID,
+		"remainingTurns": player.ShadowbanRemainingTurns,
+		"source":         source,
+	})
+}
+
+// liftShadowban clears a player's shadowban
+func liftShadowban(player *models.Player, result *Result) {
+	player.Shadowbanned = false
+	player.ShadowbanRemainingTurns = 0
+
+	result.emit("shadowban_lifted", map[string]interface{}{
+		"playerId": player.ID,
+	})
+}
+
+// countDownShadowban uses up one turn of a player's shadowban at the end of their
+// turn, lifting it when none remain
+func countDownShadowban(player *models.Player, result *Result) {
+	if !isShadowbanned(player) {
+		return
+	}
+	player.ShadowbanRemainingTurns--
+	if player.ShadowbanRemainingTurns == 0 {
+		liftShadowban(player, result)
+	}
+}
@@ -0,0 +1,172 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/board"
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rng"
+)
+
+const shadowbanBoardJSON = `{"id":"shadowban-test","version":1,"spaces":[
+	{"position":0,"name":"JAIL","type":"JAIL"},
+	{"position":1,"name":"START","type":"START"},
+	{"position":2,"name":"FREE","type":"FREE"},
+	{"position":3,"name":"FREE","type":"FREE"},
+	{"position":4,"name":"BANNED","type":"SPECIAL","effect":"shadowban"},
+	{"position":5,"name":"FREE","type":"FREE"}]}`
+
+func TestLandingOnShadowbanSpace(t *testing.T) {
+	b, err := board.Parse([]byte(shadowbanBoardJSON))
+	require.NoError(t, err)
+	game := newTestGame()
+	game.BoardState.Properties = nil
+	engine := NewEngine(b, rng.NewScripted(0, 1))
+
+	result, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dice_rolled", "player_shadowbanned"}, eventTypes(result))
+	assert.True(t, game.Players[0].Shadowbanned)
+	assert.Equal(t, DefaultShadowbanTurns, game.Players[0].ShadowbanRemainingTurns)
+	assert.Equal(t, []Shadowban{{PlayerID: "p1", RemainingTurns: DefaultShadowbanTurns}}, ActiveShadowbans(game))
+}
+
+func TestShadowbannedOwnerCollectsNoRent(t *testing.T) {
+	game := newTestGame()
+	game.BoardState.Properties[0].OwnerID = "p2"
+	game.Players[1].Shadowbanned = true
+	game.Players[1].ShadowbanRemainingTurns = 2
+	engine := newTestEngine(1, 2)
+
+	result, err := engine.Apply(game, action(models.ActionTypeRollDice, "p1", nil))
+	require.NoError(t, err)
+	assert.Nil(t, game.TurnState.PendingRent)
+	assert.Equal(t, []string{"dice_rolled", "rent_blocked"}, eventTypes(result))
+}
+
+func TestShadowbanCountsDownAtTurnEnd(t *testing.T) {
+	game := newTestGame()
+	player := &game.Players[0]
+	player.Shadowbanned = true
+	player.ShadowbanRemainingTurns = 2
+	engine := newTestEngine(1, 2)
+
+	endTurn := func(playerID string) *Result {
+		game.TurnState.HasRolled = true
+		result, err := engine.Apply(game, action(models.ActionTypeEndTurn, playerID, nil))
+		require.NoError(t, err)
+		return result
+	}
+
+	endTurn("p1")
+	assert.Equal(t, 1, player.ShadowbanRemainingTurns)
+	assert.True(t, player.Shadowbanned)
+
+	// Other players' turns do not count
+	endTurn("p2")
+	assert.Equal(t, 1, player.ShadowbanRemainingTurns)
+
+	result := endTurn("p1")
+	assert.Contains(t, eventTypes(result), "shadowban_lifted")
+	assert.False(t, player.Shadowbanned)
+	assert.Equal(t, 0, player.ShadowbanRemainingTurns)
+	assert.Empty(t, ActiveShadowbans(game))
+}
+
+func TestShadowbanKeepsLongerBan(t *testing.T) {
+	game := newTestGame()
+	player := &game.Players[0]
+	result := newResult(game, action(models.ActionTypeSpecial, "p1", nil))
+
+	shadowban(player, 5, ShadowbanSourceCard, result)
+	shadowban(player, 2, ShadowbanSourceSpace, result)
+	assert.Equal(t, 5, player.ShadowbanRemainingTurns)
+	assert.ErrorIs(t, requireNotShadowbanned(player), ErrShadowbanned)
+}
+
+func TestShadowbannedPlayerCannotTrade(t *testing.T) {
+	game := newTradeGame()
+	game.Players[0].ShadowbanRemainingTurns = 2
+	engine := newTestEngine()
+
+	_, err := engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2",
+		map[string]interface{}{"kekels": 100}, nil)))
+	assert.ErrorIs(t, err, ErrShadowbanned)
+	assert.Empty(t, game.Trades)
+}
+
+func TestTradeFromShadowbannedProposerCannotBeAccepted(t *testing.T) {
+	game := newTradeGame()
+	engine := newTestEngine()
+	_, err := engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2",
+		map[string]interface{}{"kekels": 100}, nil)))
+	require.NoError(t, err)
+	trade := game.Trades[0]
+
+	game.Players[0].ShadowbanRemainingTurns = 2
+	assert.ErrorIs(t, CanAcceptTrade(game, &trade), ErrShadowbanned)
+	_, err = engine.Apply(game, action(models.ActionTypeTrade, "p2", tradeResponse(trade.ID, TradeResponseAccept)))
+	assert.ErrorIs(t, err, ErrShadowbanned)
+	assert.Equal(t, 1500, game.Players[1].Balance)
+}
+
+func TestShadowbanBlocksCounterOffers(t *testing.T) {
+	game := newTradeGame()
+	engine := newTestEngine()
+	_, err := engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2",
+		map[string]interface{}{"kekels": 100}, map[string]interface{}{"properties": []interface{}{"prop-7"}})))
+	require.NoError(t, err)
+	original := game.Trades[0].ID
+
+	counter := tradeResponse(original, TradeResponseCounter)
+	counter["requested"] = map[string]interface{}{"kekels": 250}
+
+	// Neither a shadowbanned recipient nor one countering a shadowbanned proposer gets through
+	game.Players[1].ShadowbanRemainingTurns = 2
+	_, err = engine.Apply(game, action(models.ActionTypeTrade, "p2", counter))
+	assert.ErrorIs(t, err, ErrShadowbanned)
+	game.Players[1].ShadowbanRemainingTurns = 0
+	game.Players[0].ShadowbanRemainingTurns = 2
+	_, err = engine.Apply(game, action(models.ActionTypeTrade, "p2", counter))
+	assert.ErrorIs(t, err, ErrShadowbanned)
+
+	require.Len(t, game.Trades, 1)
+	assert.Equal(t, original, game.Trades[0].ID)
+	assert.Equal(t, models.TradeStatusPending, game.Trades[0].Status)
+}
+
+func TestShadowbannedPlayerCanStillCloseTrades(t *testing.T) {
+	game := newTradeGame()
+	engine := newTestEngine()
+	_, err := engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2",
+		map[string]interface{}{"kekels": 100}, nil)))
+	require.NoError(t, err)
+	_, err = engine.Apply(game, action(models.ActionTypeTrade, "p2", tradePayload("p1",
+		map[string]interface{}{"kekels": 50}, nil)))
+	require.NoError(t, err)
+	require.Len(t, game.Trades, 2)
+	proposed, received := game.Trades[0].ID, game.Trades[1].ID
+
+	game.Players[0].ShadowbanRemainingTurns = 2
+	result, err := engine.Apply(game, action(models.ActionTypeTrade, "p1", tradeResponse(proposed, TradeResponseCancel)))
+	require.NoError(t, err)
+	assert.Equal(t, models.TradeStatusCancelled, result.Trades[0].Status)
+	result, err = engine.Apply(game, action(models.ActionTypeTrade, "p1", tradeResponse(received, TradeResponseReject)))
+	require.NoError(t, err)
+	assert.Equal(t, models.TradeStatusRejected, result.Trades[0].Status)
+	assert.Empty(t, game.Trades)
+}
+
+func TestNoTradeIsOfferedToShadowbannedPlayer(t *testing.T) {
+	game := newTradeGame()
+	game.Players[1].ShadowbanRemainingTurns = 2
+	engine := newTestEngine()
+
+	_, err := engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2",
+		map[string]interface{}{"kekels": 100}, nil)))
+	assert.ErrorIs(t, err, ErrShadowbanned)
+	assert.Empty(t, game.Trades)
+}
@@ -0,0 +1,131 @@
+package rules
+
+import (
+	"sort"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// NetWorth is what a player is worth: their kekels, the price of their properties less
+// any mortgage, and what their buildings cost
+func NetWorth(game *models.Game, player *models.Player) int {
+	worth := player.Balance
+	for i := range game.BoardState.Properties {
+		property := &game.BoardState.Properties[i]
+		if property.OwnerID != player.ID {
+			continue
+		}
+		worth += property.Price + property.Engagements*engagementCost(property)
+		if property.Mortgaged {
+			worth -= mortgageValue(property)
+		}
+		if property.BlueCheckmark {
+			worth += checkmarkCost(property)
+		}
+	}
+	return worth
+}
+
+// updateNetWorths refreshes the net worth of every player still in the game
+func updateNetWorths(game *models.Game) {
+	for i := range game.Players {
+		if player := &game.Players[i]; isInGame(player) {
+			player.NetWorth = NetWorth(game, player)
+		}
+	}
+}
+
+// checkRoundLimit ends a round-capped game once its last round has been played
+func checkRoundLimit(game *models.Game, result *Result) bool {
+	if game.RoundLimit <= 0 || game.Round <= game.RoundLimit {
+		return false
+	}
+	completeGame(game, models.GameEndReasonRoundLimit, result)
+	return true
+}
+
+// completeGame ends the game, placing the players still in it by net worth ahead of
+// those who went bankrupt, and cancels anything left unfinished
+func completeGame(game *models.Game, reason models.GameEndReason, result *Result) {
+	updateNetWorths(game)
+	game.Standings = rankPlayers(game)
+	for _, standing := range game.Standings {
+		findPlayer(game, standing.PlayerID).Placement = standing.Placement
+	}
+	for i := len(game.Trades) - 1; i >= 0; i-- {
+		closeTrade(game, &game.Trades[i], models.TradeStatusCancelled, result)
+	}
+
+	completedAt := result.timestamp
+	game.Status = models.GameStatusCompleted
+	game.EndReason = reason
+	game.CompletedAt = &completedAt
+	game.CurrentTurn = ""
+	game.TurnState = models.TurnState{}
+	if len(game.Standings) > 0 {
+		game.WinnerID = game.Standings[0].PlayerID
+	}
+
+	result.emit("game_over", map[string]interface{}{
+		"winnerId":  game.WinnerID,
+		"reason":    reason,
+		"standings": game.Standings,
+	})
+}
+
+// rankPlayers orders players still in the game by net worth, ties going to the player
+// with more kekels, followed by the rest in the order of the placement they were
+// knocked out at
+func rankPlayers(game *models.Game) []models.Standing {
+	standings := make([]models.Standing, 0, len(game.Players))
+	for i := range game.Players {
+		player := &game.Players[i]
+		standing := models.Standing{
+			PlayerID:  player.ID,
+			Placement: player.Placement,
+			Status:    player.Status,
+			NetWorth:  player.NetWorth,
+			Balance:   player.Balance,
+		}
+		for j := range game.BoardState.Properties {
+			property := &game.BoardState.Properties[j]
+			if property.OwnerID != player.ID {
+				continue
+			}
+			standing.Properties++
+			standing.Engagements += property.Engagements
+			if property.Mortgaged {
+				standing.Mortgaged++
+			}
+			if property.BlueCheckmark {
+				standing.Checkmarks++
+			}
+		}
+		switch {
+		case isInGame(player):
+			standing.Placement = 0
+		case standing.Placement == 0:
+			// Left the game without being placed, so behind everyone who was
+			standing.Placement = len(game.Players) + 1
+		}
+		standings = append(standings, standing)
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		switch {
+		case (a.Placement == 0) != (b.Placement == 0):
+			return a.Placement == 0
+		case a.Placement != b.Placement:
+			return a.Placement < b.Placement
+		case a.NetWorth != b.NetWorth:
+			return a.NetWorth > b.NetWorth
+		default:
+			return a.Balance > b.Balance
+		}
+	})
+	for i := range standings {
+		standings[i].Placement = i + 1
+	}
+	return standings
+}
@@ -0,0 +1,89 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+func TestNetWorthCountsPropertiesAndBuildings(t *testing.T) {
+	game := newTestGame()
+	game.BoardState.Properties[0].OwnerID = "p1"
+	game.BoardState.Properties[0].Mortgaged = true
+	game.BoardState.Properties[1].OwnerID = "p1"
+	game.BoardState.Properties[1].Engagements = 2
+
+	property := &game.BoardState.Properties[1]
+	expected := 1500 + 100 - mortgageValue(&game.BoardState.Properties[0]) + 200 + 2*engagementCost(property)
+	assert.Equal(t, expected, NetWorth(game, &game.Players[0]))
+	assert.Equal(t, 1500, NetWorth(game, &game.Players[1]))
+}
+
+func TestRoundLimitEndsTheGameByNetWorth(t *testing.T) {
+	game := newTestGame()
+	game.Round = 1
+	game.RoundLimit = 1
+	game.Players[0].Balance = 1400
+	game.BoardState.Properties[1].OwnerID = "p1"
+	game.Players[0].Properties = []string{"prop-7"}
+	engine := newTestEngine()
+	engine.SetMarketSettings(MarketSettings{})
+
+	_, err := engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2", map[string]interface{}{"kekels": 10}, nil)))
+	require.NoError(t, err)
+
+	game.TurnState.HasRolled = true
+	_, err = engine.Apply(game, action(models.ActionTypeEndTurn, "p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, models.GameStatusActive, game.Status)
+
+	game.TurnState.HasRolled = true
+	result, err := engine.Apply(game, action(models.ActionTypeEndTurn, "p2", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"turn_ended", "trade_resolved", "game_over"}, eventTypes(result))
+	assert.Equal(t, models.GameStatusCompleted, game.Status)
+	assert.Equal(t, models.GameEndReasonRoundLimit, game.EndReason)
+	assert.Equal(t, "p1", game.WinnerID)
+	assert.Empty(t, game.Trades)
+
+	require.Len(t, game.Standings, 2)
+	assert.Equal(t, models.Standing{PlayerID: "p1", Placement: 1, Status: models.PlayerStatusActive, NetWorth: 1600, Balance: 1400, Properties: 1}, game.Standings[0])
+	assert.Equal(t, "p2", game.Standings[1].PlayerID)
+	assert.Equal(t, 2, game.Players[1].Placement)
+}
+
+func TestTimedGameEndsAtItsDeadline(t *testing.T) {
+	game := newAuctionGame()
+	endsAt := auctionStart.Add(10 * time.Second)
+	game.EndsAt = &endsAt
+	game.TimeWarningsSent = len(TimeWarnings)
+	game.Players[1].Status = models.PlayerStatusBankrupt
+	game.Players[1].Placement = 3
+	game.Players[2].Balance = 1600
+	engine := newTestEngine()
+
+	// The end of the game comes before the end of the auction
+	_, err := engine.Apply(game, timedAction(models.ActionTypeDeclineProperty, "p1", nil, 0))
+	require.NoError(t, err)
+	deadline, ok := NextDeadline(game)
+	require.True(t, ok)
+	assert.Equal(t, endsAt, deadline)
+
+	result, err := engine.Tick(game, endsAt)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"game_over"}, eventTypes(result))
+	assert.Equal(t, models.GameEndReasonTimeLimit, game.EndReason)
+	assert.Equal(t, endsAt, *game.CompletedAt)
+	assert.Nil(t, game.TurnState.Auction)
+
+	placements := make([]string, 0, len(game.Standings))
+	for _, standing := range game.Standings {
+		placements = append(placements, standing.PlayerID)
+	}
+	assert.Equal(t, []string{"p3", "p1", "p2"}, placements)
+	assert.Equal(t, "p3", game.WinnerID)
+}
@@ -0,0 +1,150 @@
+package rules
+
+import (
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// findPlayer returns a pointer to the player with the given ID, or nil if not present
+func findPlayer(game *models.Game, playerID string) *models.Player {
+	for i := range game.Players {
+		if game.Players[i].ID == playerID {
+			return &game.Players[i]
+		}
+	}
+	return nil
+}
+
+// findProperty returns a pointer to the property with the given ID, or nil if not present
+func findProperty(game *models.Game, propertyID string) *models.Property {
+	for i := range game.BoardState.Properties {
+		if game.BoardState.Properties[i].ID == propertyID {
+			return &game.BoardState.Properties[i]
+		}
+	}
+	return nil
+}
+
+// propertyAt returns the property occupying a board position, or nil for non-property spaces
+func propertyAt(game *models.Game, position int) *models.Property {
+	for i := range game.BoardState.Properties {
+		if game.BoardState.Properties[i].Position == position {
+			return &game.BoardState.Properties[i]
+		}
+	}
+	return nil
+}
+
+// isInGame reports whether a player can still take part in the game
+func isInGame(player *models.Player) bool {
+	return player.Status != models.PlayerStatusBankrupt && player.Status != models.PlayerStatusForfeited
+}
+
+// nextPlayerID returns the next player in turn order who is still in the game
+func nextPlayerID(game *models.Game, currentID string) string {
+	return seatedPlayerID(game, currentID, 1)
+}
+
+// previousPlayerID returns the previous player in turn order who is still in the game
+func previousPlayerID(game *models.Game, currentID string) string {
+	return seatedPlayerID(game, currentID, -1)
+}
+
+// seatedPlayerID walks the turn order from a player in the given direction and
+// returns the first player still in the game
+func seatedPlayerID(game *models.Game, currentID string, direction int) string {
+	if len(game.TurnOrder) == 0 {
+		return ""
+	}
+
+	start := -1
+	for i, id := range game.TurnOrder {
+		if id == currentID {
+			start = i
+			break
+		}
+	}
+
+	n := len(game.TurnOrder)
+	for offset := 1; offset <= n; offset++ {
+		candidateID := game.TurnOrder[((start+direction*offset)%n+n)%n]
+		if candidate := findPlayer(game, candidateID); candidate != nil && isInGame(candidate) {
+			return candidateID
+		}
+	}
+
+	return ""
+}
+
+// opponents returns the other players still in the game
+func opponents(game *models.Game, player *models.Player) []*models.Player {
+	var others []*models.Player
+	for i := range game.Players {
+		other := &game.Players[i]
+		if other.ID != player.ID && isInGame(other) {
+			others = append(others, other)
+		}
+	}
+	return others
+}
+
+// startsRound reports whether passing the turn from one player to the next wraps
+// around the turn order and so begins a new round
+func startsRound(game *models.Game, fromID, toID string) bool {
+	from, to := -1, -1
+	for i, id := range game.TurnOrder {
+		switch id {
+		case fromID:
+			from = i
+		case toID:
+			to = i
+		}
+	}
+	return from >= 0 && to >= 0 && to <= from
+}
+
+// addOwnedProperty records a property in the player's portfolio
+func addOwnedProperty(player *models.Player, propertyID string) {
+	for _, id := range player.Properties {
+		if id == propertyID {
+			return
+		}
+	}
+	player.Properties = append(player.Properties, propertyID)
+}
+
+// removeOwnedProperty drops a property from the player's portfolio
+func removeOwnedProperty(player *models.Player, propertyID string) {
+	for i, id := range player.Properties {
+		if id == propertyID {
+			player.Properties = append(player.Properties[:i], player.Properties[i+1:]...)
+			return
+		}
+	}
+}
+
+// hasBuildings reports whether a property has any engagements or a blue checkmark
+func hasBuildings(property *models.Property) bool {
+	return property.Engagements > 0 || property.BlueCheckmark
+}
+
+// mortgageValue is the amount the bank pays out when a property is mortgaged
+func mortgageValue(property *models.Property) int {
+	if property.MortgageValue > 0 {
+		return property.MortgageValue
+	}
+	return property.Price / 2
+}
+
+// engagementCost is the price of building one engagement on a property
+func engagementCost(property *models.Property) int {
+	if property.BuildCost > 0 {
+		return property.BuildCost
+	}
+	return property.Price * 60 / 100
+}
+
+// checkmarkCost is the price of upgrading a property to a blue checkmark. It is the
+// same build cost the board charges for an engagement.
+func checkmarkCost(property *models.Property) int {
+	return engagementCost(property)
+}
@@ -0,0 +1,109 @@
+package rules
+
+import (
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// Lasting card effects held in Player.StatusEffects
+const (
+	StatusPropertyImmunity  = "PROPERTY_IMMUNITY"   // The player's properties cannot be taken, mortgaged or stripped by others
+	StatusDoubleRentAgainst = "DOUBLE_RENT_AGAINST" // The player pays double rent
+	StatusDoubleRent        = "DOUBLE_RENT"         // The player's properties earn double rent
+	StatusCollectAllRent    = "COLLECT_ALL_RENT"    // Rent on every other player's property goes to the player
+	StatusFrozen            = "FROZEN"              // The player's token does not move when they roll
+	StatusMustBuyNext       = "MUST_BUY_NEXT"       // The player buys the next unowned property they land on
+	StatusSkipNextTurn      = "SKIP_NEXT_TURN"      // The player sits out their next turn
+)
+
+// hasStatus reports whether a lasting effect is active on a player
+func hasStatus(player *models.Player, effect string) bool {
+	return statusIndex(player, effect) >= 0
+}
+
+// statusIndex returns the index of an effect in the player's status effects, or -1
+func statusIndex(player *models.Player, effect string) int {
+	for i, status := range player.StatusEffects {
+		if status.Type == effect {
+			return i
+		}
+	}
+	return -1
+}
+
+// addStatusEffect puts a lasting effect on a player for a number of rounds. Zero rounds
+// keeps the effect until it is used up. Reapplying an active effect keeps whichever
+// lasts longer.
+func addStatusEffect(player *models.Player, effect, appliedBy string, rounds int, result *Result) {
+	if i := statusIndex(player, effect); i >= 0 {
+		status := &player.StatusEffects[i]
+		if rounds == 0 || (status.ExpiresAfterTurns != 0 && rounds > status.ExpiresAfterTurns) {
+			status.ExpiresAfterTurns = rounds
+		}
+		status.AppliedBy = appliedBy
+	} else {
+		player.StatusEffects = append(player.StatusEffects, models.SpecialEffect{
+			Type:              effect,
+			AppliedBy:         appliedBy,
+			ExpiresAfterTurns: rounds,
+		})
+	}
+
+	result.emit("status_effect_added", map[string]interface{}{
+		"playerId":        player.ID,
+		"effect":          effect,
+		"appliedBy":       appliedBy,
+		"remainingRounds": rounds,
+	})
+}
+
+// removeStatusEffect clears a lasting effect from a player
+func removeStatusEffect(player *models.Player, effect string, result *Result) {
+	i := statusIndex(player, effect)
+	if i < 0 {
+		return
+	}
+	player.StatusEffects = append(player.StatusEffects[:i], player.StatusEffects[i+1:]...)
+
+	result.emit("status_effect_expired", map[string]interface{}{
+		"playerId": player.ID,
+		"effect":   effect,
+	})
+}
+
+// countDownStatusEffects runs at the start of each round, using up a round of every
+// timed effect and removing those that have run out
+func countDownStatusEffects(game *models.Game, result *Result) {
+	for i := range game.Players {
+		player := &game.Players[i]
+		var expired []string
+		for j := range player.StatusEffects {
+			status := &player.StatusEffects[j]
+			if status.ExpiresAfterTurns == 0 {
+				continue
+			}
+			status.ExpiresAfterTurns--
+			if status.ExpiresAfterTurns == 0 {
+				expired = append(expired, status.Type)
+			}
+		}
+		for _, effect := range expired {
+			removeStatusEffect(player, effect, result)
+		}
+	}
+}
+
+// isImmune reports whether a player's properties are protected from other players' cards
+func isImmune(game *models.Game, ownerID string) bool {
+	owner := findPlayer(game, ownerID)
+	return owner != nil && hasStatus(owner, StatusPropertyImmunity)
+}
+
+// rentCollector returns the player who collects all rent this round, or nil
+func rentCollector(game *models.Game) *models.Player {
+	for i := range game.Players {
+		if player := &game.Players[i]; isInGame(player) && hasStatus(player, StatusCollectAllRent) {
+			return player
+		}
+	}
+	return nil
+}
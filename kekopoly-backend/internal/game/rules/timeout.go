@@ -0,0 +1,81 @@
+package rules
+
+import (
+	"time"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// NextDeadline returns the next time at which Tick has something to do: the end of a
+// running auction, the deadline of a debt the current player cannot pay, a warning
+// about or the end of a timed game, or the turn clock
+func NextDeadline(game *models.Game) (time.Time, bool) {
+	if game.Status != models.GameStatusActive {
+		return time.Time{}, false
+	}
+	var next time.Time
+	found := false
+	consider := func(t time.Time) {
+		if !found || t.Before(next) {
+			next = t
+			found = true
+		}
+	}
+
+	if game.EndsAt != nil {
+		consider(*game.EndsAt)
+	}
+	if warning, ok := nextTimeWarning(game); ok {
+		consider(warning)
+	}
+	if turn, ok := nextTurnDeadline(game); ok {
+		consider(turn)
+	}
+	if auction := game.TurnState.Auction; auction != nil {
+		consider(auction.EndsAt)
+	}
+	if debt := game.TurnState.PendingRent; debt != nil && debt.DueBy != nil {
+		consider(*debt.DueBy)
+	}
+	return next, found
+}
+
+// Tick applies whatever has fallen due at the given time. The server calls it when a
+// deadline from NextDeadline passes, so auctions, debts and turns are settled on time
+// even if the players involved have disconnected. It returns a nil result when nothing was due.
+func (e *Engine) Tick(game *models.Game, now time.Time) (*Result, error) {
+	if game.Status != models.GameStatusActive {
+		return nil, ErrGameNotActive
+	}
+	result := newResult(game, models.GameAction{Type: models.ActionTypeTimeout, Timestamp: now})
+
+	if game.EndsAt != nil && !now.Before(*game.EndsAt) {
+		// Whatever was still running is cut short by the end of the game
+		completeGame(game, models.GameEndReasonTimeLimit, result)
+		return result, nil
+	}
+
+	due := warnTimeRunningOut(game, now, result)
+	if auction := game.TurnState.Auction; auction != nil && !now.Before(auction.EndsAt) {
+		if err := e.closeAuction(game, result); err != nil {
+			return nil, err
+		}
+		due = true
+	}
+	if debt := game.TurnState.PendingRent; debt != nil && debt.DueBy != nil && !now.Before(*debt.DueBy) {
+		e.settleOverdueDebt(game, result)
+		due = true
+	}
+	if warnTurnEnding(game, now, result) {
+		due = true
+	}
+	if e.expireTurn(game, now, result) {
+		due = true
+	}
+	if !due {
+		return nil, nil
+	}
+
+	e.settle(game, result)
+	return result, nil
+}
@@ -0,0 +1,325 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// tradeOpenRounds is how many rounds after the one it was proposed in a trade stays open
+const tradeOpenRounds = 1
+
+// Answers to a trade, sent in the response field of a TRADE action naming a tradeId
+const (
+	TradeResponseAccept  = "ACCEPT"
+	TradeResponseReject  = "REJECT"
+	TradeResponseCounter = "COUNTER"
+	TradeResponseCancel  = "CANCEL"
+)
+
+// trade proposes a trade, or answers one when the payload names a tradeId. Players
+// may trade at any time, not only during their own turn. Shadowbanned players cannot
+// propose, counter or accept trades, but may still turn down or withdraw one.
+func (e *Engine) trade(game *models.Game, player *models.Player, payload interface{}, result *Result) error {
+	tradeID := payloadString(payload, "tradeId")
+	if tradeID == "" {
+		return e.proposeTrade(game, player, payloadString(payload, "recipientId"), payload, "", result)
+	}
+
+	trade := findTrade(game, tradeID)
+	if trade == nil {
+		return ErrTradeNotFound
+	}
+	if game.Round > trade.ExpiresRound {
+		return ErrTradeExpired
+	}
+
+	switch response := payloadString(payload, "response"); response {
+	case TradeResponseAccept:
+		if player.ID != trade.RecipientID {
+			return ErrNotTradeParty
+		}
+		return acceptTrade(game, trade, result)
+	case TradeResponseReject:
+		if player.ID != trade.RecipientID {
+			return ErrNotTradeParty
+		}
+		closeTrade(game, trade, models.TradeStatusRejected, result)
+		return nil
+	case TradeResponseCancel:
+		if player.ID != trade.ProposerID {
+			return ErrNotTradeParty
+		}
+		closeTrade(game, trade, models.TradeStatusCancelled, result)
+		return nil
+	case TradeResponseCounter:
+		if player.ID != trade.RecipientID {
+			return ErrNotTradeParty
+		}
+		// The counter-offer goes back to the proposer with the roles swapped
+		return e.proposeTrade(game, player, trade.ProposerID, payload, trade.ID, result)
+	default:
+		return fmt.Errorf("%w: unknown trade response %q", ErrInvalidPayload, response)
+	}
+}
+
+// proposeTrade opens a trade from the player to a recipient on the terms in the
+// payload. A counter-offer closes the trade it answers. A shadowbanned recipient could
+// not accept, so no trade is offered to them.
+func (e *Engine) proposeTrade(game *models.Game, player *models.Player, recipientID string, payload interface{}, counterOf string, result *Result) error {
+	if err := requireNotShadowbanned(player); err != nil {
+		return err
+	}
+	recipient := findPlayer(game, recipientID)
+	if recipient == nil || recipient.ID == player.ID || !isInGame(recipient) {
+		return ErrInvalidTarget
+	}
+	if err := requireNotShadowbanned(recipient); err != nil {
+		return err
+	}
+	for i := range game.Trades {
+		open := &game.Trades[i]
+		if open.ID != counterOf && open.ProposerID == player.ID && open.RecipientID == recipient.ID {
+			return ErrTradePending
+		}
+	}
+
+	offered, err := payloadOffer(payload, "offered")
+	if err != nil {
+		return err
+	}
+	requested, err := payloadOffer(payload, "requested")
+	if err != nil {
+		return err
+	}
+	if isEmptyOffer(offered) && isEmptyOffer(requested) {
+		return ErrEmptyTrade
+	}
+	if err := checkOffer(game, player, offered); err != nil {
+		return err
+	}
+	if err := checkOffer(game, recipient, requested); err != nil {
+		return err
+	}
+
+	if counterOf != "" {
+		countered := findTrade(game, counterOf)
+		closeTrade(game, countered, models.TradeStatusCountered, result)
+	}
+
+	trade := models.Trade{
+		ID:           tradeID(game, player.ID, recipient.ID, result),
+		GameID:       game.ID.Hex(),
+		ProposerID:   player.ID,
+		RecipientID:  recipient.ID,
+		Offered:      offered,
+		Requested:    requested,
+		Status:       models.TradeStatusPending,
+		CounterOf:    counterOf,
+		ExpiresRound: game.Round + tradeOpenRounds,
+		CreatedAt:    result.timestamp,
+	}
+	game.Trades = append(game.Trades, trade)
+	result.saveTrade(trade)
+
+	result.emit("trade_proposed", map[string]interface{}{
+		"trade": trade,
+	})
+	return nil
+}
+
+// tradeID names a new trade after the action that proposed it, so replaying the game's
+// history opens the trade under the same ID its answer refers to. The history sequence
+// number tells apart actions taken within the same millisecond.
+func tradeID(game *models.Game, proposerID, recipientID string, result *Result) string {
+	name := fmt.Sprintf("%s/%d/%s/%s/%d", game.ID.Hex(), game.EventSeq, proposerID, recipientID, result.timestamp.UnixNano())
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(name)).String()
+}
+
+// acceptTrade checks that both players can still hand over what they promised and
+// then moves the properties, kekels and cards in one go
+func acceptTrade(game *models.Game, trade *models.Trade, result *Result) error {
+	if err := CanAcceptTrade(game, trade); err != nil {
+		return err
+	}
+	proposer := findPlayer(game, trade.ProposerID)
+	recipient := findPlayer(game, trade.RecipientID)
+
+	handOver(game, proposer, recipient, trade.Offered, result)
+	handOver(game, recipient, proposer, trade.Requested, result)
+	closeTrade(game, trade, models.TradeStatusAccepted, result)
+	return nil
+}
+
+// CanAcceptTrade checks that neither player is shadowbanned and both can still hand
+// over what they promised in an open trade and make room for what they receive
+func CanAcceptTrade(game *models.Game, trade *models.Trade) error {
+	proposer := findPlayer(game, trade.ProposerID)
+	recipient := findPlayer(game, trade.RecipientID)
+	if proposer == nil || recipient == nil {
+		return ErrPlayerNotFound
+	}
+	if isShadowbanned(proposer) || isShadowbanned(recipient) {
+		return ErrShadowbanned
+	}
+	if err := checkOffer(game, proposer, trade.Offered); err != nil {
+		return err
+	}
+	if err := checkOffer(game, recipient, trade.Requested); err != nil {
+		return err
+	}
+
+	// Each side must afford the kekels it pays plus the interest on mortgaged
+	// properties it receives, after being paid by the other side
+	if proposer.Balance-trade.Offered.Kekels+trade.Requested.Kekels < offerTransferCost(game, trade.Requested) ||
+		recipient.Balance-trade.Requested.Kekels+trade.Offered.Kekels < offerTransferCost(game, trade.Offered) {
+		return ErrInsufficientFunds
+	}
+	if len(proposer.Cards)-len(trade.Offered.Cards)+len(trade.Requested.Cards) > MaxHandSize ||
+		len(recipient.Cards)-len(trade.Requested.Cards)+len(trade.Offered.Cards) > MaxHandSize {
+		return ErrHandFull
+	}
+	return nil
+}
+
+// handOver moves one side of an accepted trade from one player to the other
+func handOver(game *models.Game, from, to *models.Player, offer models.TradeOffer, result *Result) {
+	if offer.Kekels > 0 {
+		from.Balance -= offer.Kekels
+		to.Balance += offer.Kekels
+		result.record(models.TransactionTypeTrade, from.ID, to.ID, offer.Kekels, "")
+	}
+	for _, propertyID := range offer.Properties {
+		transferProperty(findProperty(game, propertyID), from, to, result)
+	}
+	for _, cardID := range offer.Cards {
+		i := handIndex(from, cardID)
+		to.Cards = append(to.Cards, from.Cards[i])
+		from.Cards = append(from.Cards[:i], from.Cards[i+1:]...)
+	}
+}
+
+// closeTrade resolves an open trade and announces the outcome
+func closeTrade(game *models.Game, trade *models.Trade, status models.TradeStatus, result *Result) {
+	resolvedAt := result.timestamp
+	trade.Status = status
+	trade.ResolvedAt = &resolvedAt
+	closed := *trade
+
+	for i := range game.Trades {
+		if game.Trades[i].ID == closed.ID {
+			game.Trades = append(game.Trades[:i], game.Trades[i+1:]...)
+			break
+		}
+	}
+	result.saveTrade(closed)
+
+	data := map[string]interface{}{
+		"trade":  closed,
+		"status": status,
+	}
+	if status == models.TradeStatusAccepted {
+		balances := map[string]int{}
+		for _, id := range []string{closed.ProposerID, closed.RecipientID} {
+			if player := findPlayer(game, id); player != nil {
+				balances[id] = player.Balance
+			}
+		}
+		data["balances"] = balances
+	}
+	result.emit("trade_resolved", data)
+}
+
+// expireTrades closes the trades whose time has run out or whose players have left
+// the game. It runs after every accepted action.
+func expireTrades(game *models.Game, result *Result) {
+	for i := len(game.Trades) - 1; i >= 0; i-- {
+		trade := &game.Trades[i]
+		proposer := findPlayer(game, trade.ProposerID)
+		recipient := findPlayer(game, trade.RecipientID)
+		switch {
+		case proposer == nil || recipient == nil || !isInGame(proposer) || !isInGame(recipient):
+			closeTrade(game, trade, models.TradeStatusCancelled, result)
+		case game.Round > trade.ExpiresRound:
+			closeTrade(game, trade, models.TradeStatusExpired, result)
+		}
+	}
+}
+
+// checkOffer checks that a player holds everything on their side of a trade.
+// Properties with buildings cannot be traded.
+func checkOffer(game *models.Game, player *models.Player, offer models.TradeOffer) error {
+	if offer.Kekels < 0 {
+		return fmt.Errorf("%w: kekels cannot be negative", ErrInvalidPayload)
+	}
+	if player.Balance < offer.Kekels {
+		return ErrInsufficientFunds
+	}
+	for _, propertyID := range offer.Properties {
+		property := findProperty(game, propertyID)
+		if property == nil {
+			return ErrPropertyNotFound
+		}
+		if property.OwnerID != player.ID {
+			return ErrNotPropertyOwner
+		}
+		if hasBuildings(property) {
+			return ErrHasBuildings
+		}
+	}
+	for _, cardID := range offer.Cards {
+		if handIndex(player, cardID) < 0 {
+			return ErrCardNotHeld
+		}
+	}
+	return nil
+}
+
+// offerTransferCost is the mortgage interest owed on the properties in an offer
+func offerTransferCost(game *models.Game, offer models.TradeOffer) int {
+	cost := 0
+	for _, propertyID := range offer.Properties {
+		if property := findProperty(game, propertyID); property != nil {
+			cost += transferCost(property)
+		}
+	}
+	return cost
+}
+
+// isEmptyOffer reports whether one side of a trade hands over nothing
+func isEmptyOffer(offer models.TradeOffer) bool {
+	return offer.Kekels == 0 && len(offer.Properties) == 0 && len(offer.Cards) == 0
+}
+
+// findTrade returns the open trade with the given ID, or nil
+func findTrade(game *models.Game, tradeID string) *models.Trade {
+	for i := range game.Trades {
+		if game.Trades[i].ID == tradeID {
+			return &game.Trades[i]
+		}
+	}
+	return nil
+}
+
+// payloadOffer reads one side of a trade from an action payload. Repeated
+// properties or cards are rejected.
+func payloadOffer(payload interface{}, key string) (models.TradeOffer, error) {
+	fields := payloadMap(payloadMap(payload)[key])
+	kekels, _ := payloadInt(fields, "kekels")
+	offer := models.TradeOffer{
+		Properties: payloadStrings(fields, "properties"),
+		Kekels:     kekels,
+		Cards:      payloadStrings(fields, "cards"),
+	}
+
+	seen := map[string]bool{}
+	for _, id := range append(append([]string{}, offer.Properties...), offer.Cards...) {
+		if seen[id] {
+			return offer, fmt.Errorf("%w: %s is listed twice", ErrInvalidPayload, id)
+		}
+		seen[id] = true
+	}
+	return offer, nil
+}
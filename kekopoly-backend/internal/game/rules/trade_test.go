@@ -0,0 +1,195 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// newTradeGame returns a game where p1 owns prop-4 and p2 owns prop-7
+func newTradeGame() *models.Game {
+	game := newTestGame()
+	game.Round = 1
+	game.BoardState.Properties[0].OwnerID = "p1"
+	game.BoardState.Properties[1].OwnerID = "p2"
+	game.Players[0].Properties = []string{"prop-4"}
+	game.Players[1].Properties = []string{"prop-7"}
+	return game
+}
+
+func tradePayload(recipientID string, offered, requested map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"recipientId": recipientID, "offered": offered, "requested": requested}
+}
+
+func tradeResponse(tradeID, response string) map[string]interface{} {
+	return map[string]interface{}{"tradeId": tradeID, "response": response}
+}
+
+func TestProposeAndAcceptTrade(t *testing.T) {
+	game := newTradeGame()
+	game.Players[0].Cards = []models.Card{{ID: "meme_07"}}
+	engine := newTestEngine()
+
+	// Trades do not wait for the player's turn
+	game.CurrentTurn = "p2"
+	result, err := engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2",
+		map[string]interface{}{"properties": []interface{}{"prop-4"}, "kekels": float64(50), "cards": []interface{}{"meme_07"}},
+		map[string]interface{}{"properties": []interface{}{"prop-7"}},
+	)))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"trade_proposed"}, eventTypes(result))
+	require.Len(t, game.Trades, 1)
+	require.Len(t, result.Trades, 1)
+	trade := game.Trades[0]
+	assert.Equal(t, models.TradeStatusPending, trade.Status)
+	assert.Equal(t, 2, trade.ExpiresRound)
+
+	_, err = engine.Apply(game, action(models.ActionTypeTrade, "p1", tradeResponse(trade.ID, TradeResponseAccept)))
+	assert.ErrorIs(t, err, ErrNotTradeParty)
+
+	result, err = engine.Apply(game, action(models.ActionTypeTrade, "p2", tradeResponse(trade.ID, TradeResponseAccept)))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"trade_resolved"}, eventTypes(result))
+	assert.Empty(t, game.Trades)
+	require.Len(t, result.Trades, 1)
+	assert.Equal(t, models.TradeStatusAccepted, result.Trades[0].Status)
+	assert.NotNil(t, result.Trades[0].ResolvedAt)
+
+	assert.Equal(t, "p2", game.BoardState.Properties[0].OwnerID)
+	assert.Equal(t, "p1", game.BoardState.Properties[1].OwnerID)
+	assert.Equal(t, []string{"prop-7"}, game.Players[0].Properties)
+	assert.Equal(t, []string{"prop-4"}, game.Players[1].Properties)
+	assert.Equal(t, 1450, game.Players[0].Balance)
+	assert.Equal(t, 1550, game.Players[1].Balance)
+	assert.Empty(t, game.Players[0].Cards)
+	assert.Equal(t, []string{"meme_07"}, handIDs(&game.Players[1]))
+	require.Len(t, result.Transactions, 1)
+	assert.Equal(t, models.TransactionTypeTrade, result.Transactions[0].Type)
+}
+
+func TestTradeRejectsPropertiesWithBuildings(t *testing.T) {
+	game := newTradeGame()
+	game.BoardState.Properties[0].Engagements = 1
+	engine := newTestEngine()
+
+	_, err := engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2",
+		map[string]interface{}{"properties": []interface{}{"prop-4"}}, nil)))
+	assert.ErrorIs(t, err, ErrHasBuildings)
+
+	// Buildings added after the offer was made block it too
+	game.BoardState.Properties[0].Engagements = 0
+	_, err = engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2",
+		map[string]interface{}{"properties": []interface{}{"prop-4"}}, map[string]interface{}{"kekels": 100})))
+	require.NoError(t, err)
+	game.BoardState.Properties[0].Engagements = 1
+	_, err = engine.Apply(game, action(models.ActionTypeTrade, "p2", tradeResponse(game.Trades[0].ID, TradeResponseAccept)))
+	assert.ErrorIs(t, err, ErrHasBuildings)
+	assert.Equal(t, "p1", game.BoardState.Properties[0].OwnerID)
+	assert.Equal(t, 1500, game.Players[1].Balance)
+}
+
+func TestTradeValidatesOffers(t *testing.T) {
+	game := newTradeGame()
+	engine := newTestEngine()
+
+	_, err := engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p1", nil, map[string]interface{}{"kekels": 1})))
+	assert.ErrorIs(t, err, ErrInvalidTarget)
+	_, err = engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2", nil, nil)))
+	assert.ErrorIs(t, err, ErrEmptyTrade)
+	_, err = engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2",
+		map[string]interface{}{"properties": []interface{}{"prop-7"}}, nil)))
+	assert.ErrorIs(t, err, ErrNotPropertyOwner)
+	_, err = engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2",
+		nil, map[string]interface{}{"kekels": 5000})))
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	_, err = engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2",
+		map[string]interface{}{"cards": []interface{}{"meme_07"}}, nil)))
+	assert.ErrorIs(t, err, ErrCardNotHeld)
+
+	_, err = engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2", map[string]interface{}{"kekels": 10}, nil)))
+	require.NoError(t, err)
+	_, err = engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2", map[string]interface{}{"kekels": 20}, nil)))
+	assert.ErrorIs(t, err, ErrTradePending)
+}
+
+func TestCounterOfferSwapsRoles(t *testing.T) {
+	game := newTradeGame()
+	engine := newTestEngine()
+
+	_, err := engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2",
+		map[string]interface{}{"kekels": 100}, map[string]interface{}{"properties": []interface{}{"prop-7"}})))
+	require.NoError(t, err)
+	original := game.Trades[0].ID
+
+	counter := tradeResponse(original, TradeResponseCounter)
+	counter["offered"] = map[string]interface{}{"properties": []interface{}{"prop-7"}}
+	counter["requested"] = map[string]interface{}{"kekels": 250}
+	result, err := engine.Apply(game, action(models.ActionTypeTrade, "p2", counter))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"trade_resolved", "trade_proposed"}, eventTypes(result))
+	assert.Equal(t, models.TradeStatusCountered, result.Trades[0].Status)
+
+	require.Len(t, game.Trades, 1)
+	trade := game.Trades[0]
+	assert.Equal(t, "p2", trade.ProposerID)
+	assert.Equal(t, "p1", trade.RecipientID)
+	assert.Equal(t, original, trade.CounterOf)
+
+	_, err = engine.Apply(game, action(models.ActionTypeTrade, "p2", tradeResponse(original, TradeResponseAccept)))
+	assert.ErrorIs(t, err, ErrTradeNotFound)
+
+	result, err = engine.Apply(game, action(models.ActionTypeTrade, "p1", tradeResponse(trade.ID, TradeResponseReject)))
+	require.NoError(t, err)
+	assert.Equal(t, models.TradeStatusRejected, result.Trades[0].Status)
+	assert.Empty(t, game.Trades)
+	assert.Equal(t, "p2", game.BoardState.Properties[1].OwnerID)
+}
+
+func TestTradesProposedInTheSameMillisecondGetTheirOwnIDs(t *testing.T) {
+	game := newAuctionGame()
+	game.TurnState = models.TurnState{}
+	engine := newTestEngine()
+
+	propose := func(recipientID string) {
+		_, err := engine.Apply(game, timedAction(models.ActionTypeTrade, "p1", tradePayload(recipientID, map[string]interface{}{"kekels": 10}, nil), 0))
+		require.NoError(t, err)
+		// The game manager numbers each action in the game's history
+		game.EventSeq++
+	}
+	propose("p2")
+	propose("p3")
+
+	require.Len(t, game.Trades, 2)
+	assert.NotEqual(t, game.Trades[0].ID, game.Trades[1].ID)
+}
+
+func TestTradesExpireAfterTheNextRound(t *testing.T) {
+	game := newTradeGame()
+	engine := newTestEngine()
+	engine.SetMarketSettings(MarketSettings{})
+
+	_, err := engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2", map[string]interface{}{"kekels": 10}, nil)))
+	require.NoError(t, err)
+
+	endRound := func() *Result {
+		var result *Result
+		for _, id := range []string{"p1", "p2"} {
+			game.TurnState.HasRolled = true
+			var err error
+			result, err = engine.Apply(game, action(models.ActionTypeEndTurn, id, nil))
+			require.NoError(t, err)
+		}
+		return result
+	}
+
+	endRound()
+	assert.Len(t, game.Trades, 1)
+
+	result := endRound()
+	assert.Empty(t, game.Trades)
+	require.Len(t, result.Trades, 1)
+	assert.Equal(t, models.TradeStatusExpired, result.Trades[0].Status)
+}
@@ -0,0 +1,143 @@
+package rules
+
+import (
+	"time"
+
+	"github.com/kekopoly/backend/internal/game/cards"
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+const (
+	// TurnWarning is how long before their turn times out a player is warned
+	TurnWarning = 15 * time.Second
+	// AFKTimeouts is how many turns in a row a player may time out before being flagged AFK
+	AFKTimeouts = 2
+)
+
+// startTurnClock gives the player whose turn has just begun the game's turn timeout
+func startTurnClock(game *models.Game, now time.Time) {
+	if game.TurnTimeoutSeconds <= 0 || game.CurrentTurn == "" {
+		return
+	}
+	deadline := now.Add(time.Duration(game.TurnTimeoutSeconds) * time.Second)
+	game.TurnState.Deadline = &deadline
+	game.TurnState.Warned = false
+	game.TurnState.TimedOut = false
+}
+
+// turnClockPaused reports whether the turn clock is waiting on another deadline, an
+// auction or a liquidation window, that the turn cannot end before
+func turnClockPaused(game *models.Game) bool {
+	debt := game.TurnState.PendingRent
+	return game.TurnState.Auction != nil || (debt != nil && debt.DueBy != nil)
+}
+
+// nextTurnDeadline returns when the turn clock next needs attention: the warning to the
+// player, then the timeout itself
+func nextTurnDeadline(game *models.Game) (time.Time, bool) {
+	deadline := game.TurnState.Deadline
+	if deadline == nil || turnClockPaused(game) {
+		return time.Time{}, false
+	}
+	if !game.TurnState.Warned && !game.TurnState.TimedOut && time.Duration(game.TurnTimeoutSeconds)*time.Second > TurnWarning {
+		return deadline.Add(-TurnWarning), true
+	}
+	return *deadline, true
+}
+
+// warnTurnEnding privately warns the current player that their turn is about to time out
+func warnTurnEnding(game *models.Game, now time.Time, result *Result) bool {
+	deadline := game.TurnState.Deadline
+	if deadline == nil || game.TurnState.Warned || game.TurnState.TimedOut || turnClockPaused(game) {
+		return false
+	}
+	if now.Before(deadline.Add(-TurnWarning)) || !now.Before(*deadline) {
+		return false
+	}
+	game.TurnState.Warned = true
+	result.emitTo(game.CurrentTurn, "turn_ending_soon", map[string]interface{}{
+		"playerId":         game.CurrentTurn,
+		"deadline":         *deadline,
+		"remainingSeconds": int(deadline.Sub(now).Seconds()),
+	})
+	return true
+}
+
+// expireTurn plays out a turn whose clock has run out: the dice are rolled if they
+// were not, a card waiting to be drawn is drawn and any choice it needs is passed on,
+// a property on offer is declined and affordable rent is paid. The turn then ends,
+// unless an auction or a liquidation window has to finish first, in which case the
+// next Tick after it does picks the turn up again.
+func (e *Engine) expireTurn(game *models.Game, now time.Time, result *Result) bool {
+	deadline := game.TurnState.Deadline
+	if deadline == nil || now.Before(*deadline) || turnClockPaused(game) {
+		return false
+	}
+	player := findPlayer(game, game.CurrentTurn)
+	if player == nil {
+		return false
+	}
+
+	if !game.TurnState.TimedOut {
+		game.TurnState.TimedOut = true
+		player.Timeouts++
+		result.emit("turn_timed_out", map[string]interface{}{
+			"playerId": player.ID,
+			"timeouts": player.Timeouts,
+		})
+		if player.Timeouts >= AFKTimeouts && !player.AFK {
+			player.AFK = true
+			result.emit("player_afk", map[string]interface{}{
+				"playerId": player.ID,
+				"timeouts": player.Timeouts,
+			})
+		}
+	}
+
+	// The steps below cannot be rejected: it is the player's turn and each one only
+	// runs once what it depends on is settled
+	if !game.TurnState.HasRolled && game.TurnState.PendingRent == nil && game.TurnState.PendingCard == nil && game.TurnState.PendingDraw == "" {
+		_ = e.rollDice(game, player, nil, now, result)
+	}
+	if game.TurnState.PendingDraw != "" {
+		if err := e.drawCard(game, player, nil, result); err != nil {
+			game.TurnState.PendingDraw = ""
+		}
+	}
+	if pending := game.TurnState.PendingCard; pending != nil {
+		if err := e.resolvePendingCard(game, player, "", map[string]interface{}{"pass": true}, result); err != nil {
+			// A card that may not be passed on is discarded unplayed
+			game.TurnState.PendingCard = nil
+			if def, ok := cards.Lookup(pending.ID); ok {
+				e.discard(game, def)
+			}
+		}
+	}
+	if game.TurnState.PendingPurchase != "" {
+		_ = e.declineProperty(game, player, result)
+	}
+	if debt := game.TurnState.PendingRent; debt != nil && player.Balance >= debt.Amount {
+		_ = e.payRent(game, player, result)
+	}
+	if game.TurnState.PendingRent != nil || game.TurnState.Auction != nil {
+		return true
+	}
+
+	e.passTurn(game, player, result)
+	return true
+}
+
+// markPresent clears the timeouts of a player who acted for themselves
+func markPresent(player *models.Player, result *Result) {
+	if player.Timeouts == 0 && !player.AFK {
+		return
+	}
+	wasAFK := player.AFK
+	player.Timeouts = 0
+	player.AFK = false
+	if wasAFK {
+		result.emit("player_back", map[string]interface{}{
+			"playerId": player.ID,
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// newClockedGame returns a game whose players have a minute for each turn
+func newClockedGame() *models.Game {
+	game := newTestGame()
+	game.TurnTimeoutSeconds = 60
+	StartClock(game, auctionStart)
+	return game
+}
+
+func TestTurnClockWarnsThePlayer(t *testing.T) {
+	game := newClockedGame()
+	engine := newTestEngine()
+	deadline := auctionStart.Add(time.Minute)
+	require.NotNil(t, game.TurnState.Deadline)
+	assert.Equal(t, deadline, *game.TurnState.Deadline)
+
+	warning, ok := NextDeadline(game)
+	require.True(t, ok)
+	assert.Equal(t, deadline.Add(-TurnWarning), warning)
+
+	result, err := engine.Tick(game, warning)
+	require.NoError(t, err)
+	require.Len(t, result.Events, 1)
+	assert.Equal(t, "turn_ending_soon", result.Events[0].Type)
+	assert.Equal(t, "p1", result.Events[0].Recipient)
+
+	next, _ := NextDeadline(game)
+	assert.Equal(t, deadline, next)
+}
+
+func TestTimedOutTurnIsPlayedForThePlayer(t *testing.T) {
+	game := newClockedGame()
+	engine := newTestEngine(1, 2)
+	engine.SetMarketSettings(MarketSettings{})
+	deadline := auctionStart.Add(time.Minute)
+
+	// The roll lands on prop-4, which is declined and goes to auction
+	result, err := engine.Tick(game, deadline)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"turn_timed_out", "dice_rolled", "purchase_available", "auction_started"}, eventTypes(result))
+	assert.Equal(t, 1, game.Players[0].Timeouts)
+	assert.Equal(t, "p1", game.CurrentTurn)
+
+	// The turn clock waits for the auction, and the turn ends once it closes
+	next, _ := NextDeadline(game)
+	assert.Equal(t, deadline.Add(AuctionDuration), next)
+	result, err = engine.Tick(game, next)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"auction_ended", "turn_ended", "game_turn"}, eventTypes(result))
+	assert.Equal(t, "p2", game.CurrentTurn)
+	assert.Equal(t, 1, game.Players[0].Timeouts)
+	require.NotNil(t, game.TurnState.Deadline)
+	assert.Equal(t, next.Add(time.Minute), *game.TurnState.Deadline)
+	assert.Equal(t, game.TurnState.Deadline, result.Events[2].Data["turnDeadline"])
+}
+
+func TestRepeatedTimeoutsFlagThePlayerAFK(t *testing.T) {
+	game := newClockedGame()
+	engine := newTestEngine(2, 4)
+	engine.SetMarketSettings(MarketSettings{})
+	game.Players[0].Timeouts = AFKTimeouts - 1
+	game.BoardState.Properties[1].OwnerID = "p2"
+	game.Players[1].Properties = []string{"prop-7"}
+
+	// Rent is paid on the player's behalf before the turn ends
+	result, err := engine.Tick(game, auctionStart.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"turn_timed_out", "player_afk", "dice_rolled", "rent_due", "rent_paid", "turn_ended", "game_turn"}, eventTypes(result))
+	assert.True(t, game.Players[0].AFK)
+
+	// Acting again clears the flag
+	_, err = engine.Apply(game, action(models.ActionTypeTrade, "p1", tradePayload("p2", map[string]interface{}{"kekels": 10}, nil)))
+	require.NoError(t, err)
+	assert.False(t, game.Players[0].AFK)
+	assert.Zero(t, game.Players[0].Timeouts)
+}
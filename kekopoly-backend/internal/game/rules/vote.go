@@ -0,0 +1,104 @@
+package rules
+
+import (
+	"github.com/kekopoly/backend/internal/game/models"
+)
+
+// startVote opens a vote on which player must forfeit kekels to the bank
+func startVote(game *models.Game, player *models.Player, cardID string, amount int, result *Result) {
+	game.TurnState.PendingVote = &models.Vote{
+		CardID:   cardID,
+		CalledBy: player.ID,
+		Amount:   amount,
+		Votes:    map[string]string{},
+	}
+
+	result.emit("vote_started", map[string]interface{}{
+		"calledBy": player.ID,
+		"cardId":   cardID,
+		"amount":   amount,
+	})
+}
+
+// castVote records a player's vote. Any player still in the game may vote once, for
+// anyone but themselves, and the vote closes as soon as everyone has voted.
+func (e *Engine) castVote(game *models.Game, player *models.Player, payload interface{}, result *Result) error {
+	vote := game.TurnState.PendingVote
+	if vote == nil {
+		return ErrNoVote
+	}
+	if _, voted := vote.Votes[player.ID]; voted {
+		return ErrAlreadyVoted
+	}
+
+	targetID := payloadString(payload, "targetPlayerId")
+	target := findPlayer(game, targetID)
+	if target == nil || target.ID == player.ID || !isInGame(target) {
+		return ErrInvalidTarget
+	}
+
+	vote.Votes[player.ID] = target.ID
+	result.emit("vote_cast", map[string]interface{}{
+		"playerId":       player.ID,
+		"targetPlayerId": target.ID,
+	})
+
+	for i := range game.Players {
+		if voter := &game.Players[i]; isInGame(voter) {
+			if _, voted := vote.Votes[voter.ID]; !voted {
+				return nil
+			}
+		}
+	}
+	e.closeVote(game, result)
+	return nil
+}
+
+// closeVote counts the votes and charges the player with the most. Ties are broken
+// at random; a vote nobody took part in charges nobody.
+func (e *Engine) closeVote(game *models.Game, result *Result) {
+	vote := game.TurnState.PendingVote
+	if vote == nil {
+		return
+	}
+	game.TurnState.PendingVote = nil
+
+	tally := map[string]int{}
+	for _, targetID := range vote.Votes {
+		tally[targetID]++
+	}
+
+	var leaders []*models.Player
+	most := 0
+	for i := range game.Players {
+		candidate := &game.Players[i]
+		count := tally[candidate.ID]
+		if count == 0 || !isInGame(candidate) {
+			continue
+		}
+		switch {
+		case count > most:
+			most = count
+			leaders = []*models.Player{candidate}
+		case count == most:
+			leaders = append(leaders, candidate)
+		}
+	}
+
+	data := map[string]interface{}{
+		"cardId": vote.CardID,
+		"tally":  tally,
+	}
+	if len(leaders) == 0 {
+		result.emit("vote_resolved", data)
+		return
+	}
+
+	loser := leaders[0]
+	if len(leaders) > 1 {
+		loser = leaders[e.intn(game, len(leaders))]
+	}
+	data["playerId"] = loser.ID
+	data["amount"] = cardPayment(game, loser, nil, vote.Amount, vote.CardID, result)
+	result.emit("vote_resolved", data)
+}
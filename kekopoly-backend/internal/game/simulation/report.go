@@ -0,0 +1,295 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rules"
+)
+
+// Report sums up a batch of simulated games
+type Report struct {
+	BoardID          string            `json:"boardId"`
+	Seed             int64             `json:"seed"`
+	Games            int               `json:"games"`
+	Unfinished       int               `json:"unfinished"` // Games still going after MaxRounds
+	AverageRounds    float64           `json:"averageRounds"`
+	AverageTurns     float64           `json:"averageTurns"`
+	Seats            []SeatResult      `json:"seats"`
+	Landings         []SpaceLandings   `json:"landings"`
+	Groups           []GroupReturn     `json:"groups"`
+	BankruptcyCauses []BankruptcyCause `json:"bankruptcyCauses"`
+}
+
+// SeatResult is how the bot in one seat fared
+type SeatResult struct {
+	Seat             int                  `json:"seat"` // Place in turn order, counting from one
+	Difficulty       models.BotDifficulty `json:"difficulty"`
+	Wins             int                  `json:"wins"`
+	WinRate          float64              `json:"winRate"`          // Share of finished games won
+	AveragePlacement float64              `json:"averagePlacement"` // Over finished games
+}
+
+// SpaceLandings counts how often a turn ended up on a space
+type SpaceLandings struct {
+	Position  int     `json:"position"`
+	Name      string  `json:"name"`
+	Landings  int     `json:"landings"`
+	Frequency float64 `json:"frequency"` // Share of all landings
+}
+
+// GroupReturn compares what was spent on a property group with the rent it brought in
+type GroupReturn struct {
+	Group         string  `json:"group"`
+	Invested      int     `json:"invested"` // Purchase prices, auctions included, and buildings
+	RentCollected int     `json:"rentCollected"`
+	ROI           float64 `json:"roi"` // Rent collected less investment, over investment
+}
+
+// BankruptcyCause counts the debts that bankrupted players
+type BankruptcyCause struct {
+	Cause string  `json:"cause"` // rent:<group>, or jail_fine
+	Count int     `json:"count"`
+	Share float64 `json:"share"`
+}
+
+// state is what the collector needs from a game before a move
+type state struct {
+	positions map[string]int
+	debt      *models.Debt
+}
+
+func snapshot(game *models.Game) state {
+	s := state{positions: make(map[string]int, len(game.Players))}
+	for _, player := range game.Players {
+		s.positions[player.ID] = player.Position
+	}
+	if debt := game.TurnState.PendingRent; debt != nil {
+		copied := *debt
+		s.debt = &copied
+	}
+	return s
+}
+
+// collector adds up the moves of every game in a batch
+type collector struct {
+	cfg        Config
+	games      int
+	unfinished int
+	rounds     int
+	turns      int
+	wins       []int
+	placements []int
+	landings   []int
+	groups     []string // In board order
+	groupOf    map[string]string
+	invested   map[string]int
+	rent       map[string]int
+	causes     map[string]int
+}
+
+func newCollector(cfg Config) *collector {
+	c := &collector{
+		cfg:        cfg,
+		wins:       make([]int, len(cfg.Seats)),
+		placements: make([]int, len(cfg.Seats)),
+		landings:   make([]int, cfg.Board.Size()),
+		groupOf:    make(map[string]string),
+		invested:   make(map[string]int),
+		rent:       make(map[string]int),
+		causes:     make(map[string]int),
+	}
+	for _, property := range cfg.Board.NewProperties() {
+		group := property.Group
+		if group == "" {
+			group = string(property.Type)
+		}
+		if _, seen := c.invested[group]; !seen {
+			c.groups = append(c.groups, group)
+			c.invested[group] = 0
+		}
+		c.groupOf[property.ID] = group
+	}
+	return c
+}
+
+// record adds up what one accepted move or countdown did to the game
+func (c *collector) record(game *models.Game, before state, result *rules.Result) {
+	for _, player := range game.Players {
+		if from, ok := before.positions[player.ID]; ok && from != player.Position && player.Position < len(c.landings) {
+			c.landings[player.Position]++
+		}
+	}
+
+	for _, tx := range result.Transactions {
+		switch tx.Type {
+		case models.TransactionTypePurchase:
+			c.invested[c.groupOf[tx.PropertyID]] += tx.Amount
+		case models.TransactionTypeRent:
+			c.rent[c.groupOf[tx.PropertyID]] += tx.Amount
+		}
+	}
+
+	for _, event := range result.Events {
+		switch event.Type {
+		case "turn_ended":
+			c.turns++
+		case "engagement_built", "checkmark_built":
+			propertyID, _ := event.Data["propertyId"].(string)
+			cost, _ := event.Data["cost"].(int)
+			c.invested[c.groupOf[propertyID]] += cost
+		case "player_bankrupt":
+			c.causes[bankruptcyCause(before.debt, c.groupOf)]++
+		}
+	}
+}
+
+// bankruptcyCause names the debt a player went bankrupt over
+func bankruptcyCause(debt *models.Debt, groupOf map[string]string) string {
+	switch {
+	case debt == nil:
+		return "unknown"
+	case debt.PropertyID != "":
+		return "rent:" + groupOf[debt.PropertyID]
+	default:
+		return "jail_fine"
+	}
+}
+
+// endGame adds up how a game ended. Unfinished games count towards the averages but
+// not the seat results.
+func (c *collector) endGame(game *models.Game, finished bool) {
+	c.games++
+	c.rounds += game.Round
+	if !finished {
+		c.unfinished++
+		return
+	}
+	for seat := range c.cfg.Seats {
+		for _, player := range game.Players {
+			if player.ID != seatID(seat) {
+				continue
+			}
+			c.placements[seat] += player.Placement
+			if player.ID == game.WinnerID {
+				c.wins[seat]++
+			}
+		}
+	}
+}
+
+func (c *collector) report() *Report {
+	finished := c.games - c.unfinished
+	report := &Report{
+		BoardID:       c.cfg.Board.ID,
+		Seed:          c.cfg.Seed,
+		Games:         c.games,
+		Unfinished:    c.unfinished,
+		AverageRounds: ratio(c.rounds, c.games),
+		AverageTurns:  ratio(c.turns, c.games),
+	}
+
+	for seat, difficulty := range c.cfg.Seats {
+		report.Seats = append(report.Seats, SeatResult{
+			Seat:             seat + 1,
+			Difficulty:       difficulty,
+			Wins:             c.wins[seat],
+			WinRate:          ratio(c.wins[seat], finished),
+			AveragePlacement: ratio(c.placements[seat], finished),
+		})
+	}
+
+	total := 0
+	for _, count := range c.landings {
+		total += count
+	}
+	for position, count := range c.landings {
+		landing := SpaceLandings{Position: position, Landings: count, Frequency: ratio(count, total)}
+		if space := c.cfg.Board.SpaceAt(position); space != nil {
+			landing.Name = space.Name
+		}
+		report.Landings = append(report.Landings, landing)
+	}
+
+	for _, group := range c.groups {
+		invested, rent := c.invested[group], c.rent[group]
+		report.Groups = append(report.Groups, GroupReturn{
+			Group:         group,
+			Invested:      invested,
+			RentCollected: rent,
+			ROI:           ratio(rent-invested, invested),
+		})
+	}
+
+	bankruptcies := 0
+	for _, count := range c.causes {
+		bankruptcies += count
+	}
+	for cause, count := range c.causes {
+		report.BankruptcyCauses = append(report.BankruptcyCauses, BankruptcyCause{
+			Cause: cause,
+			Count: count,
+			Share: ratio(count, bankruptcies),
+		})
+	}
+	sort.Slice(report.BankruptcyCauses, func(i, j int) bool {
+		a, b := report.BankruptcyCauses[i], report.BankruptcyCauses[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Cause < b.Cause
+	})
+	return report
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// WriteCSV writes the report as one section,key,metric,value row per figure
+func (r *Report) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	row := func(section, key, metric, value string) {
+		_ = out.Write([]string{section, key, metric, value})
+	}
+	itoa := strconv.Itoa
+	ftoa := func(f float64) string { return strconv.FormatFloat(f, 'f', 4, 64) }
+
+	row("section", "key", "metric", "value")
+	row("summary", r.BoardID, "games", itoa(r.Games))
+	row("summary", r.BoardID, "seed", strconv.FormatInt(r.Seed, 10))
+	row("summary", r.BoardID, "unfinished", itoa(r.Unfinished))
+	row("summary", r.BoardID, "averageRounds", ftoa(r.AverageRounds))
+	row("summary", r.BoardID, "averageTurns", ftoa(r.AverageTurns))
+	for _, seat := range r.Seats {
+		key := itoa(seat.Seat)
+		row("seat", key, "difficulty", string(seat.Difficulty))
+		row("seat", key, "wins", itoa(seat.Wins))
+		row("seat", key, "winRate", ftoa(seat.WinRate))
+		row("seat", key, "averagePlacement", ftoa(seat.AveragePlacement))
+	}
+	for _, landing := range r.Landings {
+		key := itoa(landing.Position)
+		row("landing", key, "name", landing.Name)
+		row("landing", key, "landings", itoa(landing.Landings))
+		row("landing", key, "frequency", ftoa(landing.Frequency))
+	}
+	for _, group := range r.Groups {
+		row("group", group.Group, "invested", itoa(group.Invested))
+		row("group", group.Group, "rentCollected", itoa(group.RentCollected))
+		row("group", group.Group, "roi", ftoa(group.ROI))
+	}
+	for _, cause := range r.BankruptcyCauses {
+		row("bankruptcy", cause.Cause, "count", itoa(cause.Count))
+		row("bankruptcy", cause.Cause, "share", ftoa(cause.Share))
+	}
+
+	out.Flush()
+	return out.Error()
+}
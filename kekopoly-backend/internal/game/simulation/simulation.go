@@ -0,0 +1,215 @@
+// Package simulation plays bot-vs-bot games entirely in memory to measure how the
+// board and cards play out, so prices and rents can be tuned from data.
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/kekopoly/backend/internal/game/board"
+	"github.com/kekopoly/backend/internal/game/bot"
+	"github.com/kekopoly/backend/internal/game/cards"
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rng"
+	"github.com/kekopoly/backend/internal/game/rules"
+)
+
+const (
+	// MaxRounds stops a game that is still going after this many rounds; it is
+	// reported as unfinished
+	MaxRounds = 500
+	// maxSteps guards against a game that stops making progress
+	maxSteps = 200000
+	// moveInterval is the simulated time between two bot moves
+	moveInterval = time.Second
+	// turnTimeout lets the turn clock settle a turn whose next move the rules reject
+	turnTimeout = 120 * time.Second
+	// startingBalance matches the kekels the game manager gives each player
+	startingBalance = 1500
+)
+
+// simulationStart is the simulated time every game starts at
+var simulationStart = time.Unix(0, 0).UTC()
+
+// Config describes a batch of simulated games
+type Config struct {
+	Board      *board.Board
+	Games      int
+	Seed       int64                  // Game n is played with seed Seed+n, so a batch can be reproduced
+	Seats      []models.BotDifficulty // One bot per seat, in turn order
+	Cards      []string               // IDs of the cards in the decks; nil plays with every card
+	RoundLimit int                    // Rounds before a game ends by net worth; zero plays until one player is left
+}
+
+// Validate checks that a batch can be played
+func (c Config) Validate() error {
+	if c.Board == nil {
+		return errors.New("no board chosen")
+	}
+	if c.Games < 1 {
+		return errors.New("at least one game must be played")
+	}
+	if len(c.Seats) < 2 || len(c.Seats) > 6 {
+		return fmt.Errorf("a game needs 2 to 6 seats, got %d", len(c.Seats))
+	}
+	for _, difficulty := range c.Seats {
+		if _, ok := bot.Difficulties[difficulty]; !ok {
+			return fmt.Errorf("unknown bot difficulty %q", difficulty)
+		}
+	}
+	for _, id := range c.Cards {
+		if _, ok := cards.Lookup(id); !ok {
+			return fmt.Errorf("unknown card %q", id)
+		}
+	}
+	if c.RoundLimit < 0 || c.RoundLimit > rules.MaxRoundLimit {
+		return fmt.Errorf("round limit must be between 0 and %d", rules.MaxRoundLimit)
+	}
+	return nil
+}
+
+// Run plays every game in the batch and reports on them
+func Run(cfg Config) (*Report, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	stats := newCollector(cfg)
+	for n := 0; n < cfg.Games; n++ {
+		if err := play(cfg, cfg.Seed+int64(n), stats); err != nil {
+			return nil, fmt.Errorf("game %d: %w", n, err)
+		}
+	}
+	return stats.report(), nil
+}
+
+// play runs one game from the first roll to the end, moving the simulated clock on
+// by a second per move and jumping to the next deadline when no bot has a move
+func play(cfg Config, seed int64, stats *collector) error {
+	engine := rules.NewEngine(cfg.Board, rng.NewSeeded(seed))
+	clock := simulationStart
+	game, err := newGame(cfg, engine, clock)
+	if err != nil {
+		return err
+	}
+
+	isBot := func(player *models.Player) bool { return player.IsBot }
+	for step := 0; game.Status == models.GameStatusActive; step++ {
+		if step >= maxSteps || game.Round > MaxRounds {
+			stats.endGame(game, false)
+			return nil
+		}
+
+		before := snapshot(game)
+		if action, ok := bot.NextMove(game, isBot); ok {
+			clock = clock.Add(moveInterval)
+			action.Timestamp = clock
+			action.ByBot = true
+			if result, err := engine.Apply(game, action); err == nil {
+				stats.record(game, before, result)
+				continue
+			}
+		}
+
+		// Nothing to do until a countdown runs out, or the rules rejected the bot's
+		// move and the turn clock has to settle it
+		deadline, ok := rules.NextDeadline(game)
+		if !ok {
+			return fmt.Errorf("game stalled in round %d", game.Round)
+		}
+		if deadline.After(clock) {
+			clock = deadline
+		}
+		result, err := engine.Tick(game, clock)
+		if err != nil {
+			return err
+		}
+		if result != nil {
+			stats.record(game, before, result)
+		}
+	}
+
+	stats.endGame(game, true)
+	return nil
+}
+
+// newGame sets up a started game with a bot in every seat, as the game manager would
+// for a lobby game whose host pressed start
+func newGame(cfg Config, engine *rules.Engine, now time.Time) (*models.Game, error) {
+	game := &models.Game{
+		ID:           primitive.NewObjectID(),
+		Name:         "Simulation",
+		Status:       models.GameStatusLobby,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		MaxPlayers:   len(cfg.Seats),
+		BoardID:      cfg.Board.ID,
+		BoardVersion: cfg.Board.Version,
+		BoardState: models.BoardState{
+			Properties:     cfg.Board.NewProperties(),
+			BuildingSupply: rules.NewBuildingSupply(),
+		},
+		MarketCondition:    models.MarketConditionNormal,
+		TurnTimeoutSeconds: int(turnTimeout / time.Second),
+	}
+	mode := models.GameModeClassic
+	if cfg.RoundLimit > 0 {
+		mode = models.GameModeRoundCapped
+	}
+	if err := rules.ConfigureMode(game, mode, 0, cfg.RoundLimit); err != nil {
+		return nil, err
+	}
+
+	for i, difficulty := range cfg.Seats {
+		id := seatID(i)
+		game.Players = append(game.Players, models.Player{
+			ID:            id,
+			Status:        models.PlayerStatusActive,
+			Balance:       startingBalance,
+			Position:      cfg.Board.StartPosition(),
+			Cards:         []models.Card{},
+			Properties:    []string{},
+			NetWorth:      startingBalance,
+			IsBot:         true,
+			BotDifficulty: difficulty,
+		})
+		game.TurnOrder = append(game.TurnOrder, id)
+	}
+	game.HostID = game.TurnOrder[0]
+
+	// Seats keep their order so results can be compared by seat
+	engine.SetupDecks(game)
+	if cfg.Cards != nil {
+		keepCards(game, cfg.Cards)
+	}
+	game.Status = models.GameStatusActive
+	game.CurrentTurn = game.TurnOrder[0]
+	game.Round = 1
+	rules.StartClock(game, now)
+	return game, nil
+}
+
+// keepCards takes every card outside the chosen set out of the decks
+func keepCards(game *models.Game, ids []string) {
+	keep := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		keep[id] = true
+	}
+	for _, deck := range game.BoardState.Decks {
+		pile := deck.DrawPile[:0]
+		for _, id := range deck.DrawPile {
+			if keep[id] {
+				pile = append(pile, id)
+			}
+		}
+		deck.DrawPile = pile
+	}
+}
+
+// seatID names the player in a seat, counting from one
+func seatID(seat int) string {
+	return fmt.Sprintf("seat-%d", seat+1)
+}
@@ -0,0 +1,117 @@
+package simulation
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kekopoly/backend/internal/game/board"
+	"github.com/kekopoly/backend/internal/game/models"
+	"github.com/kekopoly/backend/internal/game/rng"
+	"github.com/kekopoly/backend/internal/game/rules"
+)
+
+func testConfig() Config {
+	return Config{
+		Board:      board.Default(),
+		Games:      10,
+		Seed:       7,
+		Seats:      []models.BotDifficulty{models.BotDifficultyCautious, models.BotDifficultyBalanced, models.BotDifficultyAggressive},
+		RoundLimit: 30,
+	}
+}
+
+func TestConfigIsValidated(t *testing.T) {
+	cfg := testConfig()
+	cfg.Seats = cfg.Seats[:1]
+	assert.Error(t, cfg.Validate())
+
+	cfg = testConfig()
+	cfg.Seats[0] = "RECKLESS"
+	assert.Error(t, cfg.Validate())
+
+	cfg = testConfig()
+	cfg.Cards = []string{"meme_01", "meme_99"}
+	assert.Error(t, cfg.Validate())
+
+	assert.NoError(t, testConfig().Validate())
+}
+
+func TestBatchesAreReproducible(t *testing.T) {
+	first, err := Run(testConfig())
+	require.NoError(t, err)
+	second, err := Run(testConfig())
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	cfg := testConfig()
+	cfg.Seed++
+	other, err := Run(cfg)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Landings, other.Landings)
+}
+
+func TestReportAddsUp(t *testing.T) {
+	cfg := testConfig()
+	report, err := Run(cfg)
+	require.NoError(t, err)
+
+	// Every round-capped game finishes with a winner
+	assert.Equal(t, cfg.Games, report.Games)
+	assert.Zero(t, report.Unfinished)
+	wins := 0
+	for _, seat := range report.Seats {
+		wins += seat.Wins
+	}
+	assert.Equal(t, cfg.Games, wins)
+	assert.InDelta(t, cfg.RoundLimit, report.AverageRounds, 1)
+
+	require.Len(t, report.Landings, cfg.Board.Size())
+	frequency := 0.0
+	for _, landing := range report.Landings {
+		frequency += landing.Frequency
+	}
+	assert.InDelta(t, 1, frequency, 0.0001)
+	assert.Equal(t, "START", report.Landings[cfg.Board.StartPosition()].Name)
+
+	invested := 0
+	for _, group := range report.Groups {
+		invested += group.Invested
+	}
+	assert.Positive(t, invested)
+}
+
+func TestCardSetLimitsTheDecks(t *testing.T) {
+	cfg := testConfig()
+	cfg.Cards = []string{"meme_01", "redpill_01"}
+	game, err := newGame(cfg, rules.NewEngine(cfg.Board, rng.NewSeeded(1)), simulationStart)
+	require.NoError(t, err)
+
+	var inPlay []string
+	for _, deck := range game.BoardState.Decks {
+		inPlay = append(inPlay, deck.DrawPile...)
+	}
+	assert.ElementsMatch(t, cfg.Cards, inPlay)
+	assert.Equal(t, models.GameStatusActive, game.Status)
+	assert.Equal(t, "seat-1", game.CurrentTurn)
+
+	// Games still run when card spaces have nothing to draw
+	cfg.Cards = []string{}
+	_, err = Run(cfg)
+	assert.NoError(t, err)
+}
+
+func TestReportAsCSV(t *testing.T) {
+	report, err := Run(testConfig())
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, report.WriteCSV(&out))
+	lines := strings.Split(out.String(), "\n")
+	assert.Equal(t, "section,key,metric,value", lines[0])
+	assert.Contains(t, out.String(), "seat,2,difficulty,BALANCED\n")
+	assert.Contains(t, out.String(), "group,brown,invested,")
+}
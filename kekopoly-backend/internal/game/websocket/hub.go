@@ -4,8 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"math/rand"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -18,9 +16,6 @@ import (
 	"go.uber.org/zap"
 )
 
-// Initialize a separate random number generator for dice rolls to ensure consistency
-var diceRand = rand.New(rand.NewSource(time.Now().UnixNano()))
-
 // MessageQueue defines the interface for the message queue
 type MessageQueue interface {
 	EnqueuePlayerTokenUpdate(gameID, playerID string, tokenData map[string]interface{}) error
@@ -580,6 +575,34 @@ func (h *Hub) UpdateHostID(gameID string, hostID string) {
 	}()
 }
 
+// handlePlayerReconnected gives a player who reconnects to a running game their seat
+// back, taking it off the bot that played for them while they were away
+func (h *Hub) handlePlayerReconnected(gameID, playerID, sessionID string) {
+	if h.gameManager == nil {
+		return
+	}
+	game, err := h.gameManager.GetGame(gameID)
+	if err != nil || game.Status != models.GameStatusActive {
+		return
+	}
+	seated := false
+	for _, player := range game.Players {
+		if player.ID == playerID {
+			seated = true
+			break
+		}
+	}
+	if !seated {
+		return
+	}
+
+	if err := h.gameManager.RejoinGame(gameID, playerID, sessionID); err != nil {
+		h.logger.Debugf("[Hub handlePlayerReconnected] Player %s did not rejoin game %s: %v", playerID, gameID, err)
+		return
+	}
+	h.logger.Infof("[Hub handlePlayerReconnected] Player %s rejoined running game %s", playerID, gameID)
+}
+
 // handlePlayerDisconnected handles a player disconnection
 func (h *Hub) handlePlayerDisconnected(gameID, playerID, sessionID string) {
 	h.logger.Infof("[Hub handlePlayerDisconnected] Player %s disconnected from game %s with session %s",
@@ -714,7 +737,8 @@ func (h *Hub) BroadcastToGameWithPriority(gameID string, message []byte, priorit
 	}
 }
 
-// BroadcastCompleteState broadcasts the complete game state to all clients in a game
+// BroadcastCompleteState broadcasts the complete game state to all clients in a game,
+// each seeing only their own hand
 func (h *Hub) BroadcastCompleteState(gameID string, game *models.Game) {
 	if game == nil {
 		h.logger.Errorf("Cannot broadcast complete state: game is nil for gameID %s", gameID)
@@ -729,7 +753,6 @@ func (h *Hub) BroadcastCompleteState(gameID string, game *models.Game) {
 		"gameId":      gameID,
 		"status":      string(game.Status),
 		"currentTurn": game.CurrentTurn,
-		"players":     game.Players,
 		"turnOrder":   game.TurnOrder,
 		"timestamp":   time.Now().Format(time.RFC3339),
 	}
@@ -740,17 +763,23 @@ func (h *Hub) BroadcastCompleteState(gameID string, game *models.Game) {
 			player.ID, player.CharacterToken)
 	}
 
-	// Marshal to JSON
-	stateJSON, err := json.Marshal(completeState)
-	if err != nil {
-		h.logger.Errorf("Failed to marshal complete state: %v", err)
-		return
+	// Collect the connected clients first, as sending takes the clients lock itself
+	h.clientsMutex.RLock()
+	clientIDs := make([]string, 0, len(h.clients[gameID]))
+	for clientID := range h.clients[gameID] {
+		clientIDs = append(clientIDs, clientID)
 	}
+	h.clientsMutex.RUnlock()
 
-	// Broadcast with high priority
-	h.broadcast <- &BroadcastMessage{
-		gameID: gameID,
-		data:   stateJSON,
+	// Send each client their own view with high priority
+	for _, clientID := range clientIDs {
+		completeState["players"] = models.PlayersVisibleTo(game.Players, clientID)
+		stateJSON, err := json.Marshal(completeState)
+		if err != nil {
+			h.logger.Errorf("Failed to marshal complete state: %v", err)
+			return
+		}
+		h.SendToPlayerWithPriority(gameID, clientID, stateJSON, PriorityHigh)
 	}
 
 	h.logger.Infof("Complete state sync broadcast sent for game %s", gameID)
@@ -940,6 +969,33 @@ func (h *Hub) HandleWebSocketConnection(conn *websocket.Conn, gameID, playerID,
 	go client.writePump()
 }
 
+// handleGameAction submits a game action on behalf of the client. The game manager
+// broadcasts the resulting events; only errors are sent back to the client.
+func (c *Client) handleGameAction(msg map[string]interface{}, actionType models.ActionType) {
+	requestID, _ := msg["requestId"].(string)
+	action := models.GameAction{
+		Type:      actionType,
+		PlayerID:  c.playerID,
+		GameID:    c.gameID,
+		Payload:   msg,
+		Timestamp: time.Now(),
+	}
+
+	if _, err := c.hub.gameManager.ProcessGameAction(action); err != nil {
+		c.hub.logger.Errorf("Failed to process %s for player %s in game %s: %v", actionType, c.playerID, c.gameID, err)
+		errorMsg := map[string]interface{}{
+			"type":      "error",
+			"message":   fmt.Sprintf("Failed to process %s: %v", actionType, err),
+			"requestId": requestID,
+		}
+		errorJSON, _ := json.Marshal(errorMsg)
+		c.hub.SendToPlayerWithPriority(c.gameID, c.playerID, errorJSON, PriorityHigh)
+		return
+	}
+
+	c.hub.updateGameInfoCache(c.gameID)
+}
+
 // readPump pumps messages from the WebSocket connection to the hub
 func (c *Client) readPump() {
 	defer func() {
@@ -1205,6 +1261,18 @@ func (c *Client) handleMessage(message []byte) {
 				c.hub.logger.Warnf("Game %s started but game info not found in cache", c.gameID)
 			}
 		}
+	case "add_bot":
+		// The host fills an empty lobby seat with an AI opponent
+		difficulty, _ := msg["difficulty"].(string)
+		if _, err := c.hub.gameManager.AddBot(c.gameID, c.playerID, models.BotDifficulty(difficulty)); err != nil {
+			c.hub.logger.Warnf("Failed to add bot to game %s requested by %s: %v", c.gameID, c.playerID, err)
+			errorMsg := map[string]interface{}{
+				"type":    "error",
+				"message": fmt.Sprintf("Failed to add bot: %v", err),
+			}
+			errorJSON, _ := json.Marshal(errorMsg)
+			c.hub.SendToPlayerWithPriority(c.gameID, c.playerID, errorJSON, PriorityHigh)
+		}
 	case "player_joined":
 		// Sent by a client when they join the game
 		// Payload should include player details like name, token, etc.
@@ -1253,41 +1321,9 @@ func (c *Client) handleMessage(message []byte) {
 			c.hub.logger.Infof("Dice roll request ID: %s from player %s", requestID, c.playerID)
 		}
 
-		// First, check if it's this player's turn by getting the current game state
-		game, err := c.hub.gameManager.GetGame(c.gameID)
-		if err != nil {
-			c.hub.logger.Errorf("Failed to get game state: %v", err)
-			errorMsg := map[string]interface{}{
-				"type":      "error",
-				"message":   fmt.Sprintf("Failed to get game state: %v", err),
-				"requestId": requestID,
-			}
-			errorJSON, _ := json.Marshal(errorMsg)
-			c.hub.SendToPlayerWithPriority(c.gameID, c.playerID, errorJSON, PriorityHigh)
-			return
-		}
-
-		// Check if it's this player's turn
-		if game.CurrentTurn != c.playerID {
-			c.hub.logger.Errorf("Not player's turn. Current turn: %s, Player: %s", game.CurrentTurn, c.playerID)
-			errorMsg := map[string]interface{}{
-				"type":        "error",
-				"message":     "Not your turn",
-				"currentTurn": game.CurrentTurn,
-				"requestId":   requestID,
-			}
-			errorJSON, _ := json.Marshal(errorMsg)
-			c.hub.SendToPlayerWithPriority(c.gameID, c.playerID, errorJSON, PriorityHigh)
-			return
-		}
-
-		// Update the game info cache with the current turn information
-		c.hub.updateGameInfoCache(c.gameID)
-
 		// Create a roll dice action with the request ID in the payload
 		payload := map[string]interface{}{
 			"requestId": requestID,
-			"timestamp": time.Now().UnixNano(),
 		}
 
 		action := models.GameAction{
@@ -1298,138 +1334,45 @@ func (c *Client) handleMessage(message []byte) {
 			Timestamp: time.Now(),
 		}
 
-		// Process the action through the game manager
-		err = c.hub.gameManager.ProcessGameAction(action)
+		// The game manager rolls the dice, moves the player, records the roll as the
+		// game's LastRoll and broadcasts dice_rolled to every player in the game
+		result, err := c.hub.gameManager.ProcessGameAction(action)
 		if err != nil {
 			c.hub.logger.Errorf("Failed to process dice roll: %v", err)
 			// Send error message back to the client
 			errorMsg := map[string]interface{}{
-				"type":    "error",
-				"message": fmt.Sprintf("Failed to roll dice: %v", err),
+				"type":      "error",
+				"message":   fmt.Sprintf("Failed to roll dice: %v", err),
+				"requestId": requestID,
 			}
 			errorJSON, _ := json.Marshal(errorMsg)
 			c.hub.SendToPlayerWithPriority(c.gameID, c.playerID, errorJSON, PriorityHigh)
 			return
 		}
 
-		// Get the updated game state
-		game, err = c.hub.gameManager.GetGame(c.gameID)
-		if err != nil {
-			c.hub.logger.Errorf("Failed to get updated game state: %v", err)
-			return
-		}
-
-		// Find the current player in the game
-		var currentPlayer *models.Player
-		for i := range game.Players {
-			if game.Players[i].ID == c.playerID {
-				currentPlayer = &game.Players[i]
-				break
-			}
-		}
-
-		if currentPlayer == nil {
-			c.hub.logger.Errorf("Player %s not found in game %s", c.playerID, c.gameID)
-			return
-		}
-
-		// Extract the actual dice values from the game manager logs
-		// We need to parse the log message to get the dice values
-		// The log message format is: "Player %s rolled %d and %d, now at position %d"
-
-		// Get the most recent log entries for this game and player
-		// For now, we'll use the position from the player object and calculate the dice values
-		// based on the old position and new position
-
-		// We don't need to calculate old and new positions here
-		// The dice values will be retrieved from Redis
-
-		// Calculate the dice values based on the position change
-		// This is a simplified approach - in a real implementation, we would store the dice values
-		// in the game state or pass them from the game manager to the hub
-
-		// For now, we'll use a deterministic approach based on the player's position
-		// This ensures the frontend and backend show the same dice values
-
-		// Get the dice values from the game manager
-		// The game manager logs: "Player %s rolled %d and %d, now at position %d"
-		// We'll extract these values from the logs
-
-		// For now, we'll use a simple approach to get the dice values
-		// We'll modify the game manager to store the dice values in a temporary Redis key
-		// that we can retrieve here
-
-		// Try to get the dice values from Redis
-		diceKey := fmt.Sprintf("game:%s:player:%s:lastdice", c.gameID, c.playerID)
-		diceValues, err := c.hub.redisClient.Get(c.hub.ctx, diceKey).Result()
-
-		var dice1, dice2 int
-
-		if err == nil && diceValues != "" {
-			// Parse the dice values from Redis
-			parts := strings.Split(diceValues, ",")
-			if len(parts) == 2 {
-				dice1Val, err1 := strconv.Atoi(parts[0])
-				dice2Val, err2 := strconv.Atoi(parts[1])
-
-				if err1 == nil && err2 == nil {
-					dice1 = dice1Val
-					dice2 = dice2Val
-					c.hub.logger.Infof("Retrieved dice values from Redis for player %s: %d and %d", c.playerID, dice1, dice2)
-				} else {
-					// Fallback to random dice values
-					dice1 = 1 + diceRand.Intn(6)
-					dice2 = 1 + diceRand.Intn(6)
-					c.hub.logger.Infof("Failed to parse dice values from Redis, using random values for player %s: %d and %d", c.playerID, dice1, dice2)
-				}
-			} else {
-				// Fallback to random dice values
-				dice1 = 1 + diceRand.Intn(6)
-				dice2 = 1 + diceRand.Intn(6)
-				c.hub.logger.Infof("Invalid dice values format in Redis, using random values for player %s: %d and %d", c.playerID, dice1, dice2)
-			}
-		} else {
-			// Fallback to random dice values
-			dice1 = 1 + diceRand.Intn(6)
-			dice2 = 1 + diceRand.Intn(6)
-			c.hub.logger.Infof("No dice values found in Redis, using random values for player %s: %d and %d", c.playerID, dice1, dice2)
-		}
-
-		// Extract the request ID from the action payload if available
-		var diceRequestID string
-		if action.Payload != nil {
-			if payload, ok := action.Payload.(map[string]interface{}); ok {
-				if reqID, ok := payload["requestId"].(string); ok {
-					diceRequestID = reqID
-				}
-			}
-		}
-
-		// Create a response message with the dice roll result
-		response := map[string]interface{}{
-			"type":      "dice_rolled",
-			"playerId":  c.playerID,
-			"position":  currentPlayer.Position,
-			"balance":   currentPlayer.Balance,
-			"timestamp": time.Now().Format(time.RFC3339),
-			// Add dice values in both formats to ensure compatibility
-			"dice":  []int{dice1, dice2},
-			"dice1": dice1,
-			"dice2": dice2,
-			// Include the request ID if available
-			"requestId": diceRequestID,
-		}
-
-		// Marshal to JSON
-		responseJSON, err := json.Marshal(response)
-		if err != nil {
-			c.hub.logger.Errorf("Failed to marshal dice roll response: %v", err)
-			return
-		}
-
-		// Broadcast the result to all players in the game
-		c.hub.BroadcastToGame(c.gameID, responseJSON)
-		c.hub.logger.Infof("Broadcasted dice roll result for player %s in game %s", c.playerID, c.gameID)
+		// Update the game info cache with the new turn state
+		c.hub.updateGameInfoCache(c.gameID)
+		c.hub.logger.Infof("Processed dice roll for player %s in game %s (%d events)", c.playerID, c.gameID, len(result.Events))
+	case "pay_jail_fine":
+		c.handleGameAction(msg, models.ActionTypePayJailFine)
+	case "use_jail_card":
+		c.handleGameAction(msg, models.ActionTypeUseJailCard)
+	case "draw_card":
+		c.handleGameAction(msg, models.ActionTypeDrawCard)
+	case "use_card":
+		c.handleGameAction(msg, models.ActionTypeUseCard)
+	case "cast_vote":
+		c.handleGameAction(msg, models.ActionTypeCastVote)
+	case "decline_property":
+		c.handleGameAction(msg, models.ActionTypeDeclineProperty)
+	case "place_bid":
+		c.handleGameAction(msg, models.ActionTypePlaceBid)
+	case "declare_bankruptcy":
+		c.handleGameAction(msg, models.ActionTypeDeclareBankruptcy)
+	case "special_action":
+		c.handleGameAction(msg, models.ActionTypeSpecial)
+	case "propose_trade", "respond_trade":
+		c.handleGameAction(msg, models.ActionTypeTrade)
 	case "update_player_info", "update_player", "set_player_token":
 		// Extract player info from the message
 		playerId, ok := msg["playerId"].(string)
@@ -1787,6 +1730,9 @@ func (h *Hub) Run() {
 			playerInfo["connectedAt"] = time.Now().Format(time.RFC3339)
 			h.storePlayerInfo(client.gameID, client.playerID, playerInfo)
 
+			// A player coming back to a running game takes their seat back from the bot
+			go h.handlePlayerReconnected(client.gameID, client.playerID, client.sessionID)
+
 			// Trigger an active players update to notify everyone
 			go client.handleGetActivePlayers()
 